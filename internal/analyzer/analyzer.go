@@ -0,0 +1,248 @@
+// Package analyzer runs static analyzers on a checkout of a merge request, so their
+// findings on changed lines can be reported next to the LLM's and given to it as context.
+package analyzer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/handsoff/handsoff/pkg/config"
+)
+
+// Analyzer names, as configured per repository
+const (
+	GoVet        = "go_vet"
+	GolangciLint = "golangci_lint"
+	Semgrep      = "semgrep"
+)
+
+// Names lists the supported analyzers
+var Names = []string{GoVet, GolangciLint, Semgrep}
+
+// Finding categories: security issues count towards security gates and labels
+const (
+	CategorySecurity = "security"
+	CategoryStatic   = "static-analysis"
+)
+
+// Finding is one issue reported by an analyzer
+type Finding struct {
+	Analyzer string // See Names
+	Rule     string // Linter or rule ID ("" for go vet)
+	FilePath string // Relative to the repository root
+	Line     int
+	Severity string // high, medium, low
+	Category string // CategorySecurity or CategoryStatic
+	Message  string
+}
+
+// ErrHeadMoved is returned when the checked out ref is no longer at the expected commit
+var ErrHeadMoved = errors.New("ref moved to another commit")
+
+// Runner checks out merge requests and runs analyzers on them
+type Runner struct {
+	workDir      string
+	cloneTimeout time.Duration
+	timeout      time.Duration // Per analyzer
+	semgrepRules string
+}
+
+// NewRunner creates a runner that checks out into the Git temp directory
+func NewRunner(git config.GitConfig, cfg config.AnalyzerConfig) *Runner {
+	return &Runner{
+		workDir:      git.TempDir,
+		cloneTimeout: git.CloneTimeout,
+		timeout:      cfg.Timeout,
+		semgrepRules: cfg.SemgrepRules,
+	}
+}
+
+// Analyze makes a shallow checkout of ref from cloneURL and runs the named analyzers
+// on it. If ref no longer points at sha (when given), nothing runs and ErrHeadMoved
+// is returned. Analyzers that fail are reported in the error; the findings of the
+// others are still returned.
+func (r *Runner) Analyze(ctx context.Context, cloneURL, token, ref, sha string, names []string) ([]Finding, error) {
+	if err := os.MkdirAll(r.workDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create work directory: %w", err)
+	}
+	base, err := os.MkdirTemp(r.workDir, "analyze-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checkout directory: %w", err)
+	}
+	defer os.RemoveAll(base)
+	if base, err = filepath.Abs(base); err != nil {
+		return nil, err
+	}
+
+	// The checkout, and next to it the home and Go directories the tools may write to
+	dir := filepath.Join(base, "src")
+	for _, d := range []string{dir, filepath.Join(base, "home")} {
+		if err := os.Mkdir(d, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create checkout directory: %w", err)
+		}
+	}
+
+	head, err := r.checkout(ctx, dir, cloneURL, token, ref, base)
+	if err != nil {
+		return nil, err
+	}
+	if sha != "" && head != sha {
+		return nil, fmt.Errorf("%w: %s is at %s, not %s", ErrHeadMoved, ref, head, sha)
+	}
+
+	env := toolEnv(base)
+	var findings []Finding
+	var errs []error
+	for _, name := range names {
+		found, err := r.run(ctx, dir, name, env)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		findings = append(findings, found...)
+	}
+	return findings, errors.Join(errs...)
+}
+
+// run runs one analyzer in the checkout. Go analyzers are skipped without a go.mod
+// at the repository root.
+func (r *Runner) run(ctx context.Context, dir, name string, env []string) ([]Finding, error) {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	switch name {
+	case GoVet:
+		if !isGoModule(dir) {
+			return nil, nil
+		}
+		// Findings go to stderr with exit status 1
+		_, stderr, err := command(ctx, dir, env, "go", "vet", "./...")
+		findings := parseGoVet(stderr, dir)
+		if err != nil && len(findings) == 0 {
+			return nil, commandError(err, stderr)
+		}
+		return findings, nil
+
+	case GolangciLint:
+		if !isGoModule(dir) {
+			return nil, nil
+		}
+		// --no-config: the merge request could otherwise load linter plugins through its own config
+		stdout, stderr, err := command(ctx, dir, env, "golangci-lint", "run", "--no-config", "--issues-exit-code=0",
+			"--output.json.path=stdout", "--show-stats=false", "./...")
+		if err != nil {
+			return nil, commandError(err, stderr)
+		}
+		return parseGolangciLint(stdout, dir)
+
+	case Semgrep:
+		// Only the worker's local rules: the merge request must not be able to switch rules off
+		if r.semgrepRules == "" {
+			return nil, errors.New("no local rules configured (SEMGREP_RULES)")
+		}
+		stdout, stderr, err := command(ctx, dir, env, "semgrep", "scan", "--config", r.semgrepRules,
+			"--json", "--quiet", "--metrics=off", "--disable-version-check", ".")
+		if err != nil {
+			return nil, commandError(err, stderr)
+		}
+		return parseSemgrep(stdout, dir)
+
+	default:
+		return nil, fmt.Errorf("unknown analyzer")
+	}
+}
+
+// passthroughEnv are the worker's environment variables the tools may see: the
+// search path, and what they need to reach the network and module proxies. Anything
+// else (secrets, database and Redis credentials) stays out of reach of the MR's code.
+var passthroughEnv = []string{
+	"PATH",
+	"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "http_proxy", "https_proxy", "no_proxy",
+	"SSL_CERT_FILE", "SSL_CERT_DIR",
+	"GOPROXY", "GOPRIVATE", "GONOPROXY", "GONOSUMDB", "GOSUMDB",
+}
+
+// baseEnv is the minimal environment of a command run on the checkout, with a home
+// directory under base
+func baseEnv(base string) []string {
+	env := []string{"HOME=" + filepath.Join(base, "home")}
+	for _, key := range passthroughEnv {
+		if value, ok := os.LookupEnv(key); ok {
+			env = append(env, key+"="+value)
+		}
+	}
+	return env
+}
+
+// toolEnv is the environment of the analyzers. Go builds nothing with cgo, never
+// changes go.mod and never downloads a toolchain the checkout asks for; its caches
+// live under base and are removed with it.
+func toolEnv(base string) []string {
+	return append(baseEnv(base),
+		"GOPATH="+filepath.Join(base, "go"),
+		"GOCACHE="+filepath.Join(base, "go-build"),
+		"GOTOOLCHAIN=local",
+		"CGO_ENABLED=0",
+		"GOFLAGS=-mod=readonly",
+	)
+}
+
+// command runs a program in dir with the given environment
+func command(ctx context.Context, dir string, env []string, name string, args ...string) ([]byte, []byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	cmd.Env = env
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+// commandError adds the end of a failed command's output to its error
+func commandError(err error, output []byte) error {
+	const maxOutput = 500
+	output = bytes.TrimSpace(output)
+	if len(output) > maxOutput {
+		output = output[len(output)-maxOutput:]
+	}
+	if len(output) == 0 {
+		return err
+	}
+	return fmt.Errorf("%w: %s", err, output)
+}
+
+func isGoModule(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "go.mod"))
+	return err == nil
+}
+
+// OnLines keeps the findings on the given lines of each file, e.g. the lines a merge
+// request adds. Findings reported twice (go vet also runs inside golangci-lint) are kept once.
+func OnLines(findings []Finding, lines map[string]map[int]bool) []Finding {
+	type key struct {
+		file    string
+		line    int
+		message string
+	}
+	seen := map[key]bool{}
+	var kept []Finding
+	for _, f := range findings {
+		k := key{f.FilePath, f.Line, f.Message}
+		if !lines[f.FilePath][f.Line] || seen[k] {
+			continue
+		}
+		seen[k] = true
+		kept = append(kept, f)
+	}
+	return kept
+}
@@ -0,0 +1,164 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/handsoff/handsoff/pkg/config"
+)
+
+func TestParseGoVet(t *testing.T) {
+	output := "# example.com/app\n" +
+		"./main.go:7:2: fmt.Printf format %d has arg s of wrong type string\n" +
+		"/work/repo/pkg/db.go:12: unreachable code\n" +
+		"vet: some other problem\n"
+
+	got := parseGoVet([]byte(output), "/work/repo")
+	want := []Finding{
+		{Analyzer: GoVet, FilePath: "main.go", Line: 7, Severity: "medium", Category: CategoryStatic,
+			Message: "fmt.Printf format %d has arg s of wrong type string"},
+		{Analyzer: GoVet, FilePath: "pkg/db.go", Line: 12, Severity: "medium", Category: CategoryStatic,
+			Message: "unreachable code"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseGoVet() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseGolangciLint(t *testing.T) {
+	output := `{"Issues":[
+		{"FromLinter":"errcheck","Text":"Error return value is not checked","Pos":{"Filename":"api/user.go","Line":31,"Column":9}},
+		{"FromLinter":"gosec","Text":"G204: Subprocess launched with variable","Pos":{"Filename":"run.go","Line":5}}
+	],"Report":{}}`
+
+	got, err := parseGolangciLint([]byte(output), "/work/repo")
+	if err != nil {
+		t.Fatalf("parseGolangciLint() error = %v", err)
+	}
+	want := []Finding{
+		{Analyzer: GolangciLint, Rule: "errcheck", FilePath: "api/user.go", Line: 31, Severity: "medium",
+			Category: CategoryStatic, Message: "Error return value is not checked"},
+		{Analyzer: GolangciLint, Rule: "gosec", FilePath: "run.go", Line: 5, Severity: "high",
+			Category: CategorySecurity, Message: "G204: Subprocess launched with variable"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseGolangciLint() = %+v, want %+v", got, want)
+	}
+
+	if _, err := parseGolangciLint([]byte("level=error msg=..."), "/work/repo"); err == nil {
+		t.Error("parseGolangciLint() of non-JSON output should fail")
+	}
+}
+
+func TestParseSemgrep(t *testing.T) {
+	output := `{"results":[
+		{"check_id":"rules.sql-concat","path":"db/query.py","start":{"line":14,"col":5},
+		 "extra":{"message":"SQL built by string concatenation ","severity":"ERROR","metadata":{"category":"security"}}},
+		{"check_id":"rules.todo","path":"app.py","start":{"line":2},"extra":{"message":"TODO left","severity":"INFO"}}
+	],"errors":[]}`
+
+	got, err := parseSemgrep([]byte(output), "/work/repo")
+	if err != nil {
+		t.Fatalf("parseSemgrep() error = %v", err)
+	}
+	want := []Finding{
+		{Analyzer: Semgrep, Rule: "rules.sql-concat", FilePath: "db/query.py", Line: 14, Severity: "high",
+			Category: CategorySecurity, Message: "SQL built by string concatenation"},
+		{Analyzer: Semgrep, Rule: "rules.todo", FilePath: "app.py", Line: 2, Severity: "low",
+			Category: CategoryStatic, Message: "TODO left"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSemgrep() = %+v, want %+v", got, want)
+	}
+}
+
+func TestOnLines(t *testing.T) {
+	findings := []Finding{
+		{Analyzer: GoVet, FilePath: "main.go", Line: 7, Message: "bad format"},
+		{Analyzer: GolangciLint, Rule: "govet", FilePath: "main.go", Line: 7, Message: "bad format"}, // Same finding
+		{Analyzer: GoVet, FilePath: "main.go", Line: 8, Message: "not changed"},
+		{Analyzer: GoVet, FilePath: "other.go", Line: 7, Message: "file not changed"},
+	}
+	lines := map[string]map[int]bool{"main.go": {7: true}}
+
+	got := OnLines(findings, lines)
+	if len(got) != 1 || got[0].Analyzer != GoVet || got[0].Line != 7 {
+		t.Errorf("OnLines() = %+v, want the go vet finding on main.go:7", got)
+	}
+}
+
+func TestToolEnv(t *testing.T) {
+	t.Setenv("ENCRYPTION_KEY", "worker-secret")
+	t.Setenv("GOPROXY", "https://proxy.example.com")
+
+	env := strings.Join(append(toolEnv("/work/analyze-1"), gitEnv("/work/analyze-1", "glpat-token")...), "\n")
+	if strings.Contains(env, "worker-secret") || strings.Contains(env, "glpat-token") {
+		t.Errorf("environment leaks a secret:\n%s", env)
+	}
+	for _, want := range []string{"HOME=/work/analyze-1/home", "GOPROXY=https://proxy.example.com", "CGO_ENABLED=0", "GOFLAGS=-mod=readonly"} {
+		if !strings.Contains(env, want) {
+			t.Errorf("environment lacks %s", want)
+		}
+	}
+}
+
+func TestAnalyzeGoVet(t *testing.T) {
+	for _, tool := range []string{"git", "go"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s not installed", tool)
+		}
+	}
+
+	// A local repository stands in for the GitLab project
+	origin := t.TempDir()
+	files := map[string]string{
+		"go.mod":  "module example.com/app\n\ngo 1.21\n",
+		"main.go": "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Printf(\"%d\\n\", \"text\")\n}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(origin, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "feature"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = origin
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	runner := NewRunner(config.GitConfig{TempDir: t.TempDir()}, config.AnalyzerConfig{})
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = origin
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	head := strings.TrimSpace(string(out))
+
+	findings, err := runner.Analyze(context.Background(), origin, "", "feature", head, []string{GoVet})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if len(findings) != 1 || findings[0].FilePath != "main.go" || findings[0].Line != 6 {
+		t.Fatalf("Analyze() = %+v, want one go vet finding on main.go:6", findings)
+	}
+
+	if _, err := runner.Analyze(context.Background(), origin, "", "feature", "0123abcd", []string{GoVet}); !errors.Is(err, ErrHeadMoved) {
+		t.Errorf("Analyze() of a moved ref error = %v, want ErrHeadMoved", err)
+	}
+	if _, err := runner.Analyze(context.Background(), origin, "", "missing", "", []string{GoVet}); err == nil {
+		t.Error("Analyze() of a missing ref should fail")
+	}
+}
@@ -0,0 +1,59 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// checkout fetches ref with depth 1 into dir and checks it out. The ref may be a
+// branch or any ref the server advertises, e.g. refs/merge-requests/<iid>/head.
+// Git runs with a home directory under base. Returns the checked out commit.
+func (r *Runner) checkout(ctx context.Context, dir, cloneURL, token, ref, base string) (string, error) {
+	if r.cloneTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.cloneTimeout)
+		defer cancel()
+	}
+
+	env := gitEnv(base, token)
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"fetch", "-q", "--depth", "1", "--no-tags", cloneURL, ref},
+		{"checkout", "-q", "--detach", "FETCH_HEAD"},
+	} {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = dir
+		cmd.Env = env
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
+	cmd.Dir = dir
+	cmd.Env = env
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// gitEnv passes the access token as an HTTP header through the environment, so it
+// shows up neither in the clone URL (and git's error messages) nor in the process list.
+// Git never prompts for credentials, reads no system config and downloads no LFS objects.
+func gitEnv(base, token string) []string {
+	env := append(baseEnv(base), "GIT_TERMINAL_PROMPT=0", "GIT_CONFIG_NOSYSTEM=1", "GIT_LFS_SKIP_SMUDGE=1")
+	if token == "" {
+		return env
+	}
+	auth := base64.StdEncoding.EncodeToString([]byte("oauth2:" + token))
+	return append(env,
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=http.extraHeader",
+		"GIT_CONFIG_VALUE_0=Authorization: Basic "+auth,
+	)
+}
@@ -0,0 +1,142 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// goVetLine matches "path/file.go:12:5: message" (the column is optional)
+var goVetLine = regexp.MustCompile(`^(.+?\.go):(\d+)(?::\d+)?: (.+)$`)
+
+// parseGoVet reads the findings go vet prints to stderr; package headers ("# pkg")
+// and other output are skipped
+func parseGoVet(output []byte, dir string) []Finding {
+	var findings []Finding
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		m := goVetLine.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		line, _ := strconv.Atoi(m[2])
+		findings = append(findings, Finding{
+			Analyzer: GoVet,
+			FilePath: relativePath(dir, m[1]),
+			Line:     line,
+			Severity: "medium",
+			Category: CategoryStatic,
+			Message:  m[3],
+		})
+	}
+	return findings
+}
+
+// golangciLintReport is the part of golangci-lint's JSON output that is used
+type golangciLintReport struct {
+	Issues []struct {
+		FromLinter string
+		Text       string
+		Pos        struct {
+			Filename string
+			Line     int
+		}
+	}
+}
+
+// parseGolangciLint reads golangci-lint's JSON output. gosec findings are security issues.
+func parseGolangciLint(output []byte, dir string) ([]Finding, error) {
+	var report golangciLintReport
+	if err := json.Unmarshal(output, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse output: %w", err)
+	}
+
+	findings := make([]Finding, 0, len(report.Issues))
+	for _, issue := range report.Issues {
+		f := Finding{
+			Analyzer: GolangciLint,
+			Rule:     issue.FromLinter,
+			FilePath: relativePath(dir, issue.Pos.Filename),
+			Line:     issue.Pos.Line,
+			Severity: "medium",
+			Category: CategoryStatic,
+			Message:  issue.Text,
+		}
+		if issue.FromLinter == "gosec" {
+			f.Severity, f.Category = "high", CategorySecurity
+		}
+		findings = append(findings, f)
+	}
+	return findings, nil
+}
+
+// semgrepReport is the part of semgrep's JSON output that is used
+type semgrepReport struct {
+	Results []struct {
+		CheckID string `json:"check_id"`
+		Path    string `json:"path"`
+		Start   struct {
+			Line int `json:"line"`
+		} `json:"start"`
+		Extra struct {
+			Message  string `json:"message"`
+			Severity string `json:"severity"`
+			Metadata struct {
+				Category string `json:"category"`
+			} `json:"metadata"`
+		} `json:"extra"`
+	} `json:"results"`
+}
+
+// semgrepSeverities maps semgrep severities to suggestion severities
+var semgrepSeverities = map[string]string{
+	"ERROR":   "high",
+	"WARNING": "medium",
+	"INFO":    "low",
+}
+
+// parseSemgrep reads semgrep's JSON output. Rules with security metadata report
+// security issues.
+func parseSemgrep(output []byte, dir string) ([]Finding, error) {
+	var report semgrepReport
+	if err := json.Unmarshal(output, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse output: %w", err)
+	}
+
+	findings := make([]Finding, 0, len(report.Results))
+	for _, result := range report.Results {
+		severity := semgrepSeverities[strings.ToUpper(result.Extra.Severity)]
+		if severity == "" {
+			severity = "medium"
+		}
+		category := CategoryStatic
+		if strings.EqualFold(result.Extra.Metadata.Category, CategorySecurity) {
+			category = CategorySecurity
+		}
+		findings = append(findings, Finding{
+			Analyzer: Semgrep,
+			Rule:     result.CheckID,
+			FilePath: relativePath(dir, result.Path),
+			Line:     result.Start.Line,
+			Severity: severity,
+			Category: category,
+			Message:  strings.TrimSpace(result.Extra.Message),
+		})
+	}
+	return findings, nil
+}
+
+// relativePath turns an analyzer's file path into a repository path like the diff's
+func relativePath(dir, path string) string {
+	if filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(dir, path); err == nil {
+			path = rel
+		}
+	}
+	return strings.TrimPrefix(filepath.ToSlash(path), "./")
+}
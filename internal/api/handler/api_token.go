@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/service"
+	"github.com/handsoff/handsoff/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// APITokenHandler handles API token management requests
+type APITokenHandler struct {
+	service *service.APITokenService
+	log     *logger.Logger
+}
+
+// NewAPITokenHandler creates a new API token handler
+func NewAPITokenHandler(service *service.APITokenService, log *logger.Logger) *APITokenHandler {
+	return &APITokenHandler{
+		service: service,
+		log:     log,
+	}
+}
+
+// CreateAPITokenRequest represents create API token request payload
+type CreateAPITokenRequest struct {
+	Name      string             `json:"name" binding:"required"`
+	Type      model.APITokenType `json:"type"` // personal (default) or project
+	Scopes    []string           `json:"scopes" binding:"required"`
+	ExpiresAt *time.Time         `json:"expires_at"` // Optional, RFC3339
+}
+
+// CreateAPITokenResponse contains the raw token, which is only returned once
+type CreateAPITokenResponse struct {
+	Token    string          `json:"token"`
+	APIToken *model.APIToken `json:"api_token"`
+}
+
+// List returns API tokens of the current project
+// GET /api/tokens
+func (h *APITokenHandler) List(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return
+	}
+
+	tokens, err := h.service.List(projectID, c.GetUint("user_id"))
+	if err != nil {
+		h.log.Error("Failed to list API tokens", "error", err)
+		RespondInternalError(c, "Failed to list API tokens")
+		return
+	}
+
+	RespondSuccess(c, gin.H{"tokens": tokens})
+}
+
+// Create issues a new API token bound to the current project
+// POST /api/tokens
+func (h *APITokenHandler) Create(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return
+	}
+
+	var req CreateAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondBadRequest(c, ErrMsgInvalidRequest+": "+err.Error())
+		return
+	}
+
+	token, raw, err := h.service.Create(service.CreateAPITokenInput{
+		Name:      req.Name,
+		Type:      req.Type,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+		ProjectID: projectID,
+		UserID:    c.GetUint("user_id"),
+	})
+	if err != nil {
+		RespondBadRequest(c, err.Error())
+		return
+	}
+
+	h.log.Info("API token created",
+		"token_id", token.ID,
+		"project_id", projectID,
+		"type", token.Type,
+		"scopes", token.Scopes)
+
+	RespondCreated(c, CreateAPITokenResponse{
+		Token:    raw,
+		APIToken: token,
+	})
+}
+
+// Revoke revokes an API token
+// DELETE /api/tokens/:id
+func (h *APITokenHandler) Revoke(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid token ID")
+		return
+	}
+
+	if err := h.service.Revoke(uint(id), projectID, c.GetUint("user_id")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			RespondNotFound(c, "API token not found")
+			return
+		}
+		h.log.Error("Failed to revoke API token", "error", err)
+		RespondInternalError(c, "Failed to revoke API token")
+		return
+	}
+
+	h.log.Info("API token revoked", "token_id", id, "project_id", projectID)
+	c.JSON(http.StatusOK, gin.H{"message": "API token revoked"})
+}
@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/service"
 	"github.com/handsoff/handsoff/pkg/config"
 	"github.com/handsoff/handsoff/pkg/jwt"
 	"github.com/handsoff/handsoff/pkg/logger"
@@ -13,19 +15,23 @@ import (
 
 // AuthHandler handles authentication requests
 type AuthHandler struct {
-	db     *gorm.DB
-	cfg    *config.Config
-	log    *logger.Logger
-	jwtGen *jwt.Generator
+	db           *gorm.DB
+	cfg          *config.Config
+	log          *logger.Logger
+	tokenService *service.AuthTokenService
+	userService  *service.UserService
+	oidcService  *service.OIDCService
 }
 
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(db *gorm.DB, cfg *config.Config, log *logger.Logger) *AuthHandler {
+func NewAuthHandler(db *gorm.DB, cfg *config.Config, log *logger.Logger, tokenService *service.AuthTokenService, oidcService *service.OIDCService) *AuthHandler {
 	return &AuthHandler{
-		db:     db,
-		cfg:    cfg,
-		log:    log,
-		jwtGen: jwt.NewGenerator(cfg.Security.JWTSecret, cfg.Security.JWTExpiry),
+		db:           db,
+		cfg:          cfg,
+		log:          log,
+		tokenService: tokenService,
+		userService:  service.NewUserService(db),
+		oidcService:  oidcService,
 	}
 }
 
@@ -37,8 +43,26 @@ type LoginRequest struct {
 
 // LoginResponse represents login response
 type LoginResponse struct {
-	Token string      `json:"token"`
-	User  *model.User `json:"user"`
+	Token        string      `json:"token"`
+	RefreshToken string      `json:"refresh_token"`
+	ExpiresIn    int64       `json:"expires_in"` // Access token lifetime in seconds
+	User         *model.User `json:"user"`
+}
+
+// RefreshRequest represents token refresh request payload
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest represents logout request payload (refresh token is optional)
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// ChangePasswordRequest represents change password request payload
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=8"`
 }
 
 // Login handles user login
@@ -67,14 +91,20 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	// SSO accounts have no usable local password
+	if user.AuthProvider == model.AuthProviderOIDC {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "This account uses single sign-on"})
+		return
+	}
+
 	// Verify password
 	if !user.CheckPassword(req.Password) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
 		return
 	}
 
-	// Generate JWT token
-	token, err := h.jwtGen.Generate(user.ID, user.Username)
+	// Generate access + refresh tokens
+	pair, err := h.tokenService.IssueTokenPair(&user, clientMeta(c))
 	if err != nil {
 		h.log.Error("Failed to generate token", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
@@ -83,22 +113,130 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	h.log.Info("User logged in", "user_id", user.ID, "username", user.Username)
 
-	c.JSON(http.StatusOK, LoginResponse{
-		Token: token,
-		User:  &user,
-	})
+	c.JSON(http.StatusOK, newLoginResponse(pair, &user))
 }
 
-// Logout handles user logout (stateless, just for logging)
+// Refresh exchanges a refresh token for a new token pair.
+// The presented refresh token is rotated and cannot be used again.
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	pair, user, err := h.tokenService.Refresh(req.RefreshToken, clientMeta(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrRefreshTokenReused):
+			h.log.Warn("Refresh token reuse detected, session revoked", "client_ip", c.ClientIP())
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token has already been used"})
+		case errors.Is(err, service.ErrInvalidToken), errors.Is(err, service.ErrTokenRevoked):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		case errors.Is(err, service.ErrUserInactive):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User account is disabled"})
+		default:
+			h.log.Error("Failed to refresh token", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh token"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, newLoginResponse(pair, user))
+}
+
+// Logout revokes the current access token and, if provided, the refresh token
 func (h *AuthHandler) Logout(c *gin.Context) {
 	userID, _ := c.Get("user_id")
 	username, _ := c.Get("username")
 
+	// Body is optional, ignore bind errors
+	var req LogoutRequest
+	_ = c.ShouldBindJSON(&req)
+
+	if claims, ok := c.Get("token_claims"); ok {
+		if err := h.tokenService.RevokeAccessToken(claims.(*jwt.Claims), service.RevokeReasonLogout); err != nil {
+			h.log.Error("Failed to revoke access token", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to logout"})
+			return
+		}
+	}
+
+	if req.RefreshToken != "" {
+		if err := h.tokenService.RevokeRefreshToken(req.RefreshToken); err != nil {
+			h.log.Error("Failed to revoke refresh token", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to logout"})
+			return
+		}
+	}
+
 	h.log.Info("User logged out", "user_id", userID, "username", username)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
 }
 
+// ChangePassword updates the current user's password.
+// All existing sessions are revoked and a fresh token pair is returned.
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	if err := h.userService.ChangePassword(userID.(uint), req.CurrentPassword, req.NewPassword); err != nil {
+		if errors.Is(err, service.ErrInvalidPassword) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Current password is incorrect"})
+			return
+		}
+		h.log.Error("Failed to change password", "error", err, "user_id", userID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to change password"})
+		return
+	}
+
+	user, err := h.userService.GetUser(userID.(uint))
+	if err != nil {
+		h.log.Error("Failed to query user", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	pair, err := h.tokenService.IssueTokenPair(user, clientMeta(c))
+	if err != nil {
+		h.log.Error("Failed to generate token", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	h.log.Info("User changed password, sessions revoked", "user_id", user.ID, "username", user.Username)
+
+	c.JSON(http.StatusOK, newLoginResponse(pair, user))
+}
+
+// newLoginResponse builds the response returned by login, refresh and password change
+func newLoginResponse(pair *service.TokenPair, user *model.User) LoginResponse {
+	return LoginResponse{
+		Token:        pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresIn:    pair.ExpiresIn,
+		User:         user,
+	}
+}
+
+// clientMeta extracts client information recorded with refresh tokens
+func clientMeta(c *gin.Context) service.ClientMeta {
+	return service.ClientMeta{
+		UserAgent: c.Request.UserAgent(),
+		ClientIP:  c.ClientIP(),
+	}
+}
+
 // GetCurrentUser returns current authenticated user info
 func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 	userID, exists := c.Get("user_id")
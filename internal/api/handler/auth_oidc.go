@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/handsoff/handsoff/internal/service"
+)
+
+// oidcCookieName holds state, nonce and PKCE verifier between login and callback
+const oidcCookieName = "handsoff_oidc"
+
+// oidcCookieMaxAge limits how long a login attempt may take (seconds)
+const oidcCookieMaxAge = 600
+
+// GetOIDCConfig tells the frontend whether SSO login is available
+// GET /api/auth/oidc/config
+func (h *AuthHandler) GetOIDCConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"enabled": h.oidcService.Enabled()})
+}
+
+// OIDCLogin starts the authorization-code flow with PKCE and redirects to the provider
+// GET /api/auth/oidc/login
+func (h *AuthHandler) OIDCLogin(c *gin.Context) {
+	loginReq, err := h.oidcService.BeginLogin(c.Request.Context())
+	if err != nil {
+		if errors.Is(err, service.ErrOIDCDisabled) {
+			RespondNotFound(c, "Single sign-on is not enabled")
+			return
+		}
+		h.log.Error("Failed to start OIDC login", "error", err)
+		RespondInternalError(c, "Failed to start single sign-on")
+		return
+	}
+
+	value := strings.Join([]string{loginReq.State, loginReq.Nonce, loginReq.Verifier}, ".")
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcCookieName, value, oidcCookieMaxAge, "/api/auth/oidc", "", c.Request.TLS != nil, true)
+
+	c.Redirect(http.StatusFound, loginReq.URL)
+}
+
+// OIDCCallback completes the login and redirects to the frontend with the
+// issued tokens in the URL fragment (fragments are not sent to servers)
+// GET /api/auth/oidc/callback
+func (h *AuthHandler) OIDCCallback(c *gin.Context) {
+	// Login attempts are single-use
+	c.SetCookie(oidcCookieName, "", -1, "/api/auth/oidc", "", c.Request.TLS != nil, true)
+
+	if errParam := c.Query("error"); errParam != "" {
+		h.log.Warn("OIDC provider returned an error", "error", errParam, "description", c.Query("error_description"))
+		h.redirectOIDCResult(c, url.Values{"error": {errParam}})
+		return
+	}
+
+	cookie, err := c.Cookie(oidcCookieName)
+	parts := strings.Split(cookie, ".")
+	if err != nil || len(parts) != 3 {
+		h.redirectOIDCResult(c, url.Values{"error": {"login_expired"}})
+		return
+	}
+	state, nonce, verifier := parts[0], parts[1], parts[2]
+
+	if c.Query("state") != state {
+		h.log.Warn("OIDC state mismatch", "client_ip", c.ClientIP())
+		h.redirectOIDCResult(c, url.Values{"error": {"invalid_state"}})
+		return
+	}
+
+	pair, user, err := h.oidcService.CompleteLogin(c.Request.Context(), c.Query("code"), verifier, nonce, clientMeta(c))
+	if err != nil {
+		h.log.Error("OIDC login failed", "error", err)
+		h.redirectOIDCResult(c, url.Values{"error": {"login_failed"}})
+		return
+	}
+
+	h.log.Info("User logged in via OIDC", "user_id", user.ID, "username", user.Username)
+
+	h.redirectOIDCResult(c, url.Values{
+		"token":         {pair.AccessToken},
+		"refresh_token": {pair.RefreshToken},
+		"expires_in":    {strconv.FormatInt(pair.ExpiresIn, 10)},
+	})
+}
+
+// redirectOIDCResult sends the browser back to the frontend
+func (h *AuthHandler) redirectOIDCResult(c *gin.Context, values url.Values) {
+	c.Redirect(http.StatusFound, h.oidcService.PostLoginRedirect()+"#"+values.Encode())
+}
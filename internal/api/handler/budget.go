@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/service"
+	"github.com/handsoff/handsoff/pkg/logger"
+)
+
+// BudgetHandler handles budget management requests
+type BudgetHandler struct {
+	service *service.BudgetService
+	log     *logger.Logger
+}
+
+// NewBudgetHandler creates a new budget handler
+func NewBudgetHandler(service *service.BudgetService, log *logger.Logger) *BudgetHandler {
+	return &BudgetHandler{
+		service: service,
+		log:     log,
+	}
+}
+
+// BudgetRequest represents create/update budget request payload
+type BudgetRequest struct {
+	RepositoryID *uint              `json:"repository_id"` // Omit for a project-wide budget
+	Metric       model.BudgetMetric `json:"metric" binding:"required"`
+	SoftLimit    float64            `json:"soft_limit"`
+	HardLimit    float64            `json:"hard_limit"`
+	Currency     string             `json:"currency"`
+	IsActive     *bool              `json:"is_active"` // Defaults to true
+}
+
+func (r BudgetRequest) input() service.BudgetInput {
+	isActive := true
+	if r.IsActive != nil {
+		isActive = *r.IsActive
+	}
+	return service.BudgetInput{
+		RepositoryID: r.RepositoryID,
+		Metric:       r.Metric,
+		SoftLimit:    r.SoftLimit,
+		HardLimit:    r.HardLimit,
+		Currency:     r.Currency,
+		IsActive:     isActive,
+	}
+}
+
+// List returns the budgets of the current project with their usage this month
+// GET /api/budgets
+func (h *BudgetHandler) List(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return
+	}
+
+	budgets, err := h.service.List(projectID)
+	if err != nil {
+		h.log.Error("Failed to list budgets", "error", err)
+		RespondInternalError(c, "Failed to list budgets")
+		return
+	}
+
+	statuses, err := h.service.ProjectStatus(projectID, time.Now())
+	if err != nil {
+		h.log.Error("Failed to get budget status", "error", err)
+		RespondInternalError(c, "Failed to get budget status")
+		return
+	}
+
+	RespondSuccess(c, gin.H{
+		"budgets": budgets,
+		"status":  statuses,
+	})
+}
+
+// Create adds a budget to the current project
+// POST /api/budgets
+func (h *BudgetHandler) Create(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return
+	}
+
+	var req BudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondBadRequest(c, ErrMsgInvalidRequest+": "+err.Error())
+		return
+	}
+
+	budget, err := h.service.Create(projectID, req.input())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.log.Info("Budget created",
+		"budget_id", budget.ID,
+		"project_id", projectID,
+		"metric", budget.Metric)
+
+	RespondCreated(c, budget)
+}
+
+// Update changes a budget of the current project
+// PUT /api/budgets/:id
+func (h *BudgetHandler) Update(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid budget ID")
+		return
+	}
+
+	var req BudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondBadRequest(c, ErrMsgInvalidRequest+": "+err.Error())
+		return
+	}
+
+	budget, err := h.service.Update(uint(id), projectID, req.input())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.log.Info("Budget updated", "budget_id", budget.ID, "project_id", projectID)
+	RespondSuccess(c, budget)
+}
+
+// Delete removes a budget of the current project
+// DELETE /api/budgets/:id
+func (h *BudgetHandler) Delete(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid budget ID")
+		return
+	}
+
+	if err := h.service.Delete(uint(id), projectID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.log.Info("Budget deleted", "budget_id", id, "project_id", projectID)
+	RespondSuccessWithMessage(c, "Budget deleted", nil)
+}
+
+// handleError maps budget service errors to responses
+func (h *BudgetHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidBudget):
+		RespondBadRequest(c, err.Error())
+	case errors.Is(err, service.ErrBudgetNotFound):
+		RespondNotFound(c, "Budget not found")
+	default:
+		h.log.Error("Budget operation failed", "error", err)
+		RespondInternalError(c, "Budget operation failed")
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -90,6 +91,8 @@ func (h *LLMHandler) CreateProvider(c *gin.Context) {
 		APIKey   string `json:"api_key" binding:"required"`
 		Model    string `json:"model" binding:"required"`
 		IsActive bool   `json:"is_active"`
+		RPMLimit int    `json:"rpm_limit"` // Requests per minute (0 = unlimited)
+		TPMLimit int    `json:"tpm_limit"` // Tokens per minute (0 = unlimited)
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -104,10 +107,16 @@ func (h *LLMHandler) CreateProvider(c *gin.Context) {
 		APIKey:    req.APIKey,
 		Model:     req.Model,
 		IsActive:  req.IsActive,
+		RPMLimit:  req.RPMLimit,
+		TPMLimit:  req.TPMLimit,
 		ProjectID: projectID,
 	}
 
 	if err := h.service.CreateProvider(&provider); err != nil {
+		if errors.Is(err, service.ErrInvalidRateLimit) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		h.log.Error("Failed to create provider", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create provider"})
 		return
@@ -139,6 +148,8 @@ func (h *LLMHandler) UpdateProvider(c *gin.Context) {
 		APIKey   string `json:"api_key"` // Optional: empty means keep existing
 		Model    string `json:"model"`
 		IsActive *bool  `json:"is_active"` // Pointer to distinguish between false and not provided
+		RPMLimit *int   `json:"rpm_limit"` // Optional: nil keeps the current limit
+		TPMLimit *int   `json:"tpm_limit"` // Optional: nil keeps the current limit
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -146,10 +157,18 @@ func (h *LLMHandler) UpdateProvider(c *gin.Context) {
 		return
 	}
 
+	current, err := h.service.GetProvider(uint(id), projectID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Provider not found"})
+		return
+	}
+
 	// Build update data - only include non-empty fields
 	provider := &model.LLMProvider{
 		ID:        uint(id),
 		ProjectID: projectID,
+		RPMLimit:  current.RPMLimit,
+		TPMLimit:  current.TPMLimit,
 	}
 
 	// Update fields if provided
@@ -168,8 +187,18 @@ func (h *LLMHandler) UpdateProvider(c *gin.Context) {
 	if req.IsActive != nil {
 		provider.IsActive = *req.IsActive
 	}
+	if req.RPMLimit != nil {
+		provider.RPMLimit = *req.RPMLimit
+	}
+	if req.TPMLimit != nil {
+		provider.TPMLimit = *req.TPMLimit
+	}
 
 	if err := h.service.UpdateProvider(provider); err != nil {
+		if errors.Is(err, service.ErrInvalidRateLimit) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		h.log.Error("Failed to update provider", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update provider"})
 		return
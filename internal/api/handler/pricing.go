@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/handsoff/handsoff/internal/service"
+	"github.com/handsoff/handsoff/pkg/logger"
+)
+
+// PricingHandler handles model pricing catalogue requests
+type PricingHandler struct {
+	service *service.PricingService
+	log     *logger.Logger
+}
+
+// NewPricingHandler creates a new pricing handler
+func NewPricingHandler(service *service.PricingService, log *logger.Logger) *PricingHandler {
+	return &PricingHandler{
+		service: service,
+		log:     log,
+	}
+}
+
+// PriceRequest represents create/update model price request payload
+type PriceRequest struct {
+	LLMProviderID   uint       `json:"llm_provider_id" binding:"required"`
+	ModelName       string     `json:"model_name" binding:"required"`
+	PromptPrice     float64    `json:"prompt_price"`     // Per million prompt tokens
+	CompletionPrice float64    `json:"completion_price"` // Per million completion tokens
+	Currency        string     `json:"currency"`         // Defaults to USD
+	EffectiveFrom   *time.Time `json:"effective_from"`   // Defaults to now
+	EffectiveTo     *time.Time `json:"effective_to"`     // Omit for open-ended
+}
+
+func (r PriceRequest) input() service.PriceInput {
+	effectiveFrom := time.Now()
+	if r.EffectiveFrom != nil {
+		effectiveFrom = *r.EffectiveFrom
+	}
+	return service.PriceInput{
+		LLMProviderID:   r.LLMProviderID,
+		ModelName:       r.ModelName,
+		PromptPrice:     r.PromptPrice,
+		CompletionPrice: r.CompletionPrice,
+		Currency:        r.Currency,
+		EffectiveFrom:   effectiveFrom,
+		EffectiveTo:     r.EffectiveTo,
+	}
+}
+
+// List returns the model prices of the current project
+// GET /api/llm/prices?llm_provider_id=1
+func (h *PricingHandler) List(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return
+	}
+
+	var providerID uint64
+	if v := c.Query("llm_provider_id"); v != "" {
+		var err error
+		if providerID, err = strconv.ParseUint(v, 10, 32); err != nil {
+			RespondBadRequest(c, "Invalid LLM provider ID")
+			return
+		}
+	}
+
+	prices, err := h.service.List(projectID, uint(providerID))
+	if err != nil {
+		h.log.Error("Failed to list model prices", "error", err)
+		RespondInternalError(c, "Failed to list model prices")
+		return
+	}
+
+	RespondSuccess(c, prices)
+}
+
+// Create adds a model price to the current project
+// POST /api/llm/prices
+func (h *PricingHandler) Create(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return
+	}
+
+	var req PriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondBadRequest(c, ErrMsgInvalidRequest+": "+err.Error())
+		return
+	}
+
+	price, err := h.service.Create(projectID, req.input())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.log.Info("Model price created",
+		"price_id", price.ID,
+		"llm_provider_id", price.LLMProviderID,
+		"model", price.ModelName)
+
+	RespondCreated(c, price)
+}
+
+// Update changes a model price of the current project
+// PUT /api/llm/prices/:id
+func (h *PricingHandler) Update(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid price ID")
+		return
+	}
+
+	var req PriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondBadRequest(c, ErrMsgInvalidRequest+": "+err.Error())
+		return
+	}
+
+	price, err := h.service.Update(uint(id), projectID, req.input())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.log.Info("Model price updated", "price_id", price.ID, "project_id", projectID)
+	RespondSuccess(c, price)
+}
+
+// Delete removes a model price of the current project
+// DELETE /api/llm/prices/:id
+func (h *PricingHandler) Delete(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid price ID")
+		return
+	}
+
+	if err := h.service.Delete(uint(id), projectID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.log.Info("Model price deleted", "price_id", id, "project_id", projectID)
+	RespondSuccessWithMessage(c, "Model price deleted", nil)
+}
+
+// handleError maps pricing service errors to responses
+func (h *PricingHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidPrice):
+		RespondBadRequest(c, err.Error())
+	case errors.Is(err, service.ErrPriceNotFound):
+		RespondNotFound(c, "Model price not found")
+	default:
+		h.log.Error("Model price operation failed", "error", err)
+		RespondInternalError(c, "Model price operation failed")
+	}
+}
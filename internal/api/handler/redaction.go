@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/handsoff/handsoff/internal/redact"
+	"github.com/handsoff/handsoff/internal/service"
+	"github.com/handsoff/handsoff/pkg/logger"
+)
+
+// RedactionHandler handles redaction rule requests
+type RedactionHandler struct {
+	service *service.RedactionService
+	log     *logger.Logger
+}
+
+// NewRedactionHandler creates a new redaction handler
+func NewRedactionHandler(service *service.RedactionService, log *logger.Logger) *RedactionHandler {
+	return &RedactionHandler{
+		service: service,
+		log:     log,
+	}
+}
+
+// RedactionRuleRequest represents create/update redaction rule request payload
+type RedactionRuleRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Pattern     string `json:"pattern" binding:"required"`
+	Description string `json:"description"`
+	IsActive    *bool  `json:"is_active"` // Defaults to true
+}
+
+func (r RedactionRuleRequest) input() service.RedactionRuleInput {
+	isActive := true
+	if r.IsActive != nil {
+		isActive = *r.IsActive
+	}
+	return service.RedactionRuleInput{
+		Name:        r.Name,
+		Pattern:     r.Pattern,
+		Description: r.Description,
+		IsActive:    isActive,
+	}
+}
+
+// List returns the project's redaction rules and the names of the builtin ones
+// GET /api/redaction-rules
+func (h *RedactionHandler) List(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return
+	}
+
+	rules, err := h.service.List(projectID)
+	if err != nil {
+		h.log.Error("Failed to list redaction rules", "error", err)
+		RespondInternalError(c, "Failed to list redaction rules")
+		return
+	}
+
+	builtin := []gin.H{{"name": redact.PrivateKeyRule, "label": "private key"}}
+	for _, rule := range redact.BuiltinRules() {
+		builtin = append(builtin, gin.H{"name": rule.Name, "label": rule.Label})
+	}
+
+	RespondSuccess(c, gin.H{
+		"rules":   rules,
+		"builtin": builtin,
+	})
+}
+
+// Create adds a redaction rule to the current project
+// POST /api/redaction-rules
+func (h *RedactionHandler) Create(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return
+	}
+
+	var req RedactionRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondBadRequest(c, ErrMsgInvalidRequest+": "+err.Error())
+		return
+	}
+
+	rule, err := h.service.Create(projectID, req.input())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.log.Info("Redaction rule created", "rule_id", rule.ID, "project_id", projectID, "name", rule.Name)
+	RespondCreated(c, rule)
+}
+
+// Update changes a redaction rule of the current project
+// PUT /api/redaction-rules/:id
+func (h *RedactionHandler) Update(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid redaction rule ID")
+		return
+	}
+
+	var req RedactionRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondBadRequest(c, ErrMsgInvalidRequest+": "+err.Error())
+		return
+	}
+
+	rule, err := h.service.Update(uint(id), projectID, req.input())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.log.Info("Redaction rule updated", "rule_id", rule.ID, "project_id", projectID)
+	RespondSuccess(c, rule)
+}
+
+// Delete removes a redaction rule of the current project
+// DELETE /api/redaction-rules/:id
+func (h *RedactionHandler) Delete(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid redaction rule ID")
+		return
+	}
+
+	if err := h.service.Delete(uint(id), projectID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.log.Info("Redaction rule deleted", "rule_id", id, "project_id", projectID)
+	RespondSuccessWithMessage(c, "Redaction rule deleted", nil)
+}
+
+// handleError maps redaction service errors to responses
+func (h *RedactionHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidRedactionRule):
+		RespondBadRequest(c, err.Error())
+	case errors.Is(err, service.ErrRedactionRuleNotFound):
+		RespondNotFound(c, "Redaction rule not found")
+	default:
+		h.log.Error("Redaction rule operation failed", "error", err)
+		RespondInternalError(c, "Redaction rule operation failed")
+	}
+}
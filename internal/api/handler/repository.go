@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/handsoff/handsoff/internal/model"
 	"github.com/handsoff/handsoff/internal/service"
 	"github.com/handsoff/handsoff/pkg/logger"
 	"gorm.io/gorm"
@@ -175,6 +177,330 @@ func (h *RepositoryHandler) UpdateLLMModel(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "LLM model updated successfully"})
 }
 
+// UpdateTriggerRules updates the review trigger rules of a repository
+// PUT /api/repositories/:id/trigger-rules
+func (h *RepositoryHandler) UpdateTriggerRules(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid repository ID"})
+		return
+	}
+
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error("Project ID missing from context - middleware failure")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	var req model.ReviewTriggerRules
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	rules, err := h.service.UpdateTriggerRules(uint(id), projectID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTriggerRules) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.log.Error("Failed to update trigger rules", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update trigger rules"})
+		return
+	}
+
+	h.log.Info("Repository trigger rules updated", "id", id)
+	c.JSON(http.StatusOK, gin.H{"message": "Trigger rules updated successfully", "trigger_rules": rules})
+}
+
+// UpdateQualityGate updates the quality gate of a repository
+// PUT /api/repositories/:id/quality-gate
+func (h *RepositoryHandler) UpdateQualityGate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid repository ID"})
+		return
+	}
+
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error("Project ID missing from context - middleware failure")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	var req model.QualityGate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	gate, err := h.service.UpdateQualityGate(uint(id), projectID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidQualityGate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.log.Error("Failed to update quality gate", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update quality gate"})
+		return
+	}
+
+	h.log.Info("Repository quality gate updated", "id", id, "enabled", gate.Enabled)
+	c.JSON(http.StatusOK, gin.H{"message": "Quality gate updated successfully", "quality_gate": gate})
+}
+
+// UpdateLabelRulesRequest represents update MR label rules request
+type UpdateLabelRulesRequest struct {
+	LabelRules []model.LabelRule `json:"label_rules"` // Empty list turns labeling off
+}
+
+// UpdateLabelRules updates the rules that keep MR labels in sync with reviews
+// PUT /api/repositories/:id/label-rules
+func (h *RepositoryHandler) UpdateLabelRules(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid repository ID"})
+		return
+	}
+
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error("Project ID missing from context - middleware failure")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	var req UpdateLabelRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	rules, err := h.service.UpdateLabelRules(uint(id), projectID, req.LabelRules)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidLabelRules) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.log.Error("Failed to update label rules", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update label rules"})
+		return
+	}
+
+	h.log.Info("Repository label rules updated", "id", id, "rules", len(rules))
+	c.JSON(http.StatusOK, gin.H{"message": "Label rules updated successfully", "label_rules": rules})
+}
+
+// UpdateMRSummaryRequest represents update MR summary mode request
+type UpdateMRSummaryRequest struct {
+	Mode string `json:"mode"` // "" (off), comment, description
+}
+
+// UpdateMRSummary sets how generated summaries of new merge requests are posted
+// PUT /api/repositories/:id/mr-summary
+func (h *RepositoryHandler) UpdateMRSummary(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid repository ID"})
+		return
+	}
+
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error("Project ID missing from context - middleware failure")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	var req UpdateMRSummaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if err := h.service.UpdateMRSummaryMode(uint(id), projectID, req.Mode); err != nil {
+		if errors.Is(err, service.ErrInvalidMRSummaryMode) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.log.Error("Failed to update MR summary mode", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update MR summary mode"})
+		return
+	}
+
+	h.log.Info("Repository MR summary mode updated", "id", id, "mode", req.Mode)
+	c.JSON(http.StatusOK, gin.H{"message": "MR summary mode updated successfully", "mr_summary_mode": req.Mode})
+}
+
+// UpdateReviewersRequest represents update reviewer recommendation request
+type UpdateReviewersRequest struct {
+	Mode         string `json:"mode"`          // "" (off), suggest, assign
+	MaxReviewers int    `json:"max_reviewers"` // Reviewers recommended per merge request
+}
+
+// UpdateReviewers sets how human reviewers are recommended for merge requests
+// PUT /api/repositories/:id/reviewers
+func (h *RepositoryHandler) UpdateReviewers(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid repository ID"})
+		return
+	}
+
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error("Project ID missing from context - middleware failure")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	var req UpdateReviewersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if err := h.service.UpdateReviewers(uint(id), projectID, req.Mode, req.MaxReviewers); err != nil {
+		if errors.Is(err, service.ErrInvalidReviewerSettings) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.log.Error("Failed to update reviewer settings", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update reviewer settings"})
+		return
+	}
+
+	h.log.Info("Repository reviewer settings updated", "id", id, "mode", req.Mode, "max_reviewers", req.MaxReviewers)
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Reviewer settings updated successfully",
+		"reviewer_mode": req.Mode,
+		"max_reviewers": req.MaxReviewers,
+	})
+}
+
+// UpdateAnalyzersRequest represents update static analyzers request
+type UpdateAnalyzersRequest struct {
+	Analyzers []string `json:"analyzers"` // go_vet, golangci_lint, semgrep; empty list turns analysis off
+}
+
+// UpdateAnalyzers sets the static analyzers run before the LLM review
+// PUT /api/repositories/:id/analyzers
+func (h *RepositoryHandler) UpdateAnalyzers(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid repository ID"})
+		return
+	}
+
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error("Project ID missing from context - middleware failure")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	var req UpdateAnalyzersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	analyzers, err := h.service.UpdateAnalyzers(uint(id), projectID, req.Analyzers)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAnalyzers) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.log.Error("Failed to update analyzers", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update analyzers"})
+		return
+	}
+
+	h.log.Info("Repository analyzers updated", "id", id, "analyzers", analyzers)
+	c.JSON(http.StatusOK, gin.H{"message": "Analyzers updated successfully", "analyzers": analyzers})
+}
+
+// UpdateResponseCacheRequest represents update response cache request
+type UpdateResponseCacheRequest struct {
+	Disabled bool `json:"disabled"` // Bypass the LLM response cache for this repository
+}
+
+// UpdateResponseCache sets whether reviews of a repository bypass the LLM response cache
+// PUT /api/repositories/:id/response-cache
+func (h *RepositoryHandler) UpdateResponseCache(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid repository ID"})
+		return
+	}
+
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error("Project ID missing from context - middleware failure")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	var req UpdateResponseCacheRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if err := h.service.UpdateResponseCache(uint(id), projectID, req.Disabled); err != nil {
+		h.log.Error("Failed to update response cache setting", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update response cache setting"})
+		return
+	}
+
+	h.log.Info("Repository response cache updated", "id", id, "disabled", req.Disabled)
+	c.JSON(http.StatusOK, gin.H{"message": "Response cache setting updated successfully", "response_cache_disabled": req.Disabled})
+}
+
+// UpdateConsensusRequest represents update consensus mode request
+type UpdateConsensusRequest struct {
+	VerifierLLMProviderID *uint  `json:"verifier_llm_provider_id"` // null turns consensus mode off
+	RejectedFindingAction string `json:"rejected_finding_action"`  // drop (default), downgrade
+}
+
+// UpdateConsensus sets the second provider that verifies high and critical findings
+// PUT /api/repositories/:id/consensus
+func (h *RepositoryHandler) UpdateConsensus(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid repository ID"})
+		return
+	}
+
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error("Project ID missing from context - middleware failure")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	var req UpdateConsensusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if err := h.service.UpdateConsensus(uint(id), projectID, req.VerifierLLMProviderID, req.RejectedFindingAction); err != nil {
+		if errors.Is(err, service.ErrInvalidConsensusSettings) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.log.Error("Failed to update consensus settings", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update consensus settings"})
+		return
+	}
+
+	h.log.Info("Repository consensus settings updated", "id", id, "verifier_llm_provider_id", req.VerifierLLMProviderID)
+	c.JSON(http.StatusOK, gin.H{"message": "Consensus settings updated successfully"})
+}
+
 // Delete deletes a repository
 func (h *RepositoryHandler) Delete(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -9,23 +9,104 @@ import (
 	"github.com/handsoff/handsoff/internal/model"
 	"github.com/handsoff/handsoff/internal/service"
 	"github.com/handsoff/handsoff/pkg/logger"
+	"github.com/handsoff/handsoff/pkg/queue"
 	"gorm.io/gorm"
 )
 
 // ReviewHandler handles review-related HTTP requests
 type ReviewHandler struct {
-	db  *gorm.DB
-	log *logger.Logger
+	db                *gorm.DB
+	log               *logger.Logger
+	repositoryService *service.RepositoryService
+	queue             *queue.Client
 }
 
 // NewReviewHandler creates a new review handler
-func NewReviewHandler(db *gorm.DB, log *logger.Logger) *ReviewHandler {
+func NewReviewHandler(db *gorm.DB, log *logger.Logger, repositoryService *service.RepositoryService, queueClient *queue.Client) *ReviewHandler {
 	return &ReviewHandler{
-		db:  db,
-		log: log,
+		db:                db,
+		log:               log,
+		repositoryService: repositoryService,
+		queue:             queueClient,
 	}
 }
 
+// TriggerReview manually (re)runs the code review of a merge request.
+// Intended for CI jobs and scripts using an API token with reviews:write.
+// POST /api/repositories/:id/merge-requests/:iid/review
+func (h *ReviewHandler) TriggerReview(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return
+	}
+
+	repoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid repository ID")
+		return
+	}
+	mrIID, err := strconv.Atoi(c.Param("iid"))
+	if err != nil || mrIID <= 0 {
+		RespondBadRequest(c, "Invalid merge request IID")
+		return
+	}
+
+	repo, mr, err := h.repositoryService.GetMergeRequest(uint(repoID), projectID, mrIID)
+	if err != nil {
+		h.log.Error("Failed to load merge request", "error", err, "repository_id", repoID, "mr_iid", mrIID)
+		RespondNotFound(c, "Merge request not found")
+		return
+	}
+
+	if !repo.IsActive {
+		RespondBadRequest(c, "Repository is not active")
+		return
+	}
+	if repo.LLMProviderID == nil {
+		RespondBadRequest(c, "No LLM provider configured for repository")
+		return
+	}
+
+	req := reviewRequest{
+		RepositoryID:  repo.ID,
+		MRIID:         int64(mr.IID),
+		MRTitle:       mr.Title,
+		SourceBranch:  mr.SourceBranch,
+		TargetBranch:  mr.TargetBranch,
+		MRWebURL:      mr.WebURL,
+		LLMProviderID: *repo.LLMProviderID,
+		HeadSHA:       mr.SHA,
+	}
+	if mr.Author != nil {
+		req.MRAuthor = mr.Author.Username
+	}
+
+	reviewResult, previousTaskID, err := upsertReviewRecord(h.db, req)
+	if err != nil {
+		h.log.Error("Failed to create or update review result", "error", err)
+		RespondInternalError(c, "Failed to create review record")
+		return
+	}
+
+	if err := enqueueReviewTask(h.db, h.queue, h.log, reviewResult, previousTaskID); err != nil {
+		RespondInternalError(c, "Failed to enqueue review task")
+		return
+	}
+
+	h.log.Info("Review triggered manually",
+		"review_id", reviewResult.ID,
+		"repository_id", repo.ID,
+		"mr_iid", mrIID,
+		"username", c.GetString("username"))
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":   "Review task enqueued",
+		"review_id": reviewResult.ID,
+	})
+}
+
 // ListReviews lists all review results with pagination and filtering
 // GET /api/reviews?page=1&page_size=20&status=completed&repository_id=1
 func (h *ReviewHandler) ListReviews(c *gin.Context) {
@@ -376,10 +457,27 @@ func (h *ReviewHandler) GetDashboardTokenUsage(c *gin.Context) {
 		return
 	}
 
+	costs, err := usageService.GetCostBreakdown(projectID, nil, startDate, endDate)
+	if err != nil {
+		h.log.Error("Failed to get cost breakdown", "error", err)
+		RespondInternalError(c, "Failed to fetch cost breakdown")
+		return
+	}
+
+	// Budget state is always for the current month, independent of ?days
+	budgets, err := service.NewBudgetService(h.db).ProjectStatus(projectID, endDate)
+	if err != nil {
+		h.log.Error("Failed to get budget status", "error", err)
+		RespondInternalError(c, "Failed to fetch budget status")
+		return
+	}
+
 	RespondSuccess(c, gin.H{
 		"summary":          stats,
 		"top_repositories": topRepos,
 		"daily_trend":      dailyStats,
+		"cost":             costs,
+		"budgets":          budgets,
 	})
 }
 
@@ -415,11 +513,14 @@ func (h *ReviewHandler) GetRepositoryTokenUsage(c *gin.Context) {
 		TotalCalls       int64   `json:"total_calls"`
 		SuccessfulCalls  int64   `json:"successful_calls"`
 		FailedCalls      int64   `json:"failed_calls"`
+		CachedCalls      int64   `json:"cached_calls"` // Served from the response cache
 		TotalTokens      int64   `json:"total_tokens"`
 		PromptTokens     int64   `json:"prompt_tokens"`
 		CompletionTokens int64   `json:"completion_tokens"`
 		AvgDurationMs    float64 `json:"avg_duration_ms"`
 		SuccessRate      float64 `json:"success_rate"`
+
+		Cost *service.CostBreakdown `gorm:"-" json:"cost"`
 	}
 
 	err = h.db.Model(&model.LLMUsageLog{}).
@@ -427,7 +528,8 @@ func (h *ReviewHandler) GetRepositoryTokenUsage(c *gin.Context) {
 		Select(`
 			COUNT(*) as total_calls,
 			SUM(CASE WHEN status = 'success' THEN 1 ELSE 0 END) as successful_calls,
-			SUM(CASE WHEN status != 'success' THEN 1 ELSE 0 END) as failed_calls,
+			SUM(CASE WHEN status NOT IN ('success', 'cached') THEN 1 ELSE 0 END) as failed_calls,
+			SUM(CASE WHEN status = 'cached' THEN 1 ELSE 0 END) as cached_calls,
 			COALESCE(SUM(total_tokens), 0) as total_tokens,
 			COALESCE(SUM(prompt_tokens), 0) as prompt_tokens,
 			COALESCE(SUM(completion_tokens), 0) as completion_tokens,
@@ -441,8 +543,17 @@ func (h *ReviewHandler) GetRepositoryTokenUsage(c *gin.Context) {
 		return
 	}
 
-	if stats.TotalCalls > 0 {
-		stats.SuccessRate = float64(stats.SuccessfulCalls) / float64(stats.TotalCalls) * 100
+	repoID := repo.ID
+	stats.Cost, err = service.NewUsageService(h.db).GetCostBreakdown(projectID, &repoID, startDate, endDate)
+	if err != nil {
+		h.log.Error("Failed to get repository cost breakdown", "error", err, "repository_id", repositoryID)
+		RespondInternalError(c, "Failed to fetch cost breakdown")
+		return
+	}
+
+	// Cache hits made no API call, so they don't count towards the success rate
+	if apiCalls := stats.TotalCalls - stats.CachedCalls; apiCalls > 0 {
+		stats.SuccessRate = float64(stats.SuccessfulCalls) / float64(apiCalls) * 100
 	}
 
 	RespondSuccess(c, stats)
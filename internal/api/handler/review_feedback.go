@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/service"
+)
+
+// SuggestionFeedbackRequest represents suggestion feedback request payload
+type SuggestionFeedbackRequest struct {
+	Status string `json:"status"` // accepted, fixed, wont_fix, false_positive ("" clears the feedback)
+	Reason string `json:"reason"`
+}
+
+// SetSuggestionFeedback records developer feedback on a suggestion
+// PUT /api/suggestions/:id/feedback
+func (h *ReviewHandler) SetSuggestionFeedback(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid suggestion ID")
+		return
+	}
+
+	var req SuggestionFeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondBadRequest(c, ErrMsgInvalidRequest+": "+err.Error())
+		return
+	}
+
+	suggestion, err := service.NewFeedbackService(h.db).SetFeedback(uint(id), projectID, service.FeedbackInput{
+		Status: req.Status,
+		Reason: req.Reason,
+		By:     c.GetString("username"),
+		Source: model.FeedbackSourceAPI,
+	}, time.Now())
+	switch {
+	case errors.Is(err, service.ErrInvalidFeedback):
+		RespondBadRequest(c, err.Error())
+		return
+	case errors.Is(err, service.ErrSuggestionNotFound):
+		RespondNotFound(c, "Suggestion not found")
+		return
+	case err != nil:
+		h.log.Error("Failed to record suggestion feedback", "error", err, "suggestion_id", id)
+		RespondInternalError(c, "Failed to record feedback")
+		return
+	}
+
+	h.log.Info("Suggestion feedback recorded",
+		"suggestion_id", suggestion.ID,
+		"status", suggestion.FeedbackStatus,
+		"user", suggestion.FeedbackBy)
+	RespondSuccess(c, suggestion)
+}
+
+// GetDashboardPrecision returns the share of findings developers judged real,
+// per category, model and prompt version
+// GET /api/dashboard/precision?days=30&repository_id=1
+func (h *ReviewHandler) GetDashboardPrecision(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return
+	}
+
+	var repositoryID *uint
+	if value := c.Query("repository_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			RespondBadRequest(c, "Invalid repository ID")
+			return
+		}
+		repoID := uint(id)
+		repositoryID = &repoID
+	}
+
+	days := h.parseDaysParam(c)
+	endDate := time.Now()
+	startDate := endDate.AddDate(0, 0, -days)
+
+	stats, err := service.NewFeedbackService(h.db).GetPrecision(projectID, repositoryID, startDate, endDate)
+	if err != nil {
+		h.log.Error("Failed to get precision statistics", "error", err)
+		RespondInternalError(c, "Failed to fetch precision statistics")
+		return
+	}
+
+	RespondSuccess(c, stats)
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/service"
+	"github.com/xanzy/go-gitlab"
+	"gorm.io/gorm"
+)
+
+// WaiveQualityGateRequest represents a quality gate waiver request
+type WaiveQualityGateRequest struct {
+	Reason string `json:"reason" binding:"required"` // Why the failed gate may be ignored
+}
+
+// WaiveQualityGate overrides the failed quality gate of a review: the waiver is
+// recorded for audit and the head commit's status is set to success
+// POST /api/reviews/:id/gate/waive
+func (h *ReviewHandler) WaiveQualityGate(c *gin.Context) {
+	review, ok := h.projectReview(c)
+	if !ok {
+		return
+	}
+
+	var req WaiveQualityGateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondBadRequest(c, ErrMsgInvalidRequest+": "+err.Error())
+		return
+	}
+
+	// Record the waiver first, so a status set on GitLab is never without its audit entry
+	username := c.GetString("username")
+	gateSvc := service.NewQualityGateService(h.db)
+	waiver, err := gateSvc.Waive(review, req.Reason, c.GetUint("user_id"), username, time.Now())
+	switch {
+	case errors.Is(err, service.ErrInvalidQualityGate):
+		RespondBadRequest(c, err.Error())
+		return
+	case errors.Is(err, service.ErrGateNotWaivable):
+		RespondError(c, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		h.log.Error("Failed to record quality gate waiver", "error", err, "review_id", review.ID)
+		RespondInternalError(c, "Failed to record waiver")
+		return
+	}
+
+	description := fmt.Sprintf("Quality gate waived by %s: %s", username, waiver.Reason)
+	err = h.repositoryService.SetCommitStatus(review.Repository.ProjectID, review.Repository.PlatformRepoID,
+		review.HeadSHA, gitlab.Success, description, review.CommentURL)
+	if err != nil {
+		h.log.Error("Failed to set waived commit status", "error", err, "review_id", review.ID)
+		if err := gateSvc.RollbackWaiver(review, waiver); err != nil {
+			h.log.Error("Failed to roll back quality gate waiver", "error", err, "review_id", review.ID, "waiver_id", waiver.ID)
+		}
+		RespondError(c, http.StatusBadGateway, "Failed to update the commit status on GitLab")
+		return
+	}
+
+	h.log.Info("Quality gate waived",
+		"review_id", review.ID,
+		"head_sha", review.HeadSHA,
+		"user", username,
+		"reason", waiver.Reason)
+	RespondCreated(c, waiver)
+}
+
+// ListGateWaivers returns the audit trail of quality gate waivers of a review
+// GET /api/reviews/:id/gate/waivers
+func (h *ReviewHandler) ListGateWaivers(c *gin.Context) {
+	review, ok := h.projectReview(c)
+	if !ok {
+		return
+	}
+
+	waivers, err := service.NewQualityGateService(h.db).Waivers(review.ID)
+	if err != nil {
+		h.log.Error("Failed to list quality gate waivers", "error", err, "review_id", review.ID)
+		RespondInternalError(c, "Failed to list waivers")
+		return
+	}
+	RespondSuccess(c, waivers)
+}
+
+// projectReview loads the review named in the path with its repository, responding
+// with an error if it does not belong to the current project
+func (h *ReviewHandler) projectReview(c *gin.Context) (*model.ReviewResult, bool) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return nil, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid review ID")
+		return nil, false
+	}
+
+	var review model.ReviewResult
+	err = h.db.Preload("Repository").
+		Where("id = ? AND repository_id IN (?)", id,
+			h.db.Model(&model.Repository{}).Select("id").Where("project_id = ?", projectID)).
+		First(&review).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		RespondNotFound(c, "Review not found")
+		return nil, false
+	}
+	if err != nil {
+		h.log.Error("Failed to load review", "error", err, "review_id", id)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return nil, false
+	}
+	return &review, true
+}
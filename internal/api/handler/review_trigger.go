@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/task"
+	"github.com/handsoff/handsoff/pkg/logger"
+	"github.com/handsoff/handsoff/pkg/queue"
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+)
+
+// reviewRequest describes a merge request that should be reviewed.
+// Shared by webhook deliveries and manual (API) triggers.
+type reviewRequest struct {
+	RepositoryID  uint
+	MRIID         int64
+	MRTitle       string
+	MRAuthor      string
+	SourceBranch  string
+	TargetBranch  string
+	MRWebURL      string
+	LLMProviderID uint
+	HeadSHA       string // Commit to review
+
+	WebhookEventID *uint // Delivery that triggered the review (nil for manual triggers)
+}
+
+// reviewQueue is the asynq queue code review tasks run on
+const reviewQueue = "default"
+
+// upsertReviewRecord ensures a pending review result exists for the merge request.
+// One record is kept per repository+MR (idx_repo_mr), re-reviews reset it to pending.
+// Also returns the queue task ID of the review it replaces, so that task can be cancelled.
+func upsertReviewRecord(db *gorm.DB, req reviewRequest) (*model.ReviewResult, string, error) {
+	var previousTaskID string
+	err := db.Model(&model.ReviewResult{}).
+		Where("repository_id = ? AND merge_request_id = ?", req.RepositoryID, req.MRIID).
+		Select("task_id").Scan(&previousTaskID).Error
+	if err != nil {
+		return nil, "", err
+	}
+
+	reviewResult := model.ReviewResult{
+		RepositoryID:   req.RepositoryID,
+		MergeRequestID: req.MRIID,
+	}
+
+	// Update fields if record exists, or create new if not found
+	err = db.Where(&model.ReviewResult{
+		RepositoryID:   req.RepositoryID,
+		MergeRequestID: req.MRIID,
+	}).Assign(model.ReviewResult{
+		MRTitle:       req.MRTitle,
+		MRAuthor:      req.MRAuthor,
+		SourceBranch:  req.SourceBranch,
+		TargetBranch:  req.TargetBranch,
+		MRWebURL:      req.MRWebURL,
+		LLMProviderID: req.LLMProviderID,
+		Status:        model.ReviewStatusPending,
+		CommentPosted: false,
+	}).FirstOrCreate(&reviewResult).Error
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Always overwrite these (zero values included): a manual re-review must not report on
+	// the previous delivery, and moving head_sha marks in-flight reviews of older commits stale
+	err = db.Model(&reviewResult).Updates(map[string]interface{}{
+		"webhook_event_id": req.WebhookEventID,
+		"head_sha":         req.HeadSHA,
+		"task_id":          "",
+		"error_message":    "",
+	}).Error
+	if err != nil {
+		return nil, "", err
+	}
+	reviewResult.WebhookEventID = req.WebhookEventID
+	reviewResult.HeadSHA = req.HeadSHA
+	reviewResult.TaskID = ""
+
+	return &reviewResult, previousTaskID, nil
+}
+
+// enqueueReviewTask enqueues an async code review task for the review's head commit.
+// The task ID is keyed by repository+MR+commit, so the same commit is never queued twice;
+// the task of the review it replaces (an older commit) is cancelled.
+// On failure the review is marked failed so it does not stay pending forever.
+func enqueueReviewTask(db *gorm.DB, q *queue.Client, log *logger.Logger, review *model.ReviewResult, previousTaskID string) error {
+	taskID := task.ReviewTaskID(review.RepositoryID, review.MergeRequestID, review.HeadSHA)
+
+	// Supersede the review of the older commit (queued tasks are deleted, running ones cancelled)
+	if previousTaskID != "" && previousTaskID != taskID {
+		if err := q.CancelTask(reviewQueue, previousTaskID); err != nil {
+			log.Warn("Failed to cancel superseded review task", "task_id", previousTaskID, "error", err)
+		} else {
+			log.Info("Superseded review task cancelled", "task_id", previousTaskID, "review_id", review.ID)
+		}
+	}
+
+	payload := task.CodeReviewPayload{
+		ReviewResultID: review.ID,
+		CommitSHA:      review.HeadSHA,
+	}
+
+	payloadBytes, err := payload.ToJSON()
+	if err != nil {
+		log.Error("Failed to marshal task payload", "error", err)
+		markReviewEnqueueFailed(db, review.ID, fmt.Sprintf("Failed to create task: %v", err))
+		return fmt.Errorf("failed to create task: %w", err)
+	}
+
+	opts := []asynq.Option{
+		asynq.Queue(reviewQueue),
+		asynq.MaxRetry(3),
+	}
+	if taskID != "" {
+		opts = append(opts, asynq.TaskID(taskID))
+	}
+
+	taskInfo, err := q.Enqueue(asynq.NewTask(task.TypeCodeReview, payloadBytes), opts...)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		taskInfo, err = resolveTaskIDConflict(q, log, taskID, payloadBytes, opts)
+	}
+	if err != nil {
+		log.Error("Failed to enqueue task", "error", err)
+		markReviewEnqueueFailed(db, review.ID, fmt.Sprintf("Failed to enqueue task: %v", err))
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	updates := map[string]interface{}{"task_id": taskInfo.ID}
+	if taskInfo.State == asynq.TaskStateActive {
+		updates["status"] = model.ReviewStatusProcessing // Review of this commit is already running
+	}
+	db.Model(&model.ReviewResult{}).Where("id = ?", review.ID).Updates(updates)
+
+	log.Info("Enqueued code review task",
+		"task_id", taskInfo.ID,
+		"review_id", review.ID,
+		"commit_sha", review.HeadSHA,
+		"queue", taskInfo.Queue)
+
+	return nil
+}
+
+// resolveTaskIDConflict handles a review of a commit that already has a task.
+// A pending or running task is reused; a finished or archived one is replaced.
+func resolveTaskIDConflict(q *queue.Client, log *logger.Logger, taskID string, payload []byte, opts []asynq.Option) (*asynq.TaskInfo, error) {
+	existing, err := q.Inspector.GetTaskInfo(reviewQueue, taskID)
+	if errors.Is(err, asynq.ErrTaskNotFound) {
+		// Finished in the meantime
+		return q.Enqueue(asynq.NewTask(task.TypeCodeReview, payload), opts...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect existing task: %w", err)
+	}
+
+	if existing.State != asynq.TaskStateArchived && existing.State != asynq.TaskStateCompleted {
+		log.Info("Review task for this commit already queued", "task_id", taskID, "state", existing.State.String())
+		return existing, nil
+	}
+
+	if err := q.Inspector.DeleteTask(reviewQueue, taskID); err != nil && !errors.Is(err, asynq.ErrTaskNotFound) {
+		return nil, fmt.Errorf("failed to delete finished task: %w", err)
+	}
+	return q.Enqueue(asynq.NewTask(task.TypeCodeReview, payload), opts...)
+}
+
+// markReviewEnqueueFailed marks a review as failed when its task could not be queued
+func markReviewEnqueueFailed(db *gorm.DB, reviewID uint, message string) {
+	db.Model(&model.ReviewResult{}).Where("id = ?", reviewID).Updates(map[string]interface{}{
+		"status":        model.ReviewStatusFailed,
+		"error_message": message,
+	})
+}
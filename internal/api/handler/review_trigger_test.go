@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/task"
+)
+
+func TestUpsertReviewRecordMovesHeadCommit(t *testing.T) {
+	db := setupWebhookTestDB(t)
+
+	req := reviewRequest{RepositoryID: 1, MRIID: 7, LLMProviderID: 1, HeadSHA: "aaa111"}
+	first, previousTaskID, err := upsertReviewRecord(db, req)
+	if err != nil {
+		t.Fatalf("First upsert failed: %v", err)
+	}
+	if previousTaskID != "" {
+		t.Errorf("Expected no previous task, got %q", previousTaskID)
+	}
+
+	// The worker picked it up
+	oldTaskID := task.ReviewTaskID(1, 7, "aaa111")
+	db.Model(&model.ReviewResult{}).Where("id = ?", first.ID).Updates(map[string]interface{}{
+		"task_id": oldTaskID,
+		"status":  model.ReviewStatusProcessing,
+	})
+
+	// A newer commit is pushed
+	req.HeadSHA = "bbb222"
+	second, previousTaskID, err := upsertReviewRecord(db, req)
+	if err != nil {
+		t.Fatalf("Second upsert failed: %v", err)
+	}
+	if second.ID != first.ID {
+		t.Errorf("Expected the record to be reused, got ids %d and %d", first.ID, second.ID)
+	}
+	if previousTaskID != oldTaskID {
+		t.Errorf("Expected previous task %q, got %q", oldTaskID, previousTaskID)
+	}
+
+	var stored model.ReviewResult
+	db.First(&stored, second.ID)
+	if stored.HeadSHA != "bbb222" || stored.TaskID != "" || stored.Status != model.ReviewStatusPending {
+		t.Errorf("Expected pending review of bbb222 without task, got head=%q task=%q status=%q",
+			stored.HeadSHA, stored.TaskID, stored.Status)
+	}
+}
+
+func TestReviewTaskID(t *testing.T) {
+	tests := []struct {
+		name      string
+		commitSHA string
+		expected  string
+	}{
+		{"With commit", "abc123", "review:3:42:abc123"},
+		{"Without commit", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := task.ReviewTaskID(3, 42, tt.commitSHA); got != tt.expected {
+				t.Errorf("ReviewTaskID() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
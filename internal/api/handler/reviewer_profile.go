@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/handsoff/handsoff/internal/service"
+	"github.com/handsoff/handsoff/pkg/logger"
+)
+
+// ReviewerProfileHandler handles reviewer profile requests
+type ReviewerProfileHandler struct {
+	service *service.ReviewerProfileService
+	log     *logger.Logger
+}
+
+// NewReviewerProfileHandler creates a new reviewer profile handler
+func NewReviewerProfileHandler(service *service.ReviewerProfileService, log *logger.Logger) *ReviewerProfileHandler {
+	return &ReviewerProfileHandler{
+		service: service,
+		log:     log,
+	}
+}
+
+// ReviewerProfileRequest represents create/update reviewer profile request payload
+type ReviewerProfileRequest struct {
+	Name           string `json:"name" binding:"required"`
+	Category       string `json:"category" binding:"required"` // security, performance, correctness, quality, style
+	PromptTemplate string `json:"prompt_template"`             // Omit to focus the project prompt on the category
+	LLMProviderID  *uint  `json:"llm_provider_id"`             // Omit to use the repository's provider
+	IsActive       *bool  `json:"is_active"`                   // Defaults to true
+}
+
+func (r ReviewerProfileRequest) input() service.ReviewerProfileInput {
+	isActive := true
+	if r.IsActive != nil {
+		isActive = *r.IsActive
+	}
+	return service.ReviewerProfileInput{
+		Name:           r.Name,
+		Category:       r.Category,
+		PromptTemplate: r.PromptTemplate,
+		LLMProviderID:  r.LLMProviderID,
+		IsActive:       isActive,
+	}
+}
+
+// List returns the reviewer profiles of the current project
+// GET /api/reviewer-profiles
+func (h *ReviewerProfileHandler) List(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return
+	}
+
+	profiles, err := h.service.List(projectID)
+	if err != nil {
+		h.log.Error("Failed to list reviewer profiles", "error", err)
+		RespondInternalError(c, "Failed to list reviewer profiles")
+		return
+	}
+
+	RespondSuccess(c, profiles)
+}
+
+// Create adds a reviewer profile to the current project
+// POST /api/reviewer-profiles
+func (h *ReviewerProfileHandler) Create(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return
+	}
+
+	var req ReviewerProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondBadRequest(c, ErrMsgInvalidRequest+": "+err.Error())
+		return
+	}
+
+	profile, err := h.service.Create(projectID, req.input())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.log.Info("Reviewer profile created",
+		"profile_id", profile.ID,
+		"project_id", projectID,
+		"category", profile.Category)
+
+	RespondCreated(c, profile)
+}
+
+// Update changes a reviewer profile of the current project
+// PUT /api/reviewer-profiles/:id
+func (h *ReviewerProfileHandler) Update(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid reviewer profile ID")
+		return
+	}
+
+	var req ReviewerProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondBadRequest(c, ErrMsgInvalidRequest+": "+err.Error())
+		return
+	}
+
+	profile, err := h.service.Update(uint(id), projectID, req.input())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.log.Info("Reviewer profile updated", "profile_id", profile.ID, "project_id", projectID)
+	RespondSuccess(c, profile)
+}
+
+// Delete removes a reviewer profile of the current project
+// DELETE /api/reviewer-profiles/:id
+func (h *ReviewerProfileHandler) Delete(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid reviewer profile ID")
+		return
+	}
+
+	if err := h.service.Delete(uint(id), projectID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.log.Info("Reviewer profile deleted", "profile_id", id, "project_id", projectID)
+	RespondSuccessWithMessage(c, "Reviewer profile deleted", nil)
+}
+
+// handleError maps reviewer profile service errors to responses
+func (h *ReviewerProfileHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidReviewerProfile):
+		RespondBadRequest(c, err.Error())
+	case errors.Is(err, service.ErrReviewerProfileNotFound):
+		RespondNotFound(c, "Reviewer profile not found")
+	default:
+		h.log.Error("Reviewer profile operation failed", "error", err)
+		RespondInternalError(c, "Reviewer profile operation failed")
+	}
+}
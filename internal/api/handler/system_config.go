@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -74,3 +75,50 @@ func (h *SystemConfigHandler) UpdateWebhookConfig(c *gin.Context) {
 	h.log.Info("Webhook configuration updated", "project_id", projectID)
 	c.JSON(http.StatusOK, gin.H{"message": "Webhook configuration updated successfully"})
 }
+
+// MRSummaryPromptRequest represents update MR summary prompt request
+type MRSummaryPromptRequest struct {
+	PromptTemplate string `json:"prompt_template" binding:"required"`
+}
+
+// GetMRSummaryPrompt returns the prompt template of generated MR summaries
+// (the default template when the project has none)
+func (h *SystemConfigHandler) GetMRSummaryPrompt(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error("Project ID missing from context - middleware failure")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"prompt_template": h.service.GetMRSummaryPrompt(projectID)})
+}
+
+// UpdateMRSummaryPrompt updates the prompt template of generated MR summaries
+func (h *SystemConfigHandler) UpdateMRSummaryPrompt(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error("Project ID missing from context - middleware failure")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	var req MRSummaryPromptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if err := h.service.UpdateMRSummaryPrompt(projectID, req.PromptTemplate); err != nil {
+		if errors.Is(err, service.ErrInvalidPromptTemplate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.log.Error("Failed to update MR summary prompt", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update MR summary prompt"})
+		return
+	}
+
+	h.log.Info("MR summary prompt updated", "project_id", projectID)
+	c.JSON(http.StatusOK, gin.H{"message": "MR summary prompt updated successfully"})
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/service"
+	"github.com/handsoff/handsoff/pkg/logger"
+)
+
+// TestSystemConfigHandler_MRSummaryPrompt checks the MR summary prompt defaults,
+// rejects templates without the diff and is stored per project
+func TestSystemConfigHandler_MRSummaryPrompt(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupWebhookTestDB(t)
+	if err := db.AutoMigrate(&model.SystemConfig{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	h := NewSystemConfigHandler(service.NewSystemConfigService(db), logger.New("error", "console"))
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("project_id", uint(1)) })
+	router.GET("/system/mr-summary-prompt", h.GetMRSummaryPrompt)
+	router.PUT("/system/mr-summary-prompt", h.UpdateMRSummaryPrompt)
+
+	getPrompt := func() string {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/system/mr-summary-prompt", nil))
+		var body struct {
+			PromptTemplate string `json:"prompt_template"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		return body.PromptTemplate
+	}
+	putPrompt := func(prompt string) int {
+		payload, _ := json.Marshal(MRSummaryPromptRequest{PromptTemplate: prompt})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/system/mr-summary-prompt", bytes.NewReader(payload)))
+		return w.Code
+	}
+
+	if got := getPrompt(); got != llm.DefaultMRSummaryPromptTemplate {
+		t.Errorf("Expected the default template before one is set, got %q", got)
+	}
+	if code := putPrompt("Summarize this merge request."); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a template without {{.Diff}}, got %d", code)
+	}
+
+	custom := "Summarize for release notes:\n{{.Diff}}"
+	if code := putPrompt(custom); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if got := getPrompt(); got != custom {
+		t.Errorf("Expected the custom template, got %q", got)
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -41,15 +42,19 @@ type WebhookHandler struct {
 	log       *logger.Logger
 	validator *webhook.Validator
 	queue     *queue.Client
+
+	encryptionKey string // Decrypts platform tokens to answer slash commands
 }
 
 // NewWebhookHandler creates a new webhook handler
-func NewWebhookHandler(db *gorm.DB, log *logger.Logger, queueClient *queue.Client) *WebhookHandler {
+func NewWebhookHandler(db *gorm.DB, log *logger.Logger, queueClient *queue.Client, encryptionKey string) *WebhookHandler {
 	return &WebhookHandler{
 		db:        db,
 		log:       log,
 		validator: webhook.NewValidator(),
 		queue:     queueClient,
+
+		encryptionKey: encryptionKey,
 	}
 }
 
@@ -77,59 +82,163 @@ func (h *WebhookHandler) HandleGitLab(c *gin.Context) {
 		return
 	}
 
-	// Step 1: Parse and validate webhook event
-	mrEvent, err := h.parseAndValidateWebhook(body)
+	// Step 1: Parse base event (only to locate the repository)
+	baseEvent, err := h.parseWebhookEvent(body)
 	if err != nil {
 		h.handleWebhookError(c, err)
 		return
 	}
-	if mrEvent == nil {
-		// Event ignored (not MR event or doesn't trigger review)
-		c.JSON(http.StatusOK, gin.H{"message": "Event ignored"})
-		return
-	}
 
-	// Step 2: Find and validate repository
-	repo, err := h.findAndValidateRepository(mrEvent)
+	// Step 2: Find repository and verify the webhook token before anything else
+	repo, err := h.authenticateRepository(baseEvent.Project.ID, c.GetHeader("X-Gitlab-Token"), body)
 	if err != nil {
 		h.handleWebhookError(c, err)
 		return
 	}
 	if repo == nil {
-		// Repository not configured or no LLM - ignore silently
+		// Repository not configured - ignore silently
 		c.JSON(http.StatusOK, gin.H{"message": "Repository not configured for review"})
 		return
 	}
 
-	// Step 3: Create review result record
-	reviewID, err := h.createReviewRecord(repo, mrEvent)
+	// Step 3: Record the delivery and run it through the review pipeline
+	event := newWebhookEventRecord(repo, baseEvent, body)
+	response, err := h.processEvent(repo, event, body, false)
 	if err != nil {
 		h.handleWebhookError(c, err)
 		return
 	}
 
-	// Step 4: Enqueue review task
-	if err := h.enqueueReviewTask(reviewID); err != nil {
-		h.handleWebhookError(c, err)
-		return
+	c.JSON(http.StatusOK, response)
+}
+
+// processEvent runs an authenticated delivery through the review pipeline and keeps
+// its webhook event record up to date. Replays reuse the stored record and skip
+// commit deduplication.
+// Returns the response body for success (including ignored events), or *WebhookError
+func (h *WebhookHandler) processEvent(repo *model.Repository, event *model.WebhookEvent, body []byte, replay bool) (gin.H, error) {
+	// Comments carry slash commands and developer feedback on review findings
+	if event.EventType == model.EventTypeNote {
+		return h.processNoteEvent(repo, event, body)
 	}
 
-	h.log.Info("Webhook processed successfully", 
+	// Step 1: Parse and validate merge request event
+	mrEvent, reason, err := h.parseAndValidateWebhook(event.EventType, body)
+	if err != nil {
+		h.failWebhookEvent(event, err)
+		return nil, err
+	}
+	if mrEvent != nil {
+		applyMergeRequestFields(event, mrEvent)
+	}
+	// The author is looked up on GitLab, so only before the commit is claimed when
+	// the trigger rules exclude authors
+	var author string
+	if reason == "" {
+		rules := repo.EffectiveTriggerRules()
+		if len(rules.ExcludedAuthors) > 0 {
+			author = h.mergeRequestAuthor(repo, mrEvent)
+		}
+		reason = webhook.EvaluateTriggerRules(rules, mrEvent, author)
+	}
+	if reason == "" && repo.LLMProviderID == nil {
+		h.log.Warn("No LLM model configured for repository",
+			"repository_id", repo.ID,
+			"repository_name", repo.Name)
+		reason = "no LLM provider configured for repository"
+	}
+	if reason != "" {
+		// Event ignored (not MR event, doesn't trigger review, excluded by trigger rules, or repository not ready)
+		h.ignoreWebhookEvent(event, reason)
+		return gin.H{
+			"message":          "Event ignored",
+			"reason":           reason,
+			"webhook_event_id": event.ID,
+		}, nil
+	}
+
+	// Step 2: Claim the commit (redeliveries of the same commit are ignored)
+	if !replay && mrEvent.ObjectAttributes.LastCommit.ID != "" {
+		commitSHA := mrEvent.ObjectAttributes.LastCommit.ID
+		event.CommitSHA = &commitSHA
+	}
+	original, err := h.saveWebhookEvent(event)
+	if err != nil {
+		h.log.Error("Failed to save webhook event record", "error", err)
+		return nil, &WebhookError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to record webhook event",
+			Err:        err,
+		}
+	}
+	if original != nil {
+		return gin.H{
+			"message":          "Duplicate event ignored",
+			"webhook_event_id": event.ID,
+			"duplicate_of":     original.ID,
+		}, nil
+	}
+
+	// Step 3: Create review result record
+	if author == "" {
+		author = h.mergeRequestAuthor(repo, mrEvent)
+	}
+	review, previousTaskID, err := h.createReviewRecord(repo, mrEvent, author, event.ID)
+	if err != nil {
+		h.failWebhookEvent(event, err)
+		return nil, err
+	}
+	reviewID := review.ID
+
+	// Step 4: Enqueue review task (superseding the review of an older commit)
+	if err := h.enqueueReviewTask(review, previousTaskID); err != nil {
+		h.failWebhookEvent(event, err)
+		return nil, err
+	}
+
+	// Step 5: Summarize the merge request if the repository wants it and it has no summary yet
+	h.enqueueMRSummary(repo, review)
+
+	h.log.Info("Webhook processed successfully",
 		"review_id", reviewID,
 		"repository_id", repo.ID,
+		"webhook_event_id", event.ID,
 		"mr_id", mrEvent.GetMRID())
 
-	c.JSON(http.StatusOK, gin.H{
-		"message":   "Webhook received and review task enqueued",
-		"review_id": reviewID,
-	})
+	return gin.H{
+		"message":          "Webhook received and review task enqueued",
+		"review_id":        reviewID,
+		"webhook_event_id": event.ID,
+	}, nil
 }
 
-// parseAndValidateWebhook parses and validates GitLab webhook event
-// Returns (*event, nil) for success, (nil, nil) for ignored events, (nil, error) for errors
+// mergeRequestAuthor returns the username of the merge request's author. The event
+// names whoever triggered it (e.g. pushed a commit), so when that is someone else the
+// author is looked up on GitLab. If the lookup fails, the actor is used instead
+// rather than failing the delivery, which would get the webhook disabled.
+func (h *WebhookHandler) mergeRequestAuthor(repo *model.Repository, mrEvent *webhook.GitLabMergeRequestEvent) string {
+	if mrEvent.AuthorIsActor() {
+		return mrEvent.GetMRAuthor()
+	}
+
+	client, err := h.platformClient(repo)
+	if err != nil {
+		h.log.Warn("Failed to look up merge request author, using the event actor", "error", err, "repository_id", repo.ID)
+		return mrEvent.GetMRAuthor()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), gitlabAPITimeout)
+	defer cancel()
+	author, err := client.GetUserContext(ctx, mrEvent.ObjectAttributes.AuthorID)
+	if err != nil {
+		h.log.Warn("Failed to look up merge request author, using the event actor", "error", err, "repository_id", repo.ID)
+		return mrEvent.GetMRAuthor()
+	}
+	return author.Username
+}
+
+// parseWebhookEvent parses the fields shared by all GitLab webhook events
 // Does NOT touch gin.Context - caller handles all responses
-func (h *WebhookHandler) parseAndValidateWebhook(body []byte) (*webhook.GitLabMergeRequestEvent, error) {
-	// Check event type first (lightweight check)
+func (h *WebhookHandler) parseWebhookEvent(body []byte) (*webhook.GitLabWebhookEvent, error) {
 	var baseEvent webhook.GitLabWebhookEvent
 	if err := json.Unmarshal(body, &baseEvent); err != nil {
 		h.log.Error("Failed to parse webhook event", "error", err)
@@ -139,17 +248,24 @@ func (h *WebhookHandler) parseAndValidateWebhook(body []byte) (*webhook.GitLabMe
 			Err:        err,
 		}
 	}
+	return &baseEvent, nil
+}
 
-	if baseEvent.ObjectKind != "merge_request" {
-		h.log.Info("Ignoring non-merge-request event", "event_type", baseEvent.ObjectKind)
-		return nil, nil // Not an error, just ignored
+// parseAndValidateWebhook parses and validates GitLab merge request event
+// Returns (*event, "", nil) for success, a non-empty reason for ignored events
+// (the MR event is still returned when the payload is one), (nil, "", error) for errors
+// Does NOT touch gin.Context - caller handles all responses
+func (h *WebhookHandler) parseAndValidateWebhook(eventType model.EventType, body []byte) (*webhook.GitLabMergeRequestEvent, string, error) {
+	if eventType != model.EventTypeMergeRequest {
+		h.log.Info("Ignoring non-merge-request event", "event_type", eventType)
+		return nil, fmt.Sprintf("event type %q does not trigger review", eventType), nil // Not an error, just ignored
 	}
 
 	// Parse full MR event
 	var mrEvent webhook.GitLabMergeRequestEvent
 	if err := json.Unmarshal(body, &mrEvent); err != nil {
 		h.log.Error("Failed to parse MR event", "error", err)
-		return nil, &WebhookError{
+		return nil, "", &WebhookError{
 			StatusCode: http.StatusBadRequest,
 			Message:    "Invalid merge request payload",
 			Err:        err,
@@ -157,32 +273,30 @@ func (h *WebhookHandler) parseAndValidateWebhook(body []byte) (*webhook.GitLabMe
 	}
 
 	if !mrEvent.ShouldTriggerReview() {
-		h.log.Info("Event does not trigger review", 
+		h.log.Info("Event does not trigger review",
 			"action", mrEvent.ObjectAttributes.Action,
 			"state", mrEvent.ObjectAttributes.State,
 			"mr_id", mrEvent.GetMRID())
-		return nil, nil // Not an error, just ignored
+		reason := fmt.Sprintf("merge request action %q in state %q does not trigger review",
+			mrEvent.ObjectAttributes.Action, mrEvent.ObjectAttributes.State)
+		return &mrEvent, reason, nil // Not an error, just ignored
 	}
 
-	return &mrEvent, nil
+	return &mrEvent, "", nil
 }
 
-// findAndValidateRepository finds repository and validates webhook signature
+// authenticateRepository finds the repository the event belongs to and verifies the
+// X-Gitlab-Token header against its webhook secret (or the platform secret as fallback).
+// The same GitLab project may be imported by several HandsOff projects, so the
+// repository whose secret matches the token wins.
 // Returns (*repo, nil) for success, (nil, nil) for ignored, (nil, error) for errors
 // Does NOT touch gin.Context - caller handles all responses
-func (h *WebhookHandler) findAndValidateRepository(mrEvent *webhook.GitLabMergeRequestEvent) (*model.Repository, error) {
-	var repo model.Repository
+func (h *WebhookHandler) authenticateRepository(platformRepoID int64, token string, body []byte) (*model.Repository, error) {
+	var repos []model.Repository
 	err := h.db.Preload("LLMProvider").
-		Where("platform_repo_id = ? AND is_active = ?", mrEvent.GetProjectID(), true).
-		First(&repo).Error
-
-	if err == gorm.ErrRecordNotFound {
-		h.log.Warn("Repository not found or inactive", 
-			"project_id", mrEvent.GetProjectID(),
-			"mr_id", mrEvent.GetMRID())
-		return nil, nil // Not an error, just ignored (repo not configured)
-	}
-
+		Where("platform_repo_id = ? AND is_active = ?", platformRepoID, true).
+		Order("id ASC").
+		Find(&repos).Error
 	if err != nil {
 		h.log.Error("Failed to query repository", "error", err)
 		return nil, &WebhookError{
@@ -192,122 +306,138 @@ func (h *WebhookHandler) findAndValidateRepository(mrEvent *webhook.GitLabMergeR
 		}
 	}
 
-	// Validate webhook signature if configured
-	if repo.WebhookSecret != "" {
-		// Note: Signature validation requires raw body, which is not available here
-		// This is a known limitation after refactoring
-		// TODO: Pass raw body from parseAndValidateWebhook if signature validation is needed
+	if len(repos) == 0 {
+		h.log.Warn("Repository not found or inactive", "project_id", platformRepoID)
+		return nil, nil // Not an error, just ignored (repo not configured)
 	}
 
-	if repo.LLMProviderID == nil {
-		h.log.Warn("No LLM model configured for repository", 
-			"repository_id", repo.ID,
-			"repository_name", repo.Name)
-		return nil, nil // Not an error, just ignored (no LLM configured)
+	var lastErr error
+	for i := range repos {
+		secret, err := h.webhookSecret(&repos[i])
+		if err != nil {
+			h.log.Error("Failed to load webhook secret", "repository_id", repos[i].ID, "error", err)
+			return nil, &WebhookError{
+				StatusCode: http.StatusInternalServerError,
+				Message:    "Database error",
+				Err:        err,
+			}
+		}
+
+		lastErr = h.validator.ValidateGitLabSignature(body, token, secret)
+		if lastErr == nil {
+			return &repos[i], nil
+		}
 	}
 
-	return &repo, nil
+	h.log.Warn("Webhook token verification failed",
+		"project_id", platformRepoID,
+		"error", lastErr)
+	return nil, &WebhookError{
+		StatusCode: http.StatusUnauthorized,
+		Message:    "Invalid webhook token",
+		Err:        lastErr,
+	}
 }
 
-// createReviewRecord creates a new review result record in database
-// Returns *WebhookError for centralized handling - does NOT touch gin.Context
-func (h *WebhookHandler) createReviewRecord(repo *model.Repository, mrEvent *webhook.GitLabMergeRequestEvent) (uint, error) {
-	// Use FirstOrCreate to ensure idempotency (prevent duplicate reviews for same MR)
-	reviewResult := model.ReviewResult{
-		RepositoryID:   repo.ID,
-		MergeRequestID: mrEvent.GetMRID(),
+// webhookSecret returns the repository webhook secret, falling back to the platform secret
+func (h *WebhookHandler) webhookSecret(repo *model.Repository) (string, error) {
+	if repo.WebhookSecret != "" {
+		return repo.WebhookSecret, nil
 	}
 
-	// Update fields if record exists, or create new if not found
-	err := h.db.Where(&model.ReviewResult{
-		RepositoryID:   repo.ID,
-		MergeRequestID: mrEvent.GetMRID(),
-	}).Assign(model.ReviewResult{
-		MRTitle:       mrEvent.GetMRTitle(),
-		MRAuthor:      mrEvent.GetMRAuthor(),
-		SourceBranch:  mrEvent.GetSourceBranch(),
-		TargetBranch:  mrEvent.GetTargetBranch(),
-		MRWebURL:      mrEvent.GetMRWebURL(),
-		LLMProviderID: *repo.LLMProviderID,
-		Status:        "pending",
-		CommentPosted: false,
-	}).FirstOrCreate(&reviewResult).Error
+	var platform model.GitPlatformConfig
+	err := h.db.Select("webhook_secret").First(&platform, repo.PlatformID).Error
+	if err == gorm.ErrRecordNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return platform.WebhookSecret, nil
+}
 
+// createReviewRecord creates a new review result record in database for the merge
+// request of author (see mergeRequestAuthor)
+// Returns *WebhookError for centralized handling - does NOT touch gin.Context
+// Also returns the queue task ID of the review it replaces
+func (h *WebhookHandler) createReviewRecord(repo *model.Repository, mrEvent *webhook.GitLabMergeRequestEvent, author string, webhookEventID uint) (*model.ReviewResult, string, error) {
+	// Upsert keeps one record per MR (prevent duplicate reviews for same MR)
+	reviewResult, previousTaskID, err := upsertReviewRecord(h.db, reviewRequest{
+		RepositoryID:   repo.ID,
+		MRIID:          mrEvent.GetMRID(),
+		MRTitle:        mrEvent.GetMRTitle(),
+		MRAuthor:       author,
+		SourceBranch:   mrEvent.GetSourceBranch(),
+		TargetBranch:   mrEvent.GetTargetBranch(),
+		MRWebURL:       mrEvent.GetMRWebURL(),
+		LLMProviderID:  *repo.LLMProviderID,
+		HeadSHA:        mrEvent.ObjectAttributes.LastCommit.ID,
+		WebhookEventID: &webhookEventID,
+	})
 	if err != nil {
 		h.log.Error("Failed to create or update review result", "error", err)
-		return 0, &WebhookError{
+		return nil, "", &WebhookError{
 			StatusCode: http.StatusInternalServerError,
 			Message:    "Failed to create review record",
 			Err:        err,
 		}
 	}
 
-	h.log.Info("Review result record ensured", 
+	h.log.Info("Review result record ensured",
 		"review_id", reviewResult.ID,
 		"repository_id", repo.ID,
 		"mr_id", mrEvent.GetMRID())
 
-	return reviewResult.ID, nil
+	return reviewResult, previousTaskID, nil
 }
 
 // enqueueReviewTask enqueues async review task to Redis queue
 // Returns *WebhookError for centralized handling - does NOT touch gin.Context
-func (h *WebhookHandler) enqueueReviewTask(reviewID uint) error {
-	// REFACTORED: Only pass ReviewResultID instead of all MR fields
-	payload := task.CodeReviewPayload{
-		ReviewResultID: reviewID,
-	}
-
-	payloadBytes, err := payload.ToJSON()
-	if err != nil {
-		h.log.Error("Failed to marshal task payload", "error", err)
-		// Mark review as failed
-		h.db.Model(&model.ReviewResult{}).Where("id = ?", reviewID).Updates(map[string]interface{}{
-			"status":        "failed",
-			"error_message": fmt.Sprintf("Failed to create task: %v", err),
-		})
+func (h *WebhookHandler) enqueueReviewTask(review *model.ReviewResult, previousTaskID string) error {
+	if err := enqueueReviewTask(h.db, h.queue, h.log, review, previousTaskID); err != nil {
 		return &WebhookError{
 			StatusCode: http.StatusInternalServerError,
-			Message:    "Failed to create task",
+			Message:    "Failed to enqueue task",
 			Err:        err,
 		}
 	}
+	return nil
+}
 
-	taskInfo, err := h.queue.Enqueue(
-		asynq.NewTask(task.TypeCodeReview, payloadBytes),
-		asynq.Queue("default"),
-		asynq.MaxRetry(3),
-	)
-
-	if err != nil {
-		h.log.Error("Failed to enqueue task", "error", err)
-		// Mark review as failed
-		h.db.Model(&model.ReviewResult{}).Where("id = ?", reviewID).Updates(map[string]interface{}{
-			"status":        "failed",
-			"error_message": fmt.Sprintf("Failed to enqueue task: %v", err),
-		})
-		return &WebhookError{
-			StatusCode: http.StatusInternalServerError,
-			Message:    "Failed to enqueue task",
-			Err:        err,
-		}
+// enqueueMRSummary queues the generated summary of a merge request that has none yet.
+// Best-effort: the review goes ahead when it cannot be queued.
+func (h *WebhookHandler) enqueueMRSummary(repo *model.Repository, review *model.ReviewResult) {
+	if repo.MRSummaryMode == model.MRSummaryOff {
+		return
 	}
 
-	h.log.Info("Enqueued code review task", 
-		"task_id", taskInfo.ID,
-		"review_id", reviewID,
-		"queue", taskInfo.Queue)
+	var unsummarized int64
+	err := h.db.Model(&model.ReviewResult{}).
+		Where("id = ? AND mr_summary_at IS NULL", review.ID).
+		Count(&unsummarized).Error
+	if err != nil || unsummarized == 0 {
+		return
+	}
 
-	return nil
+	payload := task.MRSummaryPayload{ReviewResultID: review.ID}
+	payloadBytes, err := payload.ToJSON()
+	if err == nil {
+		_, err = h.queue.Enqueue(asynq.NewTask(task.TypeMRSummary, payloadBytes),
+			asynq.Queue(reviewQueue),
+			asynq.MaxRetry(3),
+			asynq.TaskID(task.MRSummaryTaskID(review.RepositoryID, review.MergeRequestID)))
+	}
+	if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+		h.log.Error("Failed to enqueue MR summary task", "error", err, "review_id", review.ID)
+	}
 }
 
-
 // HandleWebhook is a generic webhook handler that routes to platform-specific handlers
 func (h *WebhookHandler) HandleWebhook(c *gin.Context) {
 	// Detect platform from headers
 	// GitLab sends X-Gitlab-Event header
 	// GitHub sends X-GitHub-Event header
-	
+
 	if c.GetHeader("X-Gitlab-Event") != "" || c.GetHeader("X-Gitlab-Token") != "" {
 		h.HandleGitLab(c)
 		return
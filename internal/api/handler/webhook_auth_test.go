@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/webhook"
+	"github.com/handsoff/handsoff/pkg/logger"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupWebhookTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&model.GitPlatformConfig{}, &model.LLMProvider{}, &model.Repository{},
+		&model.WebhookEvent{}, &model.ReviewResult{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	return db
+}
+
+// TestWebhookHandler_TokenVerification checks that every webhook is authenticated
+// against the repository secret, falling back to the platform secret
+func TestWebhookHandler_TokenVerification(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupWebhookTestDB(t)
+
+	platform := model.GitPlatformConfig{BaseURL: "https://gitlab.example.com", AccessToken: "x", WebhookSecret: "platform-secret", ProjectID: 1}
+	bare := model.GitPlatformConfig{BaseURL: "https://gitlab.example.com", AccessToken: "x", PlatformType: "gitea", ProjectID: 1}
+	db.Create(&platform)
+	db.Create(&bare)
+	db.Create(&model.Repository{ProjectID: 1, PlatformID: platform.ID, PlatformRepoID: 10, Name: "own", WebhookSecret: "repo-secret", IsActive: true})
+	db.Create(&model.Repository{ProjectID: 1, PlatformID: platform.ID, PlatformRepoID: 20, Name: "fallback", IsActive: true})
+	db.Create(&model.Repository{ProjectID: 1, PlatformID: bare.ID, PlatformRepoID: 30, Name: "unsigned", IsActive: true})
+
+	h := NewWebhookHandler(db, logger.New("error", "console"), nil, "")
+
+	tests := []struct {
+		name           string
+		projectID      int64
+		token          string
+		expectedStatus int
+	}{
+		{"Repository secret", 10, "repo-secret", http.StatusOK},
+		{"Platform secret does not override repository secret", 10, "platform-secret", http.StatusUnauthorized},
+		{"Wrong token", 10, "guess", http.StatusUnauthorized},
+		{"Missing token", 10, "", http.StatusUnauthorized},
+		{"Platform fallback", 20, "platform-secret", http.StatusOK},
+		{"No secret configured", 30, "anything", http.StatusUnauthorized},
+		{"Unknown repository", 99, "anything", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A push event: authenticated requests are then ignored without touching the queue
+			payload, _ := json.Marshal(webhook.GitLabWebhookEvent{
+				ObjectKind: "push",
+				Project:    webhook.GitLabProject{ID: tt.projectID},
+			})
+			req, _ := http.NewRequest("POST", "/api/webhook", bytes.NewBuffer(payload))
+			req.Header.Set("X-Gitlab-Event", "Push Hook")
+			if tt.token != "" {
+				req.Header.Set("X-Gitlab-Token", tt.token)
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+			h.HandleGitLab(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/handsoff/handsoff/internal/gitlab"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/service"
+	"github.com/handsoff/handsoff/internal/task"
+	"github.com/handsoff/handsoff/internal/webhook"
+	"github.com/handsoff/handsoff/pkg/crypto"
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+)
+
+// commandAccessLevels is the GitLab access level a commenter needs on the project
+// to run each slash command
+var commandAccessLevels = map[string]int{
+	webhook.CommandReview:  gitlab.AccessLevelDeveloper,
+	webhook.CommandIgnore:  gitlab.AccessLevelDeveloper,
+	webhook.CommandExplain: gitlab.AccessLevelReporter,
+}
+
+// gitlabAPITimeout bounds the GitLab calls made while handling a delivery
+const gitlabAPITimeout = 10 * time.Second
+
+// processSlashCommands runs the "/handsoff" commands of an MR comment and answers in
+// its thread. Re-reviews and dismissals happen right away; explanations need an LLM
+// call and are answered by the worker.
+// Returns the response body for success, or *WebhookError
+func (h *WebhookHandler) processSlashCommands(repo *model.Repository, event *model.WebhookEvent, note *webhook.GitLabNoteEvent, commands []webhook.SlashCommand) (gin.H, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gitlabAPITimeout)
+	defer cancel()
+
+	client, err := h.platformClient(repo)
+	if err != nil {
+		h.log.Error("Failed to create GitLab client for slash commands", "error", err, "repository_id", repo.ID)
+		err = &WebhookError{StatusCode: http.StatusInternalServerError, Message: "Platform not configured", Err: err}
+		h.failWebhookEvent(event, err)
+		return nil, err
+	}
+
+	accessLevel := -1 // Looked up on the first command that needs it
+	var replies []string
+	results := make([]gin.H, 0, len(commands))
+	for _, cmd := range commands {
+		if required, ok := commandAccessLevels[cmd.Name]; ok {
+			if accessLevel < 0 {
+				accessLevel, err = client.GetMemberAccessLevelContext(ctx, int(repo.PlatformRepoID), note.User.ID)
+				if err != nil {
+					h.log.Error("Failed to check member access level", "error", err, "user", note.User.Username)
+					accessLevel = gitlab.AccessLevelNone
+				}
+			}
+			if accessLevel < required {
+				replies = append(replies, fmt.Sprintf("`/handsoff %s` needs at least %s access to this project.",
+					cmd.Name, accessLevelName(required)))
+				results = append(results, gin.H{"command": cmd.Name, "status": "denied"})
+				continue
+			}
+		}
+
+		reply, status := h.runSlashCommand(repo, note, cmd)
+		if reply != "" {
+			replies = append(replies, reply)
+		}
+		results = append(results, gin.H{"command": cmd.Name, "number": cmd.Number, "status": status})
+	}
+
+	if len(replies) > 0 {
+		body := fmt.Sprintf("@%s %s", note.User.Username, strings.Join(replies, "\n\n"))
+		if err := client.ReplyToDiscussionContext(ctx, int(repo.PlatformRepoID), int(note.MergeRequest.IID),
+			note.ObjectAttributes.DiscussionID, body); err != nil {
+			h.log.Error("Failed to reply to slash command", "error", err, "repository_id", repo.ID)
+		}
+	}
+
+	h.completeWebhookEvent(event)
+	h.log.Info("Slash commands processed from merge request comment",
+		"repository_id", repo.ID,
+		"mr_iid", note.MergeRequest.IID,
+		"user", note.User.Username,
+		"commands", len(commands))
+
+	return gin.H{
+		"message":          "Commands processed",
+		"results":          results,
+		"webhook_event_id": event.ID,
+	}, nil
+}
+
+// runSlashCommand runs one permitted command and returns the reply to post (empty
+// when the worker answers) and a status for the webhook response
+func (h *WebhookHandler) runSlashCommand(repo *model.Repository, note *webhook.GitLabNoteEvent, cmd webhook.SlashCommand) (string, string) {
+	mr := note.MergeRequest
+	switch cmd.Name {
+	case webhook.CommandReview:
+		if repo.LLMProviderID == nil {
+			return "No LLM provider is configured for this repository.", "failed"
+		}
+		if mr.State != "opened" {
+			return fmt.Sprintf("This merge request is %s, it is not reviewed anymore.", mr.State), "failed"
+		}
+		review, previousTaskID, err := upsertReviewRecord(h.db, reviewRequest{
+			RepositoryID:  repo.ID,
+			MRIID:         mr.IID,
+			MRTitle:       mr.Title,
+			SourceBranch:  mr.SourceBranch,
+			TargetBranch:  mr.TargetBranch,
+			MRWebURL:      mr.URL,
+			LLMProviderID: *repo.LLMProviderID,
+			HeadSHA:       mr.LastCommit.ID,
+			MRAuthor:      h.reviewedAuthor(repo.ID, mr.IID),
+		})
+		if err == nil {
+			err = enqueueReviewTask(h.db, h.queue, h.log, review, previousTaskID)
+		}
+		if err != nil {
+			h.log.Error("Failed to queue review from slash command", "error", err, "repository_id", repo.ID)
+			return "The review could not be queued, please try again later.", "failed"
+		}
+		return "A new review is queued.", "queued"
+
+	case webhook.CommandIgnore, webhook.CommandExplain:
+		if cmd.Number <= 0 {
+			return fmt.Sprintf("Usage: `/handsoff %s <n>`, where n is the finding's number in the review.", cmd.Name), "failed"
+		}
+		var review model.ReviewResult
+		err := h.db.Where("repository_id = ? AND merge_request_id = ?", repo.ID, mr.IID).First(&review).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "This merge request has not been reviewed yet.", "failed"
+		}
+		if err != nil {
+			h.log.Error("Failed to load review for slash command", "error", err, "repository_id", repo.ID)
+			return "Something went wrong, please try again later.", "failed"
+		}
+		if cmd.Name == webhook.CommandIgnore {
+			return h.ignoreFinding(&review, note, cmd)
+		}
+		return h.queueExplanation(&review, note, cmd)
+
+	case webhook.CommandHelp:
+		return webhook.SlashCommandUsage, "answered"
+	}
+	return fmt.Sprintf("Unknown command `/handsoff %s`.\n\n%s", cmd.Name, webhook.SlashCommandUsage), "unknown"
+}
+
+// ignoreFinding dismisses a finding as won't fix, so later reviews of the MR leave it out
+func (h *WebhookHandler) ignoreFinding(review *model.ReviewResult, note *webhook.GitLabNoteEvent, cmd webhook.SlashCommand) (string, string) {
+	_, err := service.NewFeedbackService(h.db).SetFeedbackByNumber(review.ID, cmd.Number, service.FeedbackInput{
+		Status: model.FeedbackWontFix,
+		Reason: cmd.Reason,
+		By:     note.User.Username,
+		Source: model.FeedbackSourceGitLab,
+	}, time.Now())
+	if errors.Is(err, service.ErrSuggestionNotFound) {
+		return fmt.Sprintf("The latest review has no finding #%d.", cmd.Number), "failed"
+	}
+	if errors.Is(err, service.ErrInvalidFeedback) {
+		return err.Error(), "failed"
+	}
+	if err != nil {
+		h.log.Error("Failed to dismiss finding", "error", err, "review_id", review.ID)
+		return "Something went wrong, please try again later.", "failed"
+	}
+	return fmt.Sprintf("Finding #%d is dismissed and will be left out of later reviews.", cmd.Number), "applied"
+}
+
+// queueExplanation queues the worker task that explains a finding in the thread
+func (h *WebhookHandler) queueExplanation(review *model.ReviewResult, note *webhook.GitLabNoteEvent, cmd webhook.SlashCommand) (string, string) {
+	payload := task.ExplainSuggestionPayload{
+		ReviewResultID: review.ID,
+		Number:         cmd.Number,
+		DiscussionID:   note.ObjectAttributes.DiscussionID,
+		Username:       note.User.Username,
+	}
+	payloadBytes, err := payload.ToJSON()
+	if err == nil {
+		_, err = h.queue.Enqueue(asynq.NewTask(task.TypeExplainSuggestion, payloadBytes),
+			asynq.Queue(reviewQueue),
+			asynq.MaxRetry(3),
+			asynq.TaskID(task.ExplainTaskID(review.RepositoryID, note.ObjectAttributes.ID, cmd.Number)))
+	}
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return "", "queued" // Redelivered note, already being answered
+	}
+	if err != nil {
+		h.log.Error("Failed to enqueue explanation task", "error", err, "review_id", review.ID)
+		return "The explanation could not be queued, please try again later.", "failed"
+	}
+	return "", "queued"
+}
+
+// reviewedAuthor keeps the MR author recorded by an earlier review (note events
+// carry the commenter, not the author)
+func (h *WebhookHandler) reviewedAuthor(repositoryID uint, mrIID int64) string {
+	var author string
+	h.db.Model(&model.ReviewResult{}).
+		Where("repository_id = ? AND merge_request_id = ?", repositoryID, mrIID).
+		Select("mr_author").Scan(&author)
+	return author
+}
+
+// platformClient creates a GitLab API client with the repository's platform token
+func (h *WebhookHandler) platformClient(repo *model.Repository) (*gitlab.Client, error) {
+	var platform model.GitPlatformConfig
+	if err := h.db.First(&platform, repo.PlatformID).Error; err != nil {
+		return nil, fmt.Errorf("failed to load platform: %w", err)
+	}
+	token, err := crypto.DecryptString(platform.AccessToken, h.encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt access token: %w", err)
+	}
+	return gitlab.NewClient(platform.BaseURL, token), nil
+}
+
+func accessLevelName(level int) string {
+	switch level {
+	case gitlab.AccessLevelReporter:
+		return "Reporter"
+	case gitlab.AccessLevelDeveloper:
+		return "Developer"
+	case gitlab.AccessLevelMaintainer:
+		return "Maintainer"
+	}
+	return fmt.Sprintf("level %d", level)
+}
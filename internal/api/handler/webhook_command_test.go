@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/webhook"
+	"github.com/handsoff/handsoff/pkg/crypto"
+	"github.com/handsoff/handsoff/pkg/logger"
+)
+
+// TestProcessSlashCommands checks commands are permission-checked against the
+// commenter's GitLab access level and answered in the comment's thread
+func TestProcessSlashCommands(t *testing.T) {
+	var replies []string
+	gitlabServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v4/projects/10/members/all/1":
+			json.NewEncoder(w).Encode(map[string]int{"access_level": 30}) // Developer
+		case r.URL.Path == "/api/v4/projects/10/members/all/2":
+			json.NewEncoder(w).Encode(map[string]int{"access_level": 20}) // Reporter
+		case strings.HasPrefix(r.URL.Path, "/api/v4/projects/10/merge_requests/5/discussions/d1/notes"):
+			var body struct{ Body string }
+			json.NewDecoder(r.Body).Decode(&body)
+			replies = append(replies, body.Body)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer gitlabServer.Close()
+
+	key := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	token, err := crypto.EncryptString("gitlab-token", key)
+	if err != nil {
+		t.Fatalf("Failed to encrypt token: %v", err)
+	}
+
+	db := setupWebhookTestDB(t)
+	if err := db.AutoMigrate(&model.FixSuggestion{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	platform := model.GitPlatformConfig{BaseURL: gitlabServer.URL, AccessToken: token, ProjectID: 1}
+	db.Create(&platform)
+	repo := model.Repository{ProjectID: 1, PlatformID: platform.ID, PlatformRepoID: 10, Name: "repo", IsActive: true}
+	db.Create(&repo)
+	review := model.ReviewResult{RepositoryID: repo.ID, MergeRequestID: 5, Status: model.ReviewStatusCompleted}
+	db.Create(&review)
+	db.Create(&model.FixSuggestion{ReviewResultID: review.ID, FilePath: "a.go", Severity: "low", Description: "x", Number: 1})
+
+	h := NewWebhookHandler(db, logger.New("error", "console"), nil, key)
+
+	tests := []struct {
+		name         string
+		userID       int64
+		note         string
+		wantStatus   string
+		wantReply    string
+		wantFeedback string
+	}{
+		{"Reporter cannot ignore", 2, "/handsoff ignore 1", "denied", "needs at least Developer access", ""},
+		{"Developer ignores", 1, "/handsoff ignore #1: test fixture", "applied", "Finding #1 is dismissed", model.FeedbackWontFix},
+		{"Unknown finding", 1, "/handsoff ignore 7", "failed", "no finding #7", model.FeedbackWontFix},
+		{"Help needs no access", 3, "/handsoff help", "answered", "Available commands", model.FeedbackWontFix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replies = nil
+			event := model.WebhookEvent{RepositoryID: repo.ID, EventType: model.EventTypeNote}
+			note := webhook.GitLabNoteEvent{
+				User:             webhook.GitLabUser{ID: tt.userID, Username: fmt.Sprintf("user%d", tt.userID)},
+				ObjectAttributes: webhook.GitLabNoteAttributes{Note: tt.note, NoteableType: "MergeRequest", DiscussionID: "d1"},
+				MergeRequest:     &webhook.GitLabMergeRequestAttributes{IID: 5, State: "opened"},
+			}
+
+			resp, err := h.processSlashCommands(&repo, &event, &note, webhook.ParseSlashCommands(tt.note))
+			if err != nil {
+				t.Fatalf("processSlashCommands() error = %v", err)
+			}
+			if status := resp["results"].([]gin.H)[0]["status"]; status != tt.wantStatus {
+				t.Errorf("status = %v, want %s", status, tt.wantStatus)
+			}
+			if len(replies) != 1 || !strings.Contains(replies[0], tt.wantReply) {
+				t.Errorf("replies = %q, want one containing %q", replies, tt.wantReply)
+			}
+
+			var sug model.FixSuggestion
+			db.First(&sug)
+			if sug.FeedbackStatus != tt.wantFeedback {
+				t.Errorf("feedback = %q, want %q", sug.FeedbackStatus, tt.wantFeedback)
+			}
+			if event.Status != model.EventStatusCompleted {
+				t.Errorf("event status = %q, want completed", event.Status)
+			}
+		})
+	}
+}
+
+// TestProcessNoteEventFeedbackAccess checks feedback replies need the same access
+// as "/handsoff ignore" before they dismiss a finding
+func TestProcessNoteEventFeedbackAccess(t *testing.T) {
+	var replies []string
+	gitlabServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v4/projects/10/members/all/1":
+			json.NewEncoder(w).Encode(map[string]int{"access_level": 30}) // Developer
+		case r.URL.Path == "/api/v4/projects/10/members/all/2":
+			json.NewEncoder(w).Encode(map[string]int{"access_level": 20}) // Reporter
+		case strings.HasPrefix(r.URL.Path, "/api/v4/projects/10/merge_requests/5/discussions/d1/notes"):
+			var body struct{ Body string }
+			json.NewDecoder(r.Body).Decode(&body)
+			replies = append(replies, body.Body)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer gitlabServer.Close()
+
+	key := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	token, err := crypto.EncryptString("gitlab-token", key)
+	if err != nil {
+		t.Fatalf("Failed to encrypt token: %v", err)
+	}
+
+	db := setupWebhookTestDB(t)
+	if err := db.AutoMigrate(&model.FixSuggestion{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	platform := model.GitPlatformConfig{BaseURL: gitlabServer.URL, AccessToken: token, ProjectID: 1}
+	db.Create(&platform)
+	repo := model.Repository{ProjectID: 1, PlatformID: platform.ID, PlatformRepoID: 10, Name: "repo", IsActive: true}
+	db.Create(&repo)
+	review := model.ReviewResult{RepositoryID: repo.ID, MergeRequestID: 5, Status: model.ReviewStatusCompleted}
+	db.Create(&review)
+	db.Create(&model.FixSuggestion{ReviewResultID: review.ID, FilePath: "a.go", Severity: "low", Description: "x", Number: 1})
+
+	h := NewWebhookHandler(db, logger.New("error", "console"), nil, key)
+
+	tests := []struct {
+		name         string
+		userID       int64
+		wantReplies  int
+		wantFeedback string
+	}{
+		{"Reporter cannot dismiss", 2, 1, ""},
+		{"Developer dismisses", 1, 0, model.FeedbackFalsePositive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replies = nil
+			event := model.WebhookEvent{RepositoryID: repo.ID, EventType: model.EventTypeNote}
+			body, _ := json.Marshal(webhook.GitLabNoteEvent{
+				User: webhook.GitLabUser{ID: tt.userID, Username: fmt.Sprintf("user%d", tt.userID)},
+				ObjectAttributes: webhook.GitLabNoteAttributes{Note: "false positive #1: input is validated",
+					NoteableType: "MergeRequest", DiscussionID: "d1"},
+				MergeRequest: &webhook.GitLabMergeRequestAttributes{IID: 5, State: "opened"},
+			})
+
+			if _, err := h.processNoteEvent(&repo, &event, body); err != nil {
+				t.Fatalf("processNoteEvent() error = %v", err)
+			}
+			if len(replies) != tt.wantReplies {
+				t.Errorf("replies = %q, want %d", replies, tt.wantReplies)
+			}
+
+			var sug model.FixSuggestion
+			db.First(&sug)
+			if sug.FeedbackStatus != tt.wantFeedback {
+				t.Errorf("feedback = %q, want %q", sug.FeedbackStatus, tt.wantFeedback)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/handsoff/handsoff/internal/model"
+	"gorm.io/gorm"
+)
+
+// WebhookEventDetail is a webhook event including its raw payload
+type WebhookEventDetail struct {
+	model.WebhookEvent
+	RawPayload string `json:"raw_payload"`
+}
+
+// ListWebhookEvents lists recorded webhook deliveries of the current project
+// GET /api/webhook-events
+func (h *WebhookHandler) ListWebhookEvents(c *gin.Context) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return
+	}
+
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	offset := (page - 1) * pageSize
+
+	// Build query with project isolation via repository JOIN
+	query := h.db.Model(&model.WebhookEvent{}).
+		Joins("JOIN repositories ON repositories.id = webhook_events.repository_id").
+		Where("repositories.project_id = ?", projectID).
+		Preload("Repository")
+
+	// Apply filters
+	if repositoryID, _ := strconv.ParseUint(c.Query("repository_id"), 10, 32); repositoryID > 0 {
+		query = query.Where("webhook_events.repository_id = ?", repositoryID)
+	}
+	if eventType := c.Query("event_type"); eventType != "" {
+		query = query.Where("webhook_events.event_type = ?", eventType)
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("webhook_events.status = ?", status)
+	}
+	if action := c.Query("action"); action != "" {
+		query = query.Where("webhook_events.action = ?", action)
+	}
+	if mrIID, _ := strconv.ParseInt(c.Query("mr_iid"), 10, 64); mrIID > 0 {
+		query = query.Where("webhook_events.mr_iid = ?", mrIID)
+	}
+	if commitSHA := c.Query("commit_sha"); commitSHA != "" {
+		query = query.Where("webhook_events.commit_sha LIKE ?", commitSHA+"%")
+	}
+
+	// Get total count
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		h.log.Error("Failed to count webhook events", "error", err)
+		RespondInternalError(c, "Failed to count webhook events")
+		return
+	}
+
+	var events []model.WebhookEvent
+	if err := query.
+		Order("webhook_events.created_at DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&events).Error; err != nil {
+		h.log.Error("Failed to list webhook events", "error", err)
+		RespondInternalError(c, "Failed to list webhook events")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": events,
+		"pagination": gin.H{
+			"page":        page,
+			"page_size":   pageSize,
+			"total":       total,
+			"total_pages": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
+
+// GetWebhookEvent returns a single webhook event with its raw payload
+// GET /api/webhook-events/:id
+func (h *WebhookHandler) GetWebhookEvent(c *gin.Context) {
+	event, ok := h.loadWebhookEvent(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, WebhookEventDetail{
+		WebhookEvent: *event,
+		RawPayload:   event.RawPayload,
+	})
+}
+
+// ReplayWebhookEvent runs a stored delivery through the review pipeline again.
+// The event keeps its record (and commit claim); deduplication is bypassed.
+// POST /api/webhook-events/:id/replay
+func (h *WebhookHandler) ReplayWebhookEvent(c *gin.Context) {
+	event, ok := h.loadWebhookEvent(c)
+	if !ok {
+		return
+	}
+
+	repo := event.Repository
+	if repo == nil || !repo.IsActive {
+		RespondBadRequest(c, "Repository is inactive")
+		return
+	}
+
+	// Reset processing state before running the pipeline again
+	event.Repository = nil
+	event.Status = model.EventStatusPending
+	event.IgnoreReason = ""
+	event.ErrorMessage = ""
+	event.ProcessedAt = nil
+	event.ReplayCount++
+
+	h.log.Info("Replaying webhook event",
+		"webhook_event_id", event.ID,
+		"repository_id", repo.ID,
+		"replay_count", event.ReplayCount)
+
+	response, err := h.processEvent(repo, event, []byte(event.RawPayload), true)
+	if err != nil {
+		h.handleWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// loadWebhookEvent loads the webhook event from the :id parameter, scoped to the current project.
+// Responds with an error and returns false if it cannot be loaded.
+func (h *WebhookHandler) loadWebhookEvent(c *gin.Context) (*model.WebhookEvent, bool) {
+	projectID, ok := getProjectID(c)
+	if !ok {
+		h.log.Error(ErrMsgProjectIDMissing)
+		RespondInternalError(c, ErrMsgInternalServer)
+		return nil, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid webhook event ID")
+		return nil, false
+	}
+
+	var event model.WebhookEvent
+	err = h.db.Joins("JOIN repositories ON repositories.id = webhook_events.repository_id").
+		Where("webhook_events.id = ? AND repositories.project_id = ?", id, projectID).
+		Preload("Repository.LLMProvider").
+		First(&event).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		RespondNotFound(c, "Webhook event not found")
+		return nil, false
+	}
+	if err != nil {
+		h.log.Error("Failed to get webhook event", "error", err, "id", id)
+		RespondInternalError(c, "Failed to get webhook event")
+		return nil, false
+	}
+
+	return &event, true
+}
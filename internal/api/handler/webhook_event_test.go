@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/webhook"
+	"github.com/handsoff/handsoff/pkg/crypto"
+	"github.com/handsoff/handsoff/pkg/logger"
+)
+
+func TestWebhookHandler_SaveWebhookEventDeduplicates(t *testing.T) {
+	db := setupWebhookTestDB(t)
+	h := NewWebhookHandler(db, logger.New("error", "console"), nil, "")
+
+	sha := "abc123"
+	first := &model.WebhookEvent{RepositoryID: 1, EventType: model.EventTypeMergeRequest, CommitSHA: &sha}
+	if original, err := h.saveWebhookEvent(first); err != nil || original != nil {
+		t.Fatalf("First delivery: original=%v err=%v", original, err)
+	}
+
+	redelivery := &model.WebhookEvent{RepositoryID: 1, EventType: model.EventTypeMergeRequest, CommitSHA: &sha}
+	original, err := h.saveWebhookEvent(redelivery)
+	if err != nil {
+		t.Fatalf("Redelivery failed: %v", err)
+	}
+	if original == nil || original.ID != first.ID {
+		t.Fatalf("Expected duplicate of event %d, got %+v", first.ID, original)
+	}
+	if redelivery.ID == 0 || redelivery.Status != model.EventStatusIgnored || redelivery.CommitSHA != nil {
+		t.Errorf("Expected redelivery to be recorded as ignored, got %+v", redelivery)
+	}
+
+	// Same commit in another repository (e.g. a fork) is not a duplicate
+	other := &model.WebhookEvent{RepositoryID: 2, EventType: model.EventTypeMergeRequest, CommitSHA: &sha}
+	if original, err := h.saveWebhookEvent(other); err != nil || original != nil {
+		t.Errorf("Other repository: original=%v err=%v", original, err)
+	}
+
+	var count int64
+	db.Model(&model.WebhookEvent{}).Count(&count)
+	if count != 3 {
+		t.Errorf("Expected every delivery to be recorded, got %d rows", count)
+	}
+}
+
+func TestWebhookHandler_ProcessEventRecordsIgnoreReason(t *testing.T) {
+	db := setupWebhookTestDB(t)
+	h := NewWebhookHandler(db, logger.New("error", "console"), nil, "")
+	providerID := uint(1)
+
+	tests := []struct {
+		name        string
+		repo        *model.Repository
+		kind        string
+		action      string
+		state       string
+		expectEvent bool
+	}{
+		{"Push event", &model.Repository{ID: 1, LLMProviderID: &providerID}, "push", "", "", false},
+		{"Merged MR", &model.Repository{ID: 1, LLMProviderID: &providerID}, "merge_request", "merge", "merged", true},
+		{"No LLM provider", &model.Repository{ID: 1}, "merge_request", "open", "opened", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := []byte(`{"object_kind":"` + tt.kind + `","object_attributes":{"iid":5,"action":"` +
+				tt.action + `","state":"` + tt.state + `","last_commit":{"id":"deadbeef"}}}`)
+			event := newWebhookEventRecord(tt.repo, &webhook.GitLabWebhookEvent{ObjectKind: tt.kind}, body)
+
+			if _, err := h.processEvent(tt.repo, event, body, false); err != nil {
+				t.Fatalf("processEvent failed: %v", err)
+			}
+
+			var stored model.WebhookEvent
+			if err := db.First(&stored, event.ID).Error; err != nil {
+				t.Fatalf("Event not recorded: %v", err)
+			}
+			if stored.Status != model.EventStatusIgnored || stored.IgnoreReason == "" {
+				t.Errorf("Expected ignored event with reason, got status=%s reason=%q", stored.Status, stored.IgnoreReason)
+			}
+			if stored.CommitSHA != nil {
+				t.Error("Ignored events must not claim the commit")
+			}
+			if (stored.MRIID != nil) != tt.expectEvent {
+				t.Errorf("Expected MR fields recorded = %v", tt.expectEvent)
+			}
+		})
+	}
+}
+
+// TestWebhookHandler_ExcludedAuthorLookedUp checks excluded authors are matched against
+// the merge request's author, not whoever pushed the commit that triggered the event
+func TestWebhookHandler_ExcludedAuthorLookedUp(t *testing.T) {
+	gitlabServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v4/users/7" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": 7, "username": "renovate-bot"})
+	}))
+	defer gitlabServer.Close()
+
+	key := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	token, err := crypto.EncryptString("gitlab-token", key)
+	if err != nil {
+		t.Fatalf("Failed to encrypt token: %v", err)
+	}
+	db := setupWebhookTestDB(t)
+	platform := model.GitPlatformConfig{BaseURL: gitlabServer.URL, AccessToken: token, ProjectID: 1}
+	db.Create(&platform)
+	providerID := uint(1)
+	repo := &model.Repository{ID: 1, PlatformID: platform.ID, LLMProviderID: &providerID,
+		TriggerRules: &model.ReviewTriggerRules{ExcludedAuthors: []string{"renovate*"}}}
+	h := NewWebhookHandler(db, logger.New("error", "console"), nil, key)
+
+	// alice pushed to the bot's merge request
+	body := []byte(`{"object_kind":"merge_request","user":{"id":1,"username":"alice"},"object_attributes":` +
+		`{"iid":5,"author_id":7,"action":"update","state":"opened","last_commit":{"id":"deadbeef"}}}`)
+	event := newWebhookEventRecord(repo, &webhook.GitLabWebhookEvent{ObjectKind: "merge_request"}, body)
+	if _, err := h.processEvent(repo, event, body, false); err != nil {
+		t.Fatalf("processEvent failed: %v", err)
+	}
+
+	var stored model.WebhookEvent
+	db.First(&stored, event.ID)
+	if stored.Status != model.EventStatusIgnored || !strings.Contains(stored.IgnoreReason, "renovate-bot") {
+		t.Errorf("Expected the event ignored for the bot author, got status=%s reason=%q", stored.Status, stored.IgnoreReason)
+	}
+}
+
+func TestWebhookHandler_AuthorLookupFallsBack(t *testing.T) {
+	var lookups int
+	gitlabServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups++
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer gitlabServer.Close()
+
+	key := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	token, err := crypto.EncryptString("gitlab-token", key)
+	if err != nil {
+		t.Fatalf("Failed to encrypt token: %v", err)
+	}
+	db := setupWebhookTestDB(t)
+	platform := model.GitPlatformConfig{BaseURL: gitlabServer.URL, AccessToken: token, ProjectID: 1}
+	db.Create(&platform)
+	h := NewWebhookHandler(db, logger.New("error", "console"), nil, key)
+
+	// alice pushed to the bot's merge request; no LLM provider, so it stops before the review
+	body := []byte(`{"object_kind":"merge_request","user":{"id":1,"username":"alice"},"object_attributes":` +
+		`{"iid":5,"author_id":7,"action":"update","state":"opened","last_commit":{"id":"deadbeef"}}}`)
+	tests := []struct {
+		name        string
+		rules       *model.ReviewTriggerRules
+		wantLookups int
+	}{
+		{"no author rules", nil, 0},
+		{"excluded authors", &model.ReviewTriggerRules{ExcludedAuthors: []string{"renovate*"}}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookups = 0
+			repo := &model.Repository{ID: 1, PlatformID: platform.ID, TriggerRules: tt.rules}
+			event := newWebhookEventRecord(repo, &webhook.GitLabWebhookEvent{ObjectKind: "merge_request"}, body)
+			if _, err := h.processEvent(repo, event, body, false); err != nil {
+				t.Fatalf("processEvent failed: %v", err)
+			}
+
+			var stored model.WebhookEvent
+			db.First(&stored, event.ID)
+			if stored.Status != model.EventStatusIgnored || !strings.Contains(stored.IgnoreReason, "no LLM provider") {
+				t.Errorf("Expected the event to pass the trigger rules, got status=%s reason=%q", stored.Status, stored.IgnoreReason)
+			}
+			if lookups != tt.wantLookups {
+				t.Errorf("Author looked up %d times, want %d", lookups, tt.wantLookups)
+			}
+		})
+	}
+}
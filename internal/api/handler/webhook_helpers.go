@@ -1,35 +1,128 @@
 package handler
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/handsoff/handsoff/internal/model"
 	"github.com/handsoff/handsoff/internal/webhook"
 )
 
-// createWebhookEventRecord creates a webhook event record for tracking
-func (h *WebhookHandler) createWebhookEventRecord(repo *model.Repository, mrEvent *webhook.GitLabMergeRequestEvent, rawBody []byte) (*model.WebhookEvent, error) {
-	mrIID := mrEvent.GetMRID()
-	webhookEvent := &model.WebhookEvent{
+// newWebhookEventRecord builds an (unsaved) webhook event record for an authenticated delivery
+func newWebhookEventRecord(repo *model.Repository, baseEvent *webhook.GitLabWebhookEvent, rawBody []byte) *model.WebhookEvent {
+	eventType := model.EventType(baseEvent.ObjectKind)
+	if eventType == "" {
+		eventType = model.EventType(baseEvent.EventType)
+	}
+
+	return &model.WebhookEvent{
 		RepositoryID: repo.ID,
-		EventType:    model.EventTypeMergeRequest,
-		Action:       model.MRAction(mrEvent.ObjectAttributes.Action),
-		SourceBranch: mrEvent.GetSourceBranch(),
-		TargetBranch: mrEvent.GetTargetBranch(),
-		MRIID:        &mrIID,
-		CommitSHA:    mrEvent.ObjectAttributes.LastCommit.ID,
+		EventType:    eventType,
 		Status:       model.EventStatusPending,
 		RawPayload:   string(rawBody),
 	}
+}
+
+// applyMergeRequestFields copies merge request metadata onto the webhook event record
+func applyMergeRequestFields(event *model.WebhookEvent, mrEvent *webhook.GitLabMergeRequestEvent) {
+	mrIID := mrEvent.GetMRID()
+	event.Action = model.MRAction(mrEvent.ObjectAttributes.Action)
+	event.SourceBranch = mrEvent.GetSourceBranch()
+	event.TargetBranch = mrEvent.GetTargetBranch()
+	event.MRIID = &mrIID
+}
+
+// saveWebhookEvent creates or updates the webhook event record.
+// When the event carries a commit SHA that was already delivered for the repository,
+// the event is stored as ignored and the original event is returned.
+func (h *WebhookHandler) saveWebhookEvent(event *model.WebhookEvent) (*model.WebhookEvent, error) {
+	if event.ID != 0 {
+		return nil, h.db.Save(event).Error
+	}
+
+	if event.CommitSHA != nil {
+		if original := h.findEventByCommit(event.RepositoryID, *event.CommitSHA); original != nil {
+			return original, h.recordDuplicate(event, original)
+		}
+	}
 
-	if err := h.db.Create(webhookEvent).Error; err != nil {
-		h.log.Error("Failed to create webhook event record", "error", err)
+	if err := h.db.Create(event).Error; err != nil {
+		// Lost a race against a concurrent delivery of the same commit
+		if event.CommitSHA != nil {
+			if original := h.findEventByCommit(event.RepositoryID, *event.CommitSHA); original != nil {
+				return original, h.recordDuplicate(event, original)
+			}
+		}
 		return nil, err
 	}
 
 	h.log.Info("Webhook event record created",
-		"event_id", webhookEvent.ID,
-		"repository_id", repo.ID,
-		"event_type", webhookEvent.EventType,
-		"mr_iid", mrIID)
+		"event_id", event.ID,
+		"repository_id", event.RepositoryID,
+		"event_type", event.EventType)
 
-	return webhookEvent, nil
+	return nil, nil
+}
+
+// findEventByCommit returns the event that already claimed the commit, or nil
+func (h *WebhookHandler) findEventByCommit(repositoryID uint, commitSHA string) *model.WebhookEvent {
+	var original model.WebhookEvent
+	err := h.db.Where("repository_id = ? AND commit_sha = ?", repositoryID, commitSHA).First(&original).Error
+	if err != nil {
+		return nil
+	}
+	return &original
+}
+
+// recordDuplicate stores a redelivered event as ignored, without claiming the commit
+func (h *WebhookHandler) recordDuplicate(event *model.WebhookEvent, original *model.WebhookEvent) error {
+	now := time.Now()
+	event.ID = 0
+	event.CommitSHA = nil
+	event.Status = model.EventStatusIgnored
+	event.IgnoreReason = fmt.Sprintf("duplicate of webhook event #%d", original.ID)
+	event.ProcessedAt = &now
+
+	h.log.Info("Duplicate webhook delivery ignored",
+		"repository_id", event.RepositoryID,
+		"original_event_id", original.ID)
+
+	return h.db.Create(event).Error
+}
+
+// ignoreWebhookEvent records why the event did not trigger a review
+func (h *WebhookHandler) ignoreWebhookEvent(event *model.WebhookEvent, reason string) {
+	now := time.Now()
+	event.Status = model.EventStatusIgnored
+	event.IgnoreReason = reason
+	event.ProcessedAt = &now
+	event.CommitSHA = nil // Only reviewed commits take part in deduplication
+
+	if _, err := h.saveWebhookEvent(event); err != nil {
+		h.log.Error("Failed to save webhook event record", "error", err)
+	}
+}
+
+// completeWebhookEvent records that an event was handled without a review task
+func (h *WebhookHandler) completeWebhookEvent(event *model.WebhookEvent) {
+	now := time.Now()
+	event.Status = model.EventStatusCompleted
+	event.ProcessedAt = &now
+
+	if _, err := h.saveWebhookEvent(event); err != nil {
+		h.log.Error("Failed to save webhook event record", "error", err)
+	}
+}
+
+// failWebhookEvent records a processing error on the webhook event
+func (h *WebhookHandler) failWebhookEvent(event *model.WebhookEvent, cause error) {
+	now := time.Now()
+	event.Status = model.EventStatusFailed
+	event.ErrorMessage = cause.Error()
+	event.ProcessedAt = &now
+	event.CommitSHA = nil // Release the commit so a redelivery can retry it
+
+	if _, err := h.saveWebhookEvent(event); err != nil {
+		h.log.Error("Failed to save webhook event record", "error", err)
+	}
 }
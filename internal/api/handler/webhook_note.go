@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/service"
+	"github.com/handsoff/handsoff/internal/task"
+	"github.com/handsoff/handsoff/internal/webhook"
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+)
+
+// processNoteEvent handles a comment on a merge request: "/handsoff" slash commands,
+// or developer feedback on findings, e.g. "false positive #2: input is validated upstream"
+// Returns the response body for success (including ignored events), or *WebhookError
+func (h *WebhookHandler) processNoteEvent(repo *model.Repository, event *model.WebhookEvent, body []byte) (gin.H, error) {
+	var noteEvent webhook.GitLabNoteEvent
+	if err := json.Unmarshal(body, &noteEvent); err != nil {
+		h.log.Error("Failed to parse note event", "error", err)
+		err = &WebhookError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid note payload",
+			Err:        err,
+		}
+		h.failWebhookEvent(event, err)
+		return nil, err
+	}
+
+	if !noteEvent.IsMergeRequestComment() {
+		return h.ignoreNoteEvent(event, "comment is not on a merge request"), nil
+	}
+	mrIID := noteEvent.MergeRequest.IID
+	event.MRIID = &mrIID
+
+	if slashCommands := webhook.ParseSlashCommands(noteEvent.ObjectAttributes.Note); len(slashCommands) > 0 {
+		return h.processSlashCommands(repo, event, &noteEvent, slashCommands)
+	}
+
+	commands := webhook.ParseFeedbackCommands(noteEvent.ObjectAttributes.Note)
+	if len(commands) == 0 && !noteEvent.IsThreadReply() {
+		return h.ignoreNoteEvent(event, "comment has no feedback on review findings"), nil
+	}
+
+	var review model.ReviewResult
+	err := h.db.Where("repository_id = ? AND merge_request_id = ?", repo.ID, mrIID).First(&review).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return h.ignoreNoteEvent(event, "merge request has not been reviewed"), nil
+	}
+	if err != nil {
+		h.log.Error("Failed to load review for feedback", "error", err)
+		err = &WebhookError{StatusCode: http.StatusInternalServerError, Message: "Database error", Err: err}
+		h.failWebhookEvent(event, err)
+		return nil, err
+	}
+
+	// Other replies in a thread may be questions to the bot
+	if len(commands) == 0 {
+		return h.queueFollowUp(&review, event, &noteEvent)
+	}
+
+	// Feedback dismisses findings like "/handsoff ignore", so it needs the same access
+	if required := commandAccessLevels[webhook.CommandIgnore]; !h.hasAccessLevel(repo, &noteEvent, required) {
+		return h.ignoreNoteEvent(event, fmt.Sprintf("feedback needs at least %s access", accessLevelName(required))), nil
+	}
+
+	feedbackSvc := service.NewFeedbackService(h.db)
+	now := time.Now()
+	applied := 0
+	unknown := []int{}
+	for _, cmd := range commands {
+		_, err := feedbackSvc.SetFeedbackByNumber(review.ID, cmd.Number, service.FeedbackInput{
+			Status: cmd.Status,
+			Reason: cmd.Reason,
+			By:     noteEvent.User.Username,
+			Source: model.FeedbackSourceGitLab,
+		}, now)
+		if errors.Is(err, service.ErrSuggestionNotFound) || errors.Is(err, service.ErrInvalidFeedback) {
+			unknown = append(unknown, cmd.Number)
+			continue
+		}
+		if err != nil {
+			h.log.Error("Failed to record feedback", "error", err, "review_id", review.ID)
+			err = &WebhookError{StatusCode: http.StatusInternalServerError, Message: "Failed to record feedback", Err: err}
+			h.failWebhookEvent(event, err)
+			return nil, err
+		}
+		applied++
+	}
+
+	h.completeWebhookEvent(event)
+	h.log.Info("Review feedback recorded from merge request comment",
+		"review_id", review.ID,
+		"user", noteEvent.User.Username,
+		"applied", applied,
+		"unknown", len(unknown))
+
+	return gin.H{
+		"message":          "Feedback recorded",
+		"review_id":        review.ID,
+		"applied":          applied,
+		"unknown_findings": unknown,
+		"webhook_event_id": event.ID,
+	}, nil
+}
+
+// hasAccessLevel checks the commenter has at least the given access level on the
+// project. When they don't, they are told so in the comment's thread.
+func (h *WebhookHandler) hasAccessLevel(repo *model.Repository, note *webhook.GitLabNoteEvent, required int) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), gitlabAPITimeout)
+	defer cancel()
+
+	client, err := h.platformClient(repo)
+	if err != nil {
+		h.log.Error("Failed to create GitLab client for access check", "error", err, "repository_id", repo.ID)
+		return false
+	}
+	accessLevel, err := client.GetMemberAccessLevelContext(ctx, int(repo.PlatformRepoID), note.User.ID)
+	if err != nil {
+		h.log.Error("Failed to check member access level", "error", err, "user", note.User.Username)
+		return false
+	}
+	if accessLevel >= required {
+		return true
+	}
+
+	if note.ObjectAttributes.DiscussionID != "" {
+		body := fmt.Sprintf("@%s Feedback on findings needs at least %s access to this project.",
+			note.User.Username, accessLevelName(required))
+		if err := client.ReplyToDiscussionContext(ctx, int(repo.PlatformRepoID), int(note.MergeRequest.IID),
+			note.ObjectAttributes.DiscussionID, body); err != nil {
+			h.log.Error("Failed to reply to denied feedback", "error", err, "repository_id", repo.ID)
+		}
+	}
+	return false
+}
+
+// queueFollowUp queues the worker task that answers a reply in a thread. The worker
+// checks the thread was started by HandsOff, which also keeps it from answering itself.
+func (h *WebhookHandler) queueFollowUp(review *model.ReviewResult, event *model.WebhookEvent, note *webhook.GitLabNoteEvent) (gin.H, error) {
+	payload := task.FollowUpReplyPayload{
+		ReviewResultID: review.ID,
+		DiscussionID:   note.ObjectAttributes.DiscussionID,
+		NoteID:         note.ObjectAttributes.ID,
+	}
+	payloadBytes, err := payload.ToJSON()
+	if err == nil {
+		_, err = h.queue.Enqueue(asynq.NewTask(task.TypeFollowUpReply, payloadBytes),
+			asynq.Queue(reviewQueue),
+			asynq.MaxRetry(3),
+			asynq.TaskID(task.FollowUpTaskID(review.RepositoryID, note.ObjectAttributes.ID)))
+	}
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return h.ignoreNoteEvent(event, "reply is already being answered"), nil
+	}
+	if err != nil {
+		h.log.Error("Failed to enqueue follow-up task", "error", err, "review_id", review.ID)
+		err = &WebhookError{StatusCode: http.StatusInternalServerError, Message: "Failed to enqueue task", Err: err}
+		h.failWebhookEvent(event, err)
+		return nil, err
+	}
+
+	h.completeWebhookEvent(event)
+	return gin.H{
+		"message":          "Follow-up reply queued",
+		"review_id":        review.ID,
+		"webhook_event_id": event.ID,
+	}, nil
+}
+
+// ignoreNoteEvent records why a comment was not processed and builds the response
+func (h *WebhookHandler) ignoreNoteEvent(event *model.WebhookEvent, reason string) gin.H {
+	h.ignoreWebhookEvent(event, reason)
+	return gin.H{
+		"message":          "Event ignored",
+		"reason":           reason,
+		"webhook_event_id": event.ID,
+	}
+}
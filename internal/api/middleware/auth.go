@@ -1,18 +1,28 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/handsoff/handsoff/pkg/config"
-	"github.com/handsoff/handsoff/pkg/jwt"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/service"
 )
 
-// Auth is a middleware that validates JWT tokens
-func Auth(cfg *config.Config) gin.HandlerFunc {
-	jwtGen := jwt.NewGenerator(cfg.Security.JWTSecret, cfg.Security.JWTExpiry)
+// Authentication types stored in context under "auth_type"
+const (
+	AuthTypeSession  = "session"   // Browser login (JWT)
+	AuthTypeAPIToken = "api_token" // Scoped API token
+)
 
+// Auth is a middleware that validates JWT access tokens and API tokens.
+// Tokens on the revocation list, tokens issued before a password change and
+// tokens of deactivated users are rejected.
+//
+// API tokens (prefixed with model.APITokenPrefix) are bound to a project, so
+// project_id is set here and ProjectContext checks the owner's role in it.
+func Auth(tokenService *service.AuthTokenService, apiTokenService *service.APITokenService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -34,18 +44,83 @@ func Auth(cfg *config.Config) gin.HandlerFunc {
 		}
 
 		tokenString := parts[1]
-		claims, err := jwtGen.Validate(tokenString)
+		if strings.HasPrefix(tokenString, model.APITokenPrefix) {
+			apiToken, err := apiTokenService.Authenticate(tokenString, c.ClientIP())
+			if err != nil {
+				abortWithTokenError(c, err)
+				return
+			}
+
+			c.Set("user_id", apiToken.UserID)
+			c.Set("username", apiToken.User.Username)
+			c.Set("project_id", apiToken.ProjectID)
+			c.Set("auth_type", AuthTypeAPIToken)
+			c.Set("api_token", apiToken)
+
+			c.Next()
+			return
+		}
+
+		claims, err := tokenService.ValidateAccessToken(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid or expired token",
-			})
-			c.Abort()
+			abortWithTokenError(c, err)
 			return
 		}
 
 		// Store user info in context
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
+		c.Set("auth_type", AuthTypeSession)
+		c.Set("token_claims", claims)
+
+		c.Next()
+	}
+}
+
+// abortWithTokenError maps token validation errors to responses
+func abortWithTokenError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrTokenRevoked):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+	case errors.Is(err, service.ErrUserInactive):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User account is disabled"})
+	case errors.Is(err, service.ErrInvalidToken):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate token"})
+	}
+	c.Abort()
+}
+
+// RequireScope restricts a route to callers granted the given scope.
+// API tokens are checked against their scopes, capped at their owner's current
+// project role; browser sessions against the scopes implied by their project role
+// (see model.ProjectRole.Scopes).
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var roleScopes []string
+		value, hasRole := c.Get("project_role")
+		if role, ok := value.(model.ProjectRole); ok {
+			roleScopes = role.Scopes()
+		}
+
+		granted := model.ScopesGrant(roleScopes, scope)
+		if value, exists := c.Get("api_token"); exists {
+			apiToken, ok := value.(*model.APIToken)
+			granted = granted && ok && apiToken.HasScope(scope)
+		} else if !hasRole {
+			// Session without project role (not resolved yet), nothing to restrict
+			c.Next()
+			return
+		}
+
+		if !granted {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Missing required permission: " + scope,
+			})
+			c.Abort()
+			return
+		}
 
 		c.Next()
 	}
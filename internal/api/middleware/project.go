@@ -11,6 +11,10 @@ import (
 // ProjectContext middleware extracts user's active project ID and sets it in context.
 // This replaces the temporary getUserDefaultProjectID() function calls in handlers.
 //
+// The user's role in the project is stored as "project_role": owner for the
+// project creator, otherwise the role from project_members. For API tokens it is
+// the role of the token's owner in the token's project.
+//
 // Usage in router:
 //   protected.Use(middleware.ProjectContext(db))
 //
@@ -27,17 +31,44 @@ func ProjectContext(db *gorm.DB) gin.HandlerFunc {
 			return
 		}
 
+		// API tokens are bound to a project by the Auth middleware. Their owner must
+		// still have access to it, and the owner's current role caps the token's scopes.
+		if value, exists := c.Get("project_id"); exists {
+			projectID, _ := value.(uint)
+			role, ok := projectRole(db, userID, projectID)
+			if !ok {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Token owner is no longer a member of the project"})
+				c.Abort()
+				return
+			}
+			c.Set("project_role", role)
+			c.Next()
+			return
+		}
+
 		// Try to get active project from user preferences
 		var pref model.UserProjectPreference
 		if err := db.Where("user_id = ?", userID).First(&pref).Error; err == nil {
-			c.Set("project_id", pref.ProjectID)
-			c.Next()
-			return
+			if role, ok := projectRole(db, userID, pref.ProjectID); ok {
+				c.Set("project_id", pref.ProjectID)
+				c.Set("project_role", role)
+				c.Next()
+				return
+			}
 		}
 
 		// If no preference set, get user's first project
 		var project model.Project
-		if err := db.Where("user_id = ?", userID).First(&project).Error; err != nil {
+		if err := db.Where("user_id = ?", userID).First(&project).Error; err == nil {
+			c.Set("project_id", project.ID)
+			c.Set("project_role", model.ProjectRoleOwner)
+			c.Next()
+			return
+		}
+
+		// Otherwise fall back to the first project the user is a member of
+		var member model.ProjectMember
+		if err := db.Where("user_id = ?", userID).Order("id").First(&member).Error; err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"error": "No project found. Please create a project first.",
 			})
@@ -45,7 +76,23 @@ func ProjectContext(db *gorm.DB) gin.HandlerFunc {
 			return
 		}
 
-		c.Set("project_id", project.ID)
+		c.Set("project_id", member.ProjectID)
+		c.Set("project_role", member.Role)
 		c.Next()
 	}
 }
+
+// projectRole returns the user's role in a project, or false without access
+func projectRole(db *gorm.DB, userID interface{}, projectID uint) (model.ProjectRole, bool) {
+	var count int64
+	if err := db.Model(&model.Project{}).Where("id = ? AND user_id = ?", projectID, userID).Count(&count).Error; err == nil && count > 0 {
+		return model.ProjectRoleOwner, true
+	}
+
+	var member model.ProjectMember
+	if err := db.Where("project_id = ? AND user_id = ?", projectID, userID).First(&member).Error; err == nil {
+		return member.Role, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/handsoff/handsoff/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestProjectContextAPITokenOwnerRole checks an API token keeps only the scopes its
+// owner's current role in the project allows
+func TestProjectContextAPITokenOwnerRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&model.Project{}, &model.ProjectMember{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	db.Create(&model.Project{ID: 1, Name: "handsoff", UserID: 1})
+	member := model.ProjectMember{ProjectID: 1, UserID: 2, Role: model.ProjectRoleAdmin}
+	db.Create(&member)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		// As set by Auth for an admin token of user 2
+		c.Set("user_id", uint(2))
+		c.Set("project_id", uint(1))
+		c.Set("api_token", &model.APIToken{UserID: 2, ProjectID: 1, Scopes: []string{model.ScopeAdmin}})
+	})
+	router.Use(ProjectContext(db))
+	router.GET("/read", RequireScope(model.ScopeReviewsRead), func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/admin", RequireScope(model.ScopeAdmin), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	steps := []struct {
+		name     string
+		role     model.ProjectRole // Empty removes the membership
+		path     string
+		wantCode int
+	}{
+		{"admin owner", model.ProjectRoleAdmin, "/admin", http.StatusOK},
+		{"owner lowered to viewer", model.ProjectRoleViewer, "/admin", http.StatusForbidden},
+		{"viewer can still read", model.ProjectRoleViewer, "/read", http.StatusOK},
+		{"owner removed", "", "/read", http.StatusForbidden},
+	}
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			if step.role == "" {
+				db.Delete(&member)
+			} else {
+				db.Model(&member).Update("role", step.role)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, step.path, nil))
+			if w.Code != step.wantCode {
+				t.Errorf("GET %s = %d, want %d", step.path, w.Code, step.wantCode)
+			}
+		})
+	}
+}
@@ -9,12 +9,14 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/handsoff/handsoff/internal/api/handler"
 	"github.com/handsoff/handsoff/internal/api/middleware"
+	"github.com/handsoff/handsoff/internal/model"
 	"github.com/handsoff/handsoff/internal/repository"
 	"github.com/handsoff/handsoff/internal/service"
 	"github.com/handsoff/handsoff/internal/web"
 	"github.com/handsoff/handsoff/pkg/config"
 	"github.com/handsoff/handsoff/pkg/logger"
 	"github.com/handsoff/handsoff/pkg/queue"
+	"github.com/handsoff/handsoff/pkg/ratelimit"
 	"gorm.io/gorm"
 )
 
@@ -45,7 +47,8 @@ func Setup(db *gorm.DB, cfg *config.Config, log *logger.Logger) *gin.Engine {
 	if err != nil {
 		log.Fatal("Failed to create platform service", "error", err)
 	}
-	llmService, err := service.NewLLMService(llmRepo, cfg)
+	rateLimiter := ratelimit.NewLimiter(cfg.Redis)
+	llmService, err := service.NewLLMService(llmRepo, cfg, rateLimiter)
 	if err != nil {
 		log.Fatal("Failed to create LLM service", "error", err)
 	}
@@ -57,80 +60,168 @@ func Setup(db *gorm.DB, cfg *config.Config, log *logger.Logger) *gin.Engine {
 		log.Fatal("Failed to create repository service", "error", err)
 	}
 
+	authTokenService := service.NewAuthTokenService(db, cfg)
+	apiTokenService := service.NewAPITokenService(db)
+	oidcService, err := service.NewOIDCService(db, cfg, authTokenService)
+	if err != nil {
+		log.Fatal("Failed to create OIDC service", "error", err)
+	}
+
 	// Initialize queue client
 	queueClient := queue.NewClient(cfg.Redis)
 
 	// Initialize handlers
-	authHandler := handler.NewAuthHandler(db, cfg, log)
+	authHandler := handler.NewAuthHandler(db, cfg, log, authTokenService, oidcService)
 	healthHandler := handler.NewHealthHandler(db, log)
 	platformHandler := handler.NewPlatformHandler(platformService, db, log)
 	llmHandler := handler.NewLLMHandler(llmService, db, log)
 	repositoryHandler := handler.NewRepositoryHandler(repositoryService, db, log)
 	systemConfigHandler := handler.NewSystemConfigHandler(systemConfigService, log)
-	webhookHandler := handler.NewWebhookHandler(db, log, queueClient)
-	reviewHandler := handler.NewReviewHandler(db, log)
+	webhookHandler := handler.NewWebhookHandler(db, log, queueClient, cfg.Security.EncryptionKey)
+	reviewHandler := handler.NewReviewHandler(db, log, repositoryService, queueClient)
+	apiTokenHandler := handler.NewAPITokenHandler(apiTokenService, log)
+	budgetHandler := handler.NewBudgetHandler(service.NewBudgetService(db), log)
+	pricingHandler := handler.NewPricingHandler(service.NewPricingService(db), log)
+	redactionHandler := handler.NewRedactionHandler(service.NewRedactionService(db), log)
+	reviewerProfileHandler := handler.NewReviewerProfileHandler(service.NewReviewerProfileService(db), log)
 
 	// Public routes
 	public := r.Group("/api")
 	{
 		public.POST("/auth/login", authHandler.Login)
+		public.POST("/auth/refresh", authHandler.Refresh)
+		public.GET("/auth/oidc/config", authHandler.GetOIDCConfig)
+		public.GET("/auth/oidc/login", authHandler.OIDCLogin)
+		public.GET("/auth/oidc/callback", authHandler.OIDCCallback)
 		public.GET("/health", healthHandler.Check)
 		public.HEAD("/health", healthHandler.Check)
 	}
 	// Protected routes (require authentication)
+	// API tokens are limited by their scopes, browser sessions by their project role.
 	protected := r.Group("/api")
-	protected.Use(middleware.Auth(cfg))
+	protected.Use(middleware.Auth(authTokenService, apiTokenService))
 	protected.Use(middleware.ProjectContext(db)) // Add project context
+
+	// Auth routes (any authenticated caller)
+	protected.POST("/auth/logout", authHandler.Logout)
+	protected.POST("/auth/password", authHandler.ChangePassword)
+
+	// Read access (reviews:read)
+	read := protected.Group("")
+	read.Use(middleware.RequireScope(model.ScopeReviewsRead))
 	{
-		// Auth routes
-		protected.POST("/auth/logout", authHandler.Logout)
-		protected.GET("/auth/user", authHandler.GetCurrentUser)
+		read.GET("/auth/user", authHandler.GetCurrentUser)
+
+		read.GET("/repositories", repositoryHandler.List)
+		read.GET("/repositories/:id", repositoryHandler.Get)
+		read.GET("/repositories/:id/statistics", reviewHandler.GetRepositoryStatistics)
+		read.GET("/repositories/:id/token-usage", reviewHandler.GetRepositoryTokenUsage)
+
+		// Review routes
+		read.GET("/reviews", reviewHandler.ListReviews)
+		read.GET("/reviews/:id", reviewHandler.GetReview)
+		read.GET("/reviews/:id/statistics", reviewHandler.GetReviewStatistics)
+		read.GET("/reviews/:id/usage-logs", reviewHandler.GetReviewUsageLogs)
+		read.GET("/reviews/:id/gate/waivers", reviewHandler.ListGateWaivers)
+
+		// Webhook event log
+		read.GET("/webhook-events", webhookHandler.ListWebhookEvents)
+		read.GET("/webhook-events/:id", webhookHandler.GetWebhookEvent)
+
+		// Dashboard routes
+		read.GET("/dashboard/statistics", reviewHandler.GetDashboardStatistics)
+		read.GET("/dashboard/recent", reviewHandler.GetRecentReviews)
+		read.GET("/dashboard/trends", reviewHandler.GetTrendData)
+		read.GET("/dashboard/token-usage", reviewHandler.GetDashboardTokenUsage)
+		read.GET("/dashboard/precision", reviewHandler.GetDashboardPrecision)
+
+		// Budgets (with current usage)
+		read.GET("/budgets", budgetHandler.List)
+
+		// Model pricing catalogue
+		read.GET("/llm/prices", pricingHandler.List)
+	}
+
+	// Write access (reviews:write)
+	write := protected.Group("")
+	write.Use(middleware.RequireScope(model.ScopeReviewsWrite))
+	{
+		write.POST("/repositories/:id/merge-requests/:iid/review", reviewHandler.TriggerReview)
+		write.POST("/webhook-events/:id/replay", webhookHandler.ReplayWebhookEvent)
+		write.PUT("/suggestions/:id/feedback", reviewHandler.SetSuggestionFeedback)
+	}
+
+	// Administration (admin)
+	admin := protected.Group("")
+	admin.Use(middleware.RequireScope(model.ScopeAdmin))
+	{
+		// API token routes
+		admin.GET("/tokens", apiTokenHandler.List)
+		admin.POST("/tokens", apiTokenHandler.Create)
+		admin.DELETE("/tokens/:id", apiTokenHandler.Revoke)
+
+		// Budget routes
+		admin.POST("/budgets", budgetHandler.Create)
+		admin.PUT("/budgets/:id", budgetHandler.Update)
+		admin.DELETE("/budgets/:id", budgetHandler.Delete)
 
 		// Platform routes
-		protected.GET("/platform/config", platformHandler.GetConfig)
-		protected.PUT("/platform/config", platformHandler.UpdateConfig)
-		protected.POST("/platform/test", platformHandler.TestConnection)
+		admin.GET("/platform/config", platformHandler.GetConfig)
+		admin.PUT("/platform/config", platformHandler.UpdateConfig)
+		admin.POST("/platform/test", platformHandler.TestConnection)
 
 		// System Configuration routes
-		protected.GET("/system/webhook", systemConfigHandler.GetWebhookConfig)
-		protected.PUT("/system/webhook", systemConfigHandler.UpdateWebhookConfig)
-
-	// LLM Provider routes
-	protected.GET("/llm/providers", llmHandler.ListProviders)
-	protected.GET("/llm/providers/:id", llmHandler.GetProvider)
-	protected.POST("/llm/providers", llmHandler.CreateProvider)
-	protected.PUT("/llm/providers/:id", llmHandler.UpdateProvider)
-	protected.DELETE("/llm/providers/:id", llmHandler.DeleteProvider)
-	protected.POST("/llm/providers/:id/test", llmHandler.TestProviderConnection)
-	protected.POST("/llm/providers/models", llmHandler.FetchAvailableModels)
-	protected.POST("/llm/providers/test-model", llmHandler.TestTemporaryModel) // Test temporary model config
-	protected.GET("/llm/providers/:id/models", llmHandler.FetchProviderModels)
-
-		// LLM Model routes (removed - simplified to single provider layer)
+		admin.GET("/system/webhook", systemConfigHandler.GetWebhookConfig)
+		admin.PUT("/system/webhook", systemConfigHandler.UpdateWebhookConfig)
+		admin.GET("/system/mr-summary-prompt", systemConfigHandler.GetMRSummaryPrompt)
+		admin.PUT("/system/mr-summary-prompt", systemConfigHandler.UpdateMRSummaryPrompt)
 
-		// Repository routes
-		protected.GET("/repositories/gitlab", repositoryHandler.ListFromGitLab)
-		protected.GET("/repositories", repositoryHandler.List)
-		protected.GET("/repositories/:id", repositoryHandler.Get)
-		protected.POST("/repositories/batch", repositoryHandler.BatchImport)
-		protected.PUT("/repositories/:id/llm", repositoryHandler.UpdateLLMModel)
-		protected.DELETE("/repositories/:id", repositoryHandler.Delete)
-		protected.POST("/repositories/:id/webhook/test", repositoryHandler.TestWebhook)
-		protected.PUT("/repositories/:id/webhook", repositoryHandler.RecreateWebhook)
-		protected.GET("/repositories/:id/statistics", reviewHandler.GetRepositoryStatistics)
-		protected.GET("/repositories/:id/token-usage", reviewHandler.GetRepositoryTokenUsage)
+		// LLM Provider routes
+		admin.GET("/llm/providers", llmHandler.ListProviders)
+		admin.GET("/llm/providers/:id", llmHandler.GetProvider)
+		admin.POST("/llm/providers", llmHandler.CreateProvider)
+		admin.PUT("/llm/providers/:id", llmHandler.UpdateProvider)
+		admin.DELETE("/llm/providers/:id", llmHandler.DeleteProvider)
+		admin.POST("/llm/providers/:id/test", llmHandler.TestProviderConnection)
+		admin.POST("/llm/providers/models", llmHandler.FetchAvailableModels)
+		admin.POST("/llm/providers/test-model", llmHandler.TestTemporaryModel) // Test temporary model config
+		admin.GET("/llm/providers/:id/models", llmHandler.FetchProviderModels)
 
-		// Review routes
-		protected.GET("/reviews", reviewHandler.ListReviews)
-		protected.GET("/reviews/:id", reviewHandler.GetReview)
-		protected.GET("/reviews/:id/statistics", reviewHandler.GetReviewStatistics)
-		protected.GET("/reviews/:id/usage-logs", reviewHandler.GetReviewUsageLogs)
+		// Model pricing routes
+		admin.POST("/llm/prices", pricingHandler.Create)
+		admin.PUT("/llm/prices/:id", pricingHandler.Update)
+		admin.DELETE("/llm/prices/:id", pricingHandler.Delete)
 
-		// Dashboard routes
-		protected.GET("/dashboard/statistics", reviewHandler.GetDashboardStatistics)
-		protected.GET("/dashboard/recent", reviewHandler.GetRecentReviews)
-		protected.GET("/dashboard/trends", reviewHandler.GetTrendData)
-		protected.GET("/dashboard/token-usage", reviewHandler.GetDashboardTokenUsage)
+		// Secret redaction rules (applied to diffs before they reach the LLM)
+		admin.GET("/redaction-rules", redactionHandler.List)
+		admin.POST("/redaction-rules", redactionHandler.Create)
+		admin.PUT("/redaction-rules/:id", redactionHandler.Update)
+		admin.DELETE("/redaction-rules/:id", redactionHandler.Delete)
+
+		// Quality gate waivers (audited)
+		admin.POST("/reviews/:id/gate/waive", reviewHandler.WaiveQualityGate)
+
+		// Reviewer profiles (specialized review passes)
+		admin.GET("/reviewer-profiles", reviewerProfileHandler.List)
+		admin.POST("/reviewer-profiles", reviewerProfileHandler.Create)
+		admin.PUT("/reviewer-profiles/:id", reviewerProfileHandler.Update)
+		admin.DELETE("/reviewer-profiles/:id", reviewerProfileHandler.Delete)
+
+		// Repository routes
+		admin.GET("/repositories/gitlab", repositoryHandler.ListFromGitLab)
+		admin.POST("/repositories/batch", repositoryHandler.BatchImport)
+		admin.PUT("/repositories/:id/llm", repositoryHandler.UpdateLLMModel)
+		admin.PUT("/repositories/:id/trigger-rules", repositoryHandler.UpdateTriggerRules)
+		admin.PUT("/repositories/:id/response-cache", repositoryHandler.UpdateResponseCache)
+		admin.PUT("/repositories/:id/consensus", repositoryHandler.UpdateConsensus)
+		admin.PUT("/repositories/:id/quality-gate", repositoryHandler.UpdateQualityGate)
+		admin.PUT("/repositories/:id/label-rules", repositoryHandler.UpdateLabelRules)
+		admin.PUT("/repositories/:id/mr-summary", repositoryHandler.UpdateMRSummary)
+		admin.PUT("/repositories/:id/reviewers", repositoryHandler.UpdateReviewers)
+		admin.PUT("/repositories/:id/analyzers", repositoryHandler.UpdateAnalyzers)
+		admin.DELETE("/repositories/:id", repositoryHandler.Delete)
+		admin.POST("/repositories/:id/webhook/test", repositoryHandler.TestWebhook)
+		admin.PUT("/repositories/:id/webhook", repositoryHandler.RecreateWebhook)
 	}
 
 	// Webhook routes (public, but with signature verification)
@@ -0,0 +1,159 @@
+// Package codeowners reads GitLab CODEOWNERS files and finds the owners of a path.
+package codeowners
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Locations are the paths GitLab reads a CODEOWNERS file from, in order of precedence
+var Locations = []string{"CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"}
+
+var sectionHeader = regexp.MustCompile(`^\^?\[([^\]]+)\](?:\[\d+\])?\s*(.*)$`)
+
+// File is a parsed CODEOWNERS file
+type File struct {
+	sections []section
+}
+
+type section struct {
+	name  string
+	rules []rule
+}
+
+type rule struct {
+	pattern string
+	re      *regexp.Regexp
+	owners  []string
+}
+
+// Parse parses a CODEOWNERS file. Lines that cannot be parsed are skipped, as GitLab does.
+// Owners are kept as written without the leading "@" (users and groups); emails are dropped.
+func Parse(content string) *File {
+	f := &File{sections: []section{{}}} // Rules before the first header form the default section
+	var defaults []string
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if m := sectionHeader.FindStringSubmatch(line); m != nil {
+			f.sections = append(f.sections, section{name: m[1]})
+			defaults = parseOwners(strings.Fields(m[2]))
+			continue
+		}
+
+		fields := splitUnescaped(line)
+		owners := parseOwners(fields[1:])
+		if len(owners) == 0 {
+			owners = defaults
+		}
+		s := &f.sections[len(f.sections)-1]
+		s.rules = append(s.rules, rule{pattern: fields[0], re: compilePattern(fields[0]), owners: owners})
+	}
+	return f
+}
+
+// Owners returns the owners of a path: in each section the last matching rule wins,
+// and the owners of all sections are combined
+func (f *File) Owners(path string) []string {
+	path = strings.TrimPrefix(path, "/")
+	var owners []string
+	seen := map[string]bool{}
+	for _, s := range f.sections {
+		for i := len(s.rules) - 1; i >= 0; i-- {
+			if !s.rules[i].re.MatchString(path) {
+				continue
+			}
+			for _, owner := range s.rules[i].owners {
+				if !seen[owner] {
+					seen[owner] = true
+					owners = append(owners, owner)
+				}
+			}
+			break
+		}
+	}
+	return owners
+}
+
+// parseOwners keeps @user and @group/subgroup owners, without the "@"
+func parseOwners(fields []string) []string {
+	var owners []string
+	for _, field := range fields {
+		if strings.HasPrefix(field, "#") {
+			break // Trailing comment
+		}
+		if strings.HasPrefix(field, "@") && len(field) > 1 {
+			owners = append(owners, field[1:])
+		}
+	}
+	return owners
+}
+
+// splitUnescaped splits a line on whitespace, keeping escaped spaces ("\ ") in paths
+func splitUnescaped(line string) []string {
+	var fields []string
+	var current strings.Builder
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ' ' || r == '\t':
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+	return fields
+}
+
+// compilePattern turns a CODEOWNERS pattern into a regexp. Patterns starting with "/"
+// are anchored to the repository root, others match at any depth; a pattern also
+// matches everything below a directory of that name, and one ending in "/" only that.
+func compilePattern(pattern string) *regexp.Regexp {
+	anchored := strings.HasPrefix(pattern, "/")
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.Trim(pattern, "/")
+
+	var sb strings.Builder
+	if anchored {
+		sb.WriteString("^")
+	} else {
+		sb.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	if dirOnly {
+		sb.WriteString("/.*$")
+	} else {
+		sb.WriteString("(?:/.*)?$")
+	}
+	return regexp.MustCompile(sb.String())
+}
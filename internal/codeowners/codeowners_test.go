@@ -0,0 +1,65 @@
+package codeowners
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOwners(t *testing.T) {
+	f := Parse(`# Default owners
+* @lead
+*.go @gopher
+/docs/ @writer
+internal/api/ @api-team/backend   # comment
+/Makefile
+
+[Security][2] @security
+internal/auth/
+/pkg/crypto/ @alice @bob
+file\ with\ spaces.txt @carol
+`)
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"README.md", []string{"lead"}},
+		{"cmd/main.go", []string{"gopher"}},
+		{"docs/guide.md", []string{"writer"}},
+		{"src/docs/guide.md", []string{"lead"}}, // /docs/ is anchored
+		{"internal/api/handler/review.go", []string{"api-team/backend"}},
+		{"services/internal/api/x.txt", []string{"api-team/backend"}}, // Unanchored: any depth
+		{"Makefile", nil}, // Last match has no owners
+		{"internal/auth/token.go", []string{"gopher", "security"}}, // Both sections apply
+		{"pkg/crypto/aes.go", []string{"gopher", "alice", "bob"}},
+		{"file with spaces.txt", []string{"lead", "carol"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := f.Owners(tt.path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Owners(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompilePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.md", "a/b/c.md", true},
+		{"/*.md", "a/c.md", false},
+		{"/app/**/test.rb", "app/models/deep/test.rb", true},
+		{"/app/**/test.rb", "app/test.rb", true},
+		{"lib", "lib/x.go", true},
+		{"lib/", "lib", false},
+		{"?.go", "ab.go", false},
+	}
+	for _, tt := range tests {
+		if got := compilePattern(tt.pattern).MatchString(tt.path); got != tt.want {
+			t.Errorf("pattern %q on %q = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
@@ -2,10 +2,14 @@ package gitlab
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
+	"strings"
 	"time"
 )
 
@@ -29,10 +33,15 @@ func NewClient(baseURL, accessToken string) *Client {
 
 // GetMRDiff retrieves the diff content of a merge request
 func (c *Client) GetMRDiff(projectID, mrIID int) (string, error) {
+	return c.GetMRDiffContext(context.Background(), projectID, mrIID)
+}
+
+// GetMRDiffContext retrieves the diff content of a merge request, aborting when ctx is done
+func (c *Client) GetMRDiffContext(ctx context.Context, projectID, mrIID int) (string, error) {
 	// GitLab API endpoint: GET /api/v4/projects/:id/merge_requests/:merge_request_iid/changes
 	url := fmt.Sprintf("%s/api/v4/projects/%d/merge_requests/%d/changes", c.baseURL, projectID, mrIID)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
@@ -87,8 +96,59 @@ func (c *Client) GetMRDiff(projectID, mrIID int) (string, error) {
 	return fullDiff.String(), nil
 }
 
+// MergeRequest holds the merge request fields needed by the review worker
+type MergeRequest struct {
+	IID         int64  `json:"iid"`
+	State       string `json:"state"` // opened, closed, merged
+	SHA         string `json:"sha"`   // Head commit of the source branch
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Reviewers   []User `json:"reviewers"`
+}
+
+// GetMergeRequest retrieves a merge request
+func (c *Client) GetMergeRequest(projectID, mrIID int) (*MergeRequest, error) {
+	return c.GetMergeRequestContext(context.Background(), projectID, mrIID)
+}
+
+// GetMergeRequestContext retrieves a merge request, aborting when ctx is done
+func (c *Client) GetMergeRequestContext(ctx context.Context, projectID, mrIID int) (*MergeRequest, error) {
+	// GitLab API endpoint: GET /api/v4/projects/:id/merge_requests/:merge_request_iid
+	url := fmt.Sprintf("%s/api/v4/projects/%d/merge_requests/%d", c.baseURL, projectID, mrIID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("PRIVATE-TOKEN", c.accessToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("GitLab API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var mr MergeRequest
+	if err := json.NewDecoder(resp.Body).Decode(&mr); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &mr, nil
+}
+
 // PostMRComment posts a comment to a merge request
 func (c *Client) PostMRComment(projectID, mrIID int, comment string) error {
+	return c.PostMRCommentContext(context.Background(), projectID, mrIID, comment)
+}
+
+// PostMRCommentContext posts a comment to a merge request, aborting when ctx is done
+func (c *Client) PostMRCommentContext(ctx context.Context, projectID, mrIID int, comment string) error {
 	// GitLab API endpoint: POST /api/v4/projects/:id/merge_requests/:merge_request_iid/notes
 	url := fmt.Sprintf("%s/api/v4/projects/%d/merge_requests/%d/notes", c.baseURL, projectID, mrIID)
 
@@ -102,7 +162,7 @@ func (c *Client) PostMRComment(projectID, mrIID int, comment string) error {
 		return fmt.Errorf("failed to marshal comment payload: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -125,12 +185,377 @@ func (c *Client) PostMRComment(projectID, mrIID int, comment string) error {
 	return nil
 }
 
+// ErrNotFound is returned when the requested GitLab resource does not exist (or was deleted)
+var ErrNotFound = errors.New("not found on GitLab")
+
+// CreateMRDiscussionContext starts a resolvable thread on a merge request, aborting when ctx is done
+func (c *Client) CreateMRDiscussionContext(ctx context.Context, projectID, mrIID int, body string) (*Discussion, error) {
+	// GitLab API endpoint: POST /api/v4/projects/:id/merge_requests/:merge_request_iid/discussions
+	url := fmt.Sprintf("%s/api/v4/projects/%d/merge_requests/%d/discussions", c.baseURL, projectID, mrIID)
+
+	var discussion Discussion
+	if err := c.sendJSON(ctx, "POST", url, map[string]string{"body": body}, http.StatusCreated, &discussion); err != nil {
+		return nil, err
+	}
+	return &discussion, nil
+}
+
+// ReplyToDiscussionContext replies in the thread of an MR discussion, aborting when ctx is done
+func (c *Client) ReplyToDiscussionContext(ctx context.Context, projectID, mrIID int, discussionID, body string) error {
+	// GitLab API endpoint: POST /api/v4/projects/:id/merge_requests/:merge_request_iid/discussions/:discussion_id/notes
+	url := fmt.Sprintf("%s/api/v4/projects/%d/merge_requests/%d/discussions/%s/notes",
+		c.baseURL, projectID, mrIID, discussionID)
+	return c.sendJSON(ctx, "POST", url, map[string]string{"body": body}, http.StatusCreated, nil)
+}
+
+// UpdateMRNoteContext replaces the body of a merge request note, aborting when ctx is done.
+// Returns ErrNotFound if the note was deleted.
+func (c *Client) UpdateMRNoteContext(ctx context.Context, projectID, mrIID int, noteID int64, body string) error {
+	// GitLab API endpoint: PUT /api/v4/projects/:id/merge_requests/:merge_request_iid/notes/:note_id
+	url := fmt.Sprintf("%s/api/v4/projects/%d/merge_requests/%d/notes/%d", c.baseURL, projectID, mrIID, noteID)
+	return c.sendJSON(ctx, "PUT", url, map[string]string{"body": body}, http.StatusOK, nil)
+}
+
+// ResolveMRDiscussionContext resolves or reopens a merge request thread, aborting when ctx is done
+func (c *Client) ResolveMRDiscussionContext(ctx context.Context, projectID, mrIID int, discussionID string, resolved bool) error {
+	// GitLab API endpoint: PUT /api/v4/projects/:id/merge_requests/:merge_request_iid/discussions/:discussion_id
+	url := fmt.Sprintf("%s/api/v4/projects/%d/merge_requests/%d/discussions/%s", c.baseURL, projectID, mrIID, discussionID)
+	return c.sendJSON(ctx, "PUT", url, map[string]bool{"resolved": resolved}, http.StatusOK, nil)
+}
+
+// UpdateMRLabelsContext adds and removes labels of a merge request, aborting when ctx is done
+func (c *Client) UpdateMRLabelsContext(ctx context.Context, projectID, mrIID int, add, remove []string) error {
+	if len(add) == 0 && len(remove) == 0 {
+		return nil
+	}
+
+	// GitLab API endpoint: PUT /api/v4/projects/:id/merge_requests/:merge_request_iid
+	url := fmt.Sprintf("%s/api/v4/projects/%d/merge_requests/%d", c.baseURL, projectID, mrIID)
+	payload := map[string]string{}
+	if len(add) > 0 {
+		payload["add_labels"] = strings.Join(add, ",")
+	}
+	if len(remove) > 0 {
+		payload["remove_labels"] = strings.Join(remove, ",")
+	}
+	return c.sendJSON(ctx, "PUT", url, payload, http.StatusOK, nil)
+}
+
+// UpdateMRDescriptionContext replaces the description of a merge request, aborting when ctx is done
+func (c *Client) UpdateMRDescriptionContext(ctx context.Context, projectID, mrIID int, description string) error {
+	// GitLab API endpoint: PUT /api/v4/projects/:id/merge_requests/:merge_request_iid
+	url := fmt.Sprintf("%s/api/v4/projects/%d/merge_requests/%d", c.baseURL, projectID, mrIID)
+	return c.sendJSON(ctx, "PUT", url, map[string]string{"description": description}, http.StatusOK, nil)
+}
+
+// SetMRReviewersContext replaces the reviewers of a merge request, aborting when ctx is done
+func (c *Client) SetMRReviewersContext(ctx context.Context, projectID, mrIID int, reviewerIDs []int64) error {
+	// GitLab API endpoint: PUT /api/v4/projects/:id/merge_requests/:merge_request_iid
+	url := fmt.Sprintf("%s/api/v4/projects/%d/merge_requests/%d", c.baseURL, projectID, mrIID)
+	return c.sendJSON(ctx, "PUT", url, map[string][]int64{"reviewer_ids": reviewerIDs}, http.StatusOK, nil)
+}
+
+// Commit status states accepted by GitLab
+const (
+	CommitStatusPending  = "pending"
+	CommitStatusSuccess  = "success"
+	CommitStatusFailed   = "failed"
+	CommitStatusCanceled = "canceled"
+)
+
+// maxStatusDescription is the longest commit status description GitLab keeps
+const maxStatusDescription = 255
+
+// CommitStatus is an external status set on a commit, shown on its merge requests
+type CommitStatus struct {
+	State       string `json:"state"` // pending, success, failed, canceled
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	TargetURL   string `json:"target_url,omitempty"`
+}
+
+// SetCommitStatusContext sets the status of a commit, aborting when ctx is done
+func (c *Client) SetCommitStatusContext(ctx context.Context, projectID int, sha string, status CommitStatus) error {
+	// GitLab API endpoint: POST /api/v4/projects/:id/statuses/:sha
+	url := fmt.Sprintf("%s/api/v4/projects/%d/statuses/%s", c.baseURL, projectID, sha)
+	if runes := []rune(status.Description); len(runes) > maxStatusDescription {
+		status.Description = string(runes[:maxStatusDescription-3]) + "..."
+	}
+	return c.sendJSON(ctx, "POST", url, status, http.StatusCreated, nil)
+}
+
+// sendJSON sends an authenticated request with a JSON body and decodes the response
+// into v (when not nil). A 404 is returned as ErrNotFound.
+func (c *Client) sendJSON(ctx context.Context, method, url string, payload interface{}, wantStatus int, v interface{}) error {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("PRIVATE-TOKEN", c.accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != wantStatus {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("GitLab API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+	return nil
+}
+
+// User is a GitLab user
+type User struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+// Note is one comment of a discussion
+type Note struct {
+	ID     int64  `json:"id"`
+	Body   string `json:"body"`
+	Author User   `json:"author"`
+	System bool   `json:"system"` // Generated by GitLab
+}
+
+// Discussion is a comment thread
+type Discussion struct {
+	ID    string `json:"id"`
+	Notes []Note `json:"notes"`
+}
+
+// GetMRDiscussionContext retrieves a discussion of a merge request with its notes, aborting when ctx is done
+func (c *Client) GetMRDiscussionContext(ctx context.Context, projectID, mrIID int, discussionID string) (*Discussion, error) {
+	// GitLab API endpoint: GET /api/v4/projects/:id/merge_requests/:merge_request_iid/discussions/:discussion_id
+	url := fmt.Sprintf("%s/api/v4/projects/%d/merge_requests/%d/discussions/%s", c.baseURL, projectID, mrIID, discussionID)
+
+	var discussion Discussion
+	if err := c.getJSON(ctx, url, &discussion); err != nil {
+		return nil, err
+	}
+	return &discussion, nil
+}
+
+// GetMRNotesContext retrieves the notes of a merge request, oldest first, aborting when ctx is done
+func (c *Client) GetMRNotesContext(ctx context.Context, projectID, mrIID int) ([]Note, error) {
+	// GitLab API endpoint: GET /api/v4/projects/:id/merge_requests/:merge_request_iid/notes
+	const perPage = 100
+	var notes []Note
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/api/v4/projects/%d/merge_requests/%d/notes?sort=asc&order_by=created_at&per_page=%d&page=%d",
+			c.baseURL, projectID, mrIID, perPage, page)
+
+		var batch []Note
+		if err := c.getJSON(ctx, url, &batch); err != nil {
+			return nil, err
+		}
+		notes = append(notes, batch...)
+		if len(batch) < perPage {
+			return notes, nil
+		}
+	}
+}
+
+// Commit is a commit of a merge request
+type Commit struct {
+	ID      string `json:"id"`
+	ShortID string `json:"short_id"`
+	Title   string `json:"title"`
+}
+
+// GetMRCommitsContext retrieves the commits of a merge request, oldest first (up to 100),
+// aborting when ctx is done
+func (c *Client) GetMRCommitsContext(ctx context.Context, projectID, mrIID int) ([]Commit, error) {
+	// GitLab API endpoint: GET /api/v4/projects/:id/merge_requests/:merge_request_iid/commits (newest first)
+	url := fmt.Sprintf("%s/api/v4/projects/%d/merge_requests/%d/commits?per_page=100", c.baseURL, projectID, mrIID)
+
+	var commits []Commit
+	if err := c.getJSON(ctx, url, &commits); err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+	return commits, nil
+}
+
+// GetRawFileContext retrieves a file of the repository at ref, aborting when ctx is done.
+// Returns ErrNotFound if the file does not exist.
+func (c *Client) GetRawFileContext(ctx context.Context, projectID int, path, ref string) (string, error) {
+	// GitLab API endpoint: GET /api/v4/projects/:id/repository/files/:file_path/raw?ref=
+	url := fmt.Sprintf("%s/api/v4/projects/%d/repository/files/%s/raw?ref=%s",
+		c.baseURL, projectID, neturl.PathEscape(path), neturl.QueryEscape(ref))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("PRIVATE-TOKEN", c.accessToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return "", ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GitLab API error (status %d): %s", resp.StatusCode, string(body))
+	}
+	return string(body), nil
+}
+
+// FindUserContext looks up an active user by username, aborting when ctx is done.
+// Returns ErrNotFound if there is none (e.g. the name is a group).
+func (c *Client) FindUserContext(ctx context.Context, username string) (*User, error) {
+	// GitLab API endpoint: GET /api/v4/users?username=
+	url := fmt.Sprintf("%s/api/v4/users?username=%s", c.baseURL, neturl.QueryEscape(username))
+
+	var users []struct {
+		User
+		State string `json:"state"`
+	}
+	if err := c.getJSON(ctx, url, &users); err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		if u.State == "" || u.State == "active" {
+			user := u.User
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// GetUserContext retrieves a user by ID, aborting when ctx is done
+func (c *Client) GetUserContext(ctx context.Context, userID int64) (*User, error) {
+	// GitLab API endpoint: GET /api/v4/users/:id
+	var user User
+	if err := c.getJSON(ctx, fmt.Sprintf("%s/api/v4/users/%d", c.baseURL, userID), &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetCurrentUserContext retrieves the user the access token belongs to, aborting when ctx is done
+func (c *Client) GetCurrentUserContext(ctx context.Context) (*User, error) {
+	// GitLab API endpoint: GET /api/v4/user
+	var user User
+	if err := c.getJSON(ctx, fmt.Sprintf("%s/api/v4/user", c.baseURL), &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// getJSON sends an authenticated GET request and decodes the JSON response into v
+func (c *Client) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("PRIVATE-TOKEN", c.accessToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("GitLab API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// GitLab member access levels
+const (
+	AccessLevelNone       = 0
+	AccessLevelGuest      = 10
+	AccessLevelReporter   = 20
+	AccessLevelDeveloper  = 30
+	AccessLevelMaintainer = 40
+	AccessLevelOwner      = 50
+)
+
+// GetMemberAccessLevelContext returns a user's access level on a project, including
+// access inherited from groups; AccessLevelNone if the user is not a member
+func (c *Client) GetMemberAccessLevelContext(ctx context.Context, projectID int, userID int64) (int, error) {
+	// GitLab API endpoint: GET /api/v4/projects/:id/members/all/:user_id
+	url := fmt.Sprintf("%s/api/v4/projects/%d/members/all/%d", c.baseURL, projectID, userID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return AccessLevelNone, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("PRIVATE-TOKEN", c.accessToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return AccessLevelNone, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return AccessLevelNone, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return AccessLevelNone, fmt.Errorf("GitLab API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var member struct {
+		AccessLevel int `json:"access_level"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&member); err != nil {
+		return AccessLevelNone, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return member.AccessLevel, nil
+}
+
 // TestConnection tests the GitLab API connection
 func (c *Client) TestConnection() error {
+	return c.TestConnectionContext(context.Background())
+}
+
+// TestConnectionContext tests the GitLab API connection, aborting when ctx is done
+func (c *Client) TestConnectionContext(ctx context.Context) error {
 	// GitLab API endpoint: GET /api/v4/user
 	url := fmt.Sprintf("%s/api/v4/user", c.baseURL)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -0,0 +1,78 @@
+package gitlab
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CountChangedLines counts added and removed lines in a unified diff as built by GetMRDiff
+func CountChangedLines(diff string) int {
+	count := 0
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "+++ ") || strings.HasPrefix(line, "--- ") {
+			continue // File headers
+		}
+		if strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-") {
+			count++
+		}
+	}
+	return count
+}
+
+// ChangedFiles lists the files of a unified diff as built by GetMRDiff, by new path
+func ChangedFiles(diff string) []string {
+	var files []string
+	lines := strings.Split(diff, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "--- a/") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ b/") {
+			files = append(files, strings.TrimPrefix(lines[i+1], "+++ b/"))
+		}
+	}
+	return files
+}
+
+// AddedLines maps each file of a unified diff as built by GetMRDiff (by new path) to
+// the new-side line numbers the diff adds
+func AddedLines(diff string) map[string]map[int]bool {
+	added := map[string]map[int]bool{}
+	var file string
+	line := 0
+	lines := strings.Split(diff, "\n")
+	for i := 0; i < len(lines); i++ {
+		text := lines[i]
+		switch {
+		case strings.HasPrefix(text, "--- a/") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ b/"):
+			file = strings.TrimPrefix(lines[i+1], "+++ b/")
+			line = 0
+			i++
+		case strings.HasPrefix(text, "@@ "):
+			line = hunkNewStart(text)
+		case line == 0 || file == "":
+			// Outside a hunk
+		case strings.HasPrefix(text, "+"):
+			if added[file] == nil {
+				added[file] = map[int]bool{}
+			}
+			added[file][line] = true
+			line++
+		case strings.HasPrefix(text, " "):
+			line++
+		}
+	}
+	return added
+}
+
+// hunkNewStart returns the new-side start line of a hunk header
+// ("@@ -10,4 +12,6 @@"), 0 if it cannot be read
+func hunkNewStart(header string) int {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0
+	}
+	start, _, _ := strings.Cut(fields[2][1:], ",")
+	n, err := strconv.Atoi(start)
+	if err != nil {
+		return 0
+	}
+	return n
+}
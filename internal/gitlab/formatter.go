@@ -5,14 +5,18 @@ import (
 	"strings"
 
 	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
 )
 
+// reviewCommentHeader starts every review comment
+const reviewCommentHeader = "## 🤖 AI Code Review\n\n"
+
 // FormatReviewComment formats LLM review response as a GitLab Markdown comment
 func FormatReviewComment(response *llm.ReviewResponse) string {
 	var sb strings.Builder
 
 	// Header
-	sb.WriteString("## 🤖 AI Code Review\n\n")
+	sb.WriteString(reviewCommentHeader)
 
 	// Summary section
 	sb.WriteString("### 📝 Summary\n\n")
@@ -26,15 +30,22 @@ func FormatReviewComment(response *llm.ReviewResponse) string {
 	// Score section
 	sb.WriteString(fmt.Sprintf("**Quality Score:** %d/100\n\n", response.Score))
 
-	// Suggestions section
-	if len(response.Suggestions) > 0 {
-		sb.WriteString(fmt.Sprintf("### 🔍 Issues Found (%d)\n\n", len(response.Suggestions)))
+	// Change since the previous review of the MR (left out on the first review)
+	if p := response.Progress; p != nil && p.Persisting+p.Resolved > 0 {
+		sb.WriteString(fmt.Sprintf("**Since last review:** %d new, %d persisting, %d resolved\n\n",
+			p.New, p.Persisting, p.Resolved))
+	}
+
+	// Suggestions section (findings rejected by the verifier model are left out)
+	suggestions := withoutRejected(response.Suggestions)
+	if len(suggestions) > 0 {
+		sb.WriteString(fmt.Sprintf("### 🔍 Issues Found (%d)\n\n", len(suggestions)))
 
 		// Group by severity
-		criticalIssues := filterBySeverity(response.Suggestions, "critical")
-		highIssues := filterBySeverity(response.Suggestions, "high")
-		mediumIssues := filterBySeverity(response.Suggestions, "medium")
-		lowIssues := filterBySeverity(response.Suggestions, "low")
+		criticalIssues := filterBySeverity(suggestions, "critical")
+		highIssues := filterBySeverity(suggestions, "high")
+		mediumIssues := filterBySeverity(suggestions, "medium")
+		lowIssues := filterBySeverity(suggestions, "low")
 
 		if len(criticalIssues) > 0 {
 			sb.WriteString("#### 🔴 Critical Issues\n\n")
@@ -60,20 +71,54 @@ func FormatReviewComment(response *llm.ReviewResponse) string {
 		sb.WriteString("Great work! The code looks good.\n\n")
 	}
 
+	// Recommended human reviewers, in code spans so nobody is notified by the comment
+	if len(response.Reviewers) > 0 {
+		sb.WriteString("### 👥 Suggested Reviewers\n\n")
+		for _, r := range response.Reviewers {
+			sb.WriteString(fmt.Sprintf("- `@%s` — %s\n", r.Username, r.Reason))
+		}
+		sb.WriteString("\n")
+	}
+
 	// Footer
 	sb.WriteString("---\n\n")
+	if len(suggestions) > 0 {
+		sb.WriteString("_Reply with `accepted #1`, `fixed #1`, `won't fix #1: reason` or `false positive #1: reason` to give feedback on a finding, or `/handsoff help` for commands._\n\n")
+	}
 	sb.WriteString(fmt.Sprintf("_Generated by HandsOff AI Code Review | Model: %s | Tokens: %d | Duration: %.2fs_\n",
 		response.ModelUsed, response.TokensUsed, response.Duration.Seconds()))
 
 	return sb.String()
 }
 
+// IsReviewComment reports whether a note body is a review comment that is not
+// marked outdated yet
+func IsReviewComment(body string) bool {
+	return strings.HasPrefix(body, reviewCommentHeader)
+}
+
+// FormatOutdatedReviewComment collapses a review comment that a newer review replaced
+func FormatOutdatedReviewComment(body string) string {
+	return "<details><summary>⏮️ Outdated AI code review, see the latest review below</summary>\n\n" +
+		body + "\n\n</details>"
+}
+
+// FormatMRSummary formats a generated merge request summary, as a comment or an MR description
+func FormatMRSummary(summary, modelName string) string {
+	var sb strings.Builder
+	sb.WriteString("## 📋 Merge Request Summary\n\n")
+	sb.WriteString(strings.TrimSpace(summary))
+	sb.WriteString("\n\n---\n\n")
+	sb.WriteString(fmt.Sprintf("_Generated by HandsOff AI from the diff and commits | Model: %s_\n", modelName))
+	return sb.String()
+}
+
 // formatIssueTable formats a list of suggestions as a Markdown table
 func formatIssueTable(sb *strings.Builder, suggestions []llm.FixSuggestion) {
-	sb.WriteString("| File | Lines | Category | Description |\n")
-	sb.WriteString("|------|-------|----------|-------------|\n")
+	sb.WriteString("| # | File | Lines | Category | Description |\n")
+	sb.WriteString("|---|------|-------|----------|-------------|\n")
 
-	for _, sug := range suggestions {
+	for i, sug := range suggestions {
 		filePath := sug.FilePath
 		if filePath == "" {
 			filePath = "_general_"
@@ -91,8 +136,8 @@ func formatIssueTable(sb *strings.Builder, suggestions []llm.FixSuggestion) {
 			description = description[:97] + "..."
 		}
 
-		sb.WriteString(fmt.Sprintf("| `%s` | %s | **%s** | %s |\n",
-			filePath, lineInfo, category, description))
+		sb.WriteString(fmt.Sprintf("| %d | `%s` | %s | **%s** | %s |\n",
+			suggestionNumber(sug, i), filePath, lineInfo, category, description))
 	}
 
 	sb.WriteString("\n")
@@ -100,7 +145,7 @@ func formatIssueTable(sb *strings.Builder, suggestions []llm.FixSuggestion) {
 	// Detailed suggestions
 	sb.WriteString("<details>\n<summary>📋 Detailed Suggestions</summary>\n\n")
 	for i, sug := range suggestions {
-		sb.WriteString(fmt.Sprintf("**%d. %s**\n\n", i+1, sug.Description))
+		sb.WriteString(fmt.Sprintf("**%d. %s**\n\n", suggestionNumber(sug, i), sug.Description))
 
 		if sug.FilePath != "" {
 			sb.WriteString(fmt.Sprintf("- **File:** `%s`\n", sug.FilePath))
@@ -111,6 +156,13 @@ func formatIssueTable(sb *strings.Builder, suggestions []llm.FixSuggestion) {
 			sb.WriteString(fmt.Sprintf("- **Category:** %s\n", sug.Category))
 		}
 
+		switch sug.Verdict {
+		case model.VerdictConfirmed:
+			sb.WriteString(fmt.Sprintf("- **Verified:** confirmed by a second model (confidence %.0f%%)\n", sug.VerdictConfidence*100))
+		case model.VerdictDowngraded:
+			sb.WriteString(fmt.Sprintf("- **Verified:** not confirmed by a second model, downgraded: %s\n", sug.VerdictReason))
+		}
+
 		if sug.Suggestion != "" {
 			sb.WriteString(fmt.Sprintf("\n**Recommendation:**\n%s\n", sug.Suggestion))
 		}
@@ -126,6 +178,26 @@ func formatIssueTable(sb *strings.Builder, suggestions []llm.FixSuggestion) {
 	sb.WriteString("</details>\n\n")
 }
 
+// suggestionNumber returns the number developers use to refer to a finding,
+// falling back to its position in the table when the findings were not numbered
+func suggestionNumber(sug llm.FixSuggestion, i int) int {
+	if sug.Number > 0 {
+		return sug.Number
+	}
+	return i + 1
+}
+
+// withoutRejected drops the findings the verifier model rejected
+func withoutRejected(suggestions []llm.FixSuggestion) []llm.FixSuggestion {
+	var kept []llm.FixSuggestion
+	for _, sug := range suggestions {
+		if !sug.Rejected() {
+			kept = append(kept, sug)
+		}
+	}
+	return kept
+}
+
 // filterBySeverity filters suggestions by severity level
 func filterBySeverity(suggestions []llm.FixSuggestion, severity string) []llm.FixSuggestion {
 	var filtered []llm.FixSuggestion
@@ -0,0 +1,37 @@
+package llm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// ResponseCacheKey identifies a review response that can be reused: the same diff
+// (up to formatting noise), reviewed with the same rendered prompt by the same
+// provider model. The prompt is rendered without the diff, which is hashed normalized.
+func ResponseCacheKey(providerID uint, modelName, template string, data PromptData) string {
+	diff := NormalizeDiff(data.Diff)
+	data.Diff = ""
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%d\x00%s\x00", providerID, modelName)
+	h.Write([]byte(RenderPrompt(template, data)))
+	h.Write([]byte{0})
+	h.Write([]byte(diff))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// NormalizeDiff strips what changes between identical diffs: line endings,
+// trailing whitespace and "index <sha>..<sha>" headers (which change on rebase)
+func NormalizeDiff(diff string) string {
+	lines := strings.Split(strings.ReplaceAll(diff, "\r\n", "\n"), "\n")
+	normalized := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if strings.HasPrefix(line, "index ") {
+			continue
+		}
+		normalized = append(normalized, strings.TrimRight(line, " \t"))
+	}
+	return strings.TrimRight(strings.Join(normalized, "\n"), "\n")
+}
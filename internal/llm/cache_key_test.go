@@ -0,0 +1,40 @@
+package llm
+
+import "testing"
+
+func TestResponseCacheKey(t *testing.T) {
+	base := BuildPromptData("diff --git a/x.go b/x.go\nindex 1a2b..3c4d 100644\n+fmt.Println(1)\n", "Fix", "alice", "feature", "main")
+	tmpl := "Review {{.MRTitle}}:\n{{.Diff}}"
+	key := ResponseCacheKey(1, "gpt-4", tmpl, base)
+
+	rebased := base
+	rebased.Diff = "diff --git a/x.go b/x.go\r\nindex 9f9f..8e8e 100644\r\n+fmt.Println(1)  \r\n"
+
+	changed := base
+	changed.Diff = "diff --git a/x.go b/x.go\n+fmt.Println(2)\n"
+
+	retitled := base
+	retitled.MRTitle = "Fix bug"
+
+	tests := []struct {
+		name     string
+		key      string
+		expected bool // Same key as the base review
+	}{
+		{"Same inputs", ResponseCacheKey(1, "gpt-4", tmpl, base), true},
+		{"Rebased with formatting noise", ResponseCacheKey(1, "gpt-4", tmpl, rebased), true},
+		{"Different diff", ResponseCacheKey(1, "gpt-4", tmpl, changed), false},
+		{"Different prompt", ResponseCacheKey(1, "gpt-4", "", base), false},
+		{"Different rendered title", ResponseCacheKey(1, "gpt-4", tmpl, retitled), false},
+		{"Different model", ResponseCacheKey(1, "gpt-4o", tmpl, base), false},
+		{"Different provider", ResponseCacheKey(2, "gpt-4", tmpl, base), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.key == key; got != tt.expected {
+				t.Errorf("Key match = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
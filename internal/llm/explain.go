@@ -0,0 +1,33 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BuildExplanationPrompt asks for an in-depth explanation of one finding, for a
+// developer who asked about it in the MR thread
+func BuildExplanationPrompt(diff string, f FixSuggestion) string {
+	var sb strings.Builder
+	sb.WriteString("A code reviewer reported the issue below on a merge request, and the author " +
+		"asked for a deeper explanation.\n\n")
+
+	sb.WriteString(fmt.Sprintf("- File: %s\n", f.FilePath))
+	if f.LineStart > 0 {
+		sb.WriteString(fmt.Sprintf("- Lines: %d-%d\n", f.LineStart, max(f.LineStart, f.LineEnd)))
+	}
+	sb.WriteString(fmt.Sprintf("- Severity: %s\n- Category: %s\n- Issue: %s\n", f.Severity, f.Category, f.Description))
+	if f.Suggestion != "" {
+		sb.WriteString(fmt.Sprintf("- Suggested fix: %s\n", f.Suggestion))
+	}
+	sb.WriteString("\nCode context:\n```diff\n")
+	sb.WriteString(FindingContext(diff, f))
+	sb.WriteString("\n```\n\n")
+
+	sb.WriteString(`Explain, in Markdown and in under 300 words:
+1. Why this is a problem, with a concrete scenario where it goes wrong
+2. How to fix it, with a short code example
+If the code shown does not actually have this problem, say so plainly.
+Respond with the explanation only, not JSON.`)
+	return sb.String()
+}
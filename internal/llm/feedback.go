@@ -0,0 +1,25 @@
+package llm
+
+import "strings"
+
+// commentSeverities is the order findings are listed in the MR comment
+var commentSeverities = []string{"critical", "high", "medium", "low"}
+
+// NumberSuggestions numbers the findings shown in the MR comment (#1, #2, ...) in
+// the order they are listed, so developers can refer to them in replies.
+// Findings left out of the comment keep number 0.
+func NumberSuggestions(suggestions []FixSuggestion) {
+	for i := range suggestions {
+		suggestions[i].Number = 0
+	}
+
+	n := 0
+	for _, severity := range commentSeverities {
+		for i := range suggestions {
+			if strings.EqualFold(suggestions[i].Severity, severity) && !suggestions[i].Rejected() {
+				n++
+				suggestions[i].Number = n
+			}
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package llm
+
+import "testing"
+
+func TestNumberSuggestions(t *testing.T) {
+	suggestions := []FixSuggestion{
+		{Severity: "low"},
+		{Severity: "critical"},
+		{Severity: "high", Verdict: "rejected"}, // Not in the comment
+		{Severity: "medium"},
+		{Severity: "high"},
+	}
+
+	NumberSuggestions(suggestions)
+
+	// Numbered in comment order: critical, high, medium, low
+	want := []int{4, 1, 0, 3, 2}
+	for i, s := range suggestions {
+		if s.Number != want[i] {
+			t.Errorf("Suggestion %d (%s): expected #%d, got #%d", i, s.Severity, want[i], s.Number)
+		}
+	}
+}
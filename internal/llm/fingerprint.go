@@ -0,0 +1,100 @@
+package llm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// maxSimHashDistance is the most bits two description hashes may differ in for
+// the findings to count as the same issue reworded. Descriptions are short, so one
+// changed word flips many bits; unrelated texts differ in about 32.
+const maxSimHashDistance = 16
+
+// Fingerprint identifies a finding across the commits of a merge request by its
+// file, category and quoted code (its wording when no code was quoted). Line
+// numbers are left out because they move as the branch changes.
+func Fingerprint(s FixSuggestion) string {
+	text := s.CodeSnippet
+	if strings.TrimSpace(text) == "" {
+		text = s.Description
+	}
+	normalized := strings.Join(strings.Fields(strings.ToLower(text)), " ")
+
+	sum := sha256.Sum256([]byte(s.FilePath + "\x00" + strings.ToLower(s.Category) + "\x00" + normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// stopWords carry no meaning for telling findings apart
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "that": true, "this": true, "with": true,
+	"which": true, "are": true, "was": true, "not": true, "can": true, "may": true,
+	"into": true, "from": true, "when": true, "should": true, "could": true, "its": true,
+}
+
+// SimHash returns a 64-bit similarity hash of the text's significant words, as hex.
+// Texts that share most of their words get hashes differing in few bits, so a
+// finding the model reworded on the next commit still matches.
+func SimHash(text string) string {
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_')
+	}) {
+		if len(word) >= 3 && !stopWords[word] {
+			words = append(words, stem(word))
+		}
+	}
+	if len(words) == 0 {
+		return ""
+	}
+
+	var weights [64]int
+	add := func(feature string) {
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		sum := h.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if sum&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+	for _, word := range words {
+		add(word)
+	}
+
+	var hash uint64
+	for bit, weight := range weights {
+		if weight > 0 {
+			hash |= 1 << bit
+		}
+	}
+	return fmt.Sprintf("%016x", hash)
+}
+
+// stem strips common English suffixes so "allowing" and "allows" count as one word
+func stem(word string) string {
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		if len(word) > len(suffix)+3 && strings.HasSuffix(word, suffix) {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
+
+// SimilarHashes reports whether two SimHash values are close enough for their
+// texts to describe the same issue
+func SimilarHashes(a, b string) bool {
+	x, errA := strconv.ParseUint(a, 16, 64)
+	y, errB := strconv.ParseUint(b, 16, 64)
+	if a == "" || b == "" || errA != nil || errB != nil {
+		return false
+	}
+	return bits.OnesCount64(x^y) <= maxSimHashDistance
+}
@@ -0,0 +1,43 @@
+package llm
+
+import "testing"
+
+func TestFingerprint(t *testing.T) {
+	base := FixSuggestion{FilePath: "db.go", LineStart: 10, Category: "security",
+		Description: "SQL injection", CodeSnippet: "db.Query(\"SELECT \" + id)"}
+
+	moved := base
+	moved.LineStart, moved.Description = 42, "Query built from user input"
+	moved.CodeSnippet = "  db.Query(\"SELECT \"  + id)\n"
+	if Fingerprint(base) != Fingerprint(moved) {
+		t.Error("Expected the same fingerprint when only lines, wording and whitespace change")
+	}
+
+	otherFile := base
+	otherFile.FilePath = "api.go"
+	if Fingerprint(base) == Fingerprint(otherFile) {
+		t.Error("Expected a different fingerprint for another file")
+	}
+}
+
+func TestSimilarHashes(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{"same words", "SQL query built by concatenating user input, allowing SQL injection",
+			"SQL query is built by concatenating user input, which allows SQL injection", true},
+		{"reworded", "Error returned by Close is ignored", "The error returned by Close is not checked", true},
+		{"unrelated", "Error returned by Close is ignored", "Loop allocates a new buffer on every iteration", false},
+		{"empty", "", "Error returned by Close is ignored", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SimilarHashes(SimHash(tt.a), SimHash(tt.b)); got != tt.want {
+				t.Errorf("SimilarHashes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package llm
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxTurnLength caps one message of the conversation in the follow-up prompt;
+// the bot's review comment can be long
+const maxTurnLength = 4000
+
+var findingReference = regexp.MustCompile(`#(\d+)\b`)
+
+// ConversationTurn is one comment of a review thread
+type ConversationTurn struct {
+	Author   string
+	Reviewer bool // Written by HandsOff
+	Body     string
+}
+
+// FindingReference returns the last finding number (#N) mentioned in the text, or 0
+func FindingReference(text string) int {
+	matches := findingReference.FindAllStringSubmatch(text, -1)
+	if len(matches) == 0 {
+		return 0
+	}
+	n, _ := strconv.Atoi(matches[len(matches)-1][1])
+	return n
+}
+
+// BuildFollowUpPrompt asks for the reviewer's next reply in a thread where a developer
+// questioned or pushed back on the review. finding is nil when the thread is about
+// the review as a whole.
+func BuildFollowUpPrompt(diff string, turns []ConversationTurn, finding *FixSuggestion) string {
+	var sb strings.Builder
+	sb.WriteString("You are the code reviewer of a merge request. A developer replied to your review; " +
+		"answer them.\n\n")
+
+	if finding != nil {
+		sb.WriteString("## Finding under discussion\n")
+		sb.WriteString(fmt.Sprintf("- File: %s\n", finding.FilePath))
+		if finding.LineStart > 0 {
+			sb.WriteString(fmt.Sprintf("- Lines: %d-%d\n", finding.LineStart, max(finding.LineStart, finding.LineEnd)))
+		}
+		sb.WriteString(fmt.Sprintf("- Severity: %s\n- Category: %s\n- Issue: %s\n",
+			finding.Severity, finding.Category, finding.Description))
+		if finding.Suggestion != "" {
+			sb.WriteString(fmt.Sprintf("- Suggested fix: %s\n", finding.Suggestion))
+		}
+		sb.WriteString("\nCode context:\n```diff\n")
+		sb.WriteString(FindingContext(diff, *finding))
+		sb.WriteString("\n```\n\n")
+	}
+
+	sb.WriteString("## Conversation\n")
+	for _, turn := range turns {
+		role := "Developer"
+		if turn.Reviewer {
+			role = "You (reviewer)"
+		}
+		body := turn.Body
+		if len(body) > maxTurnLength {
+			body = body[:maxTurnLength] + "\n..."
+		}
+		sb.WriteString(fmt.Sprintf("### %s (@%s)\n%s\n\n", role, turn.Author, body))
+	}
+
+	sb.WriteString(`## Response
+Reply to the developer's last message in Markdown, in under 250 words. Answer their question
+directly. If they show the finding is wrong, agree and say so; if it still stands, explain why
+with reference to the code. Respond with the reply only, not JSON.`)
+	return sb.String()
+}
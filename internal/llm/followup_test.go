@@ -0,0 +1,48 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindingReference(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"Why is #2 a problem?", 2},
+		{"#1 is fine, but what about #3?", 3},
+		{"I don't think this applies here", 0},
+		{"See issue #12a", 0},
+	}
+
+	for _, tt := range tests {
+		if got := FindingReference(tt.text); got != tt.want {
+			t.Errorf("FindingReference(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestBuildFollowUpPrompt(t *testing.T) {
+	diff := "--- a/db.go\n+++ b/db.go\n@@ -1,1 +1,2 @@\n package db\n+q := \"SELECT \" + id\n"
+	finding := FixSuggestion{FilePath: "db.go", LineStart: 2, Severity: "high", Category: "security", Description: "SQL injection"}
+	turns := []ConversationTurn{
+		{Author: "handsoff-bot", Reviewer: true, Body: strings.Repeat("x", maxTurnLength+10)},
+		{Author: "alice", Body: "id is an integer, so #1 cannot be exploited"},
+	}
+
+	prompt := BuildFollowUpPrompt(diff, turns, &finding)
+	for _, want := range []string{"SQL injection", `+q := "SELECT " + id`, "### You (reviewer) (@handsoff-bot)",
+		"### Developer (@alice)\nid is an integer"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt does not contain %q", want)
+		}
+	}
+	if strings.Contains(prompt, strings.Repeat("x", maxTurnLength+1)) {
+		t.Error("Expected long turns to be truncated")
+	}
+
+	if general := BuildFollowUpPrompt("", turns, nil); strings.Contains(general, "Finding under discussion") {
+		t.Error("Expected no finding section without a finding")
+	}
+}
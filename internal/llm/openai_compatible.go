@@ -2,10 +2,12 @@ package llm
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 	
 	"github.com/handsoff/handsoff/pkg/logger"
@@ -72,6 +74,12 @@ type compatibleResponse struct {
 
 // Review performs code review using OpenAI-compatible API
 func (c *OpenAICompatibleClient) Review(req ReviewRequest) (*ReviewResponse, error) {
+	return c.ReviewContext(context.Background(), req)
+}
+
+// ReviewContext performs code review using OpenAI-compatible API.
+// Cancelling ctx aborts the in-flight HTTP request.
+func (c *OpenAICompatibleClient) ReviewContext(ctx context.Context, req ReviewRequest) (*ReviewResponse, error) {
 	start := time.Now()
 	
 	// Log request start
@@ -110,7 +118,7 @@ func (c *OpenAICompatibleClient) Review(req ReviewRequest) (*ReviewResponse, err
 	}
 
 	// Create HTTP request
-	httpReq, err := http.NewRequest("POST", c.config.BaseURL+"/chat/completions", bytes.NewBuffer(reqBody))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.config.BaseURL+"/chat/completions", bytes.NewBuffer(reqBody))
 	if err != nil {
 		c.log.Error("Failed to create HTTP request",
 			"provider", c.providerName,
@@ -128,12 +136,16 @@ func (c *OpenAICompatibleClient) Review(req ReviewRequest) (*ReviewResponse, err
 	apiCallDuration := time.Since(apiCallStart)
 	
 	if err != nil {
+		status := "network_error"
+		if ctx.Err() != nil {
+			status = "cancelled"
+		}
 		c.log.Error("LLM API request failed",
 			"provider", c.providerName,
 			"model", c.config.ModelName,
 			"duration_ms", apiCallDuration.Milliseconds(),
 			"error", err,
-			"status", "network_error",
+			"status", status,
 		)
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -151,6 +163,31 @@ func (c *OpenAICompatibleClient) Review(req ReviewRequest) (*ReviewResponse, err
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
+	// Provider throttling: surface Retry-After so the caller can back off. An exhausted
+	// quota comes as a 429 too, but waiting does not help: it fails like other errors.
+	if resp.StatusCode == http.StatusTooManyRequests && isQuotaExceeded(body) {
+		c.log.Error("LLM API quota exceeded",
+			"provider", c.providerName,
+			"model", c.config.ModelName,
+			"status", "quota_exceeded",
+		)
+		return nil, fmt.Errorf("%s quota exceeded: %s", c.providerName, errorMessage(body))
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		rateErr := &RateLimitError{
+			Provider:   c.providerName,
+			RetryAfter: ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+			Message:    errorMessage(body),
+		}
+		c.log.Warn("LLM API rate limited",
+			"provider", c.providerName,
+			"model", c.config.ModelName,
+			"retry_after", rateErr.RetryAfter,
+			"status", "rate_limited",
+		)
+		return nil, rateErr
+	}
+
 	// Parse response
 	var apiResp compatibleResponse
 	if err := json.Unmarshal(body, &apiResp); err != nil {
@@ -240,6 +277,11 @@ func (c *OpenAICompatibleClient) Review(req ReviewRequest) (*ReviewResponse, err
 
 // TestConnection tests API connectivity
 func (c *OpenAICompatibleClient) TestConnection() error {
+	return c.TestConnectionContext(context.Background())
+}
+
+// TestConnectionContext tests API connectivity, aborting when ctx is done
+func (c *OpenAICompatibleClient) TestConnectionContext(ctx context.Context) error {
 	req := compatibleRequest{
 		Model: c.config.ModelName,
 		Messages: []compatibleMessage{
@@ -256,7 +298,7 @@ func (c *OpenAICompatibleClient) TestConnection() error {
 		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequest("POST", c.config.BaseURL+"/chat/completions", bytes.NewBuffer(reqBody))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.config.BaseURL+"/chat/completions", bytes.NewBuffer(reqBody))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -278,6 +320,20 @@ func (c *OpenAICompatibleClient) TestConnection() error {
 	return nil
 }
 
+// errorMessage extracts the provider's error message from a response body
+func errorMessage(body []byte) string {
+	var apiResp compatibleResponse
+	if err := json.Unmarshal(body, &apiResp); err == nil && apiResp.Error != nil {
+		return apiResp.Error.Message
+	}
+
+	msg := strings.TrimSpace(string(body))
+	if len(msg) > 200 {
+		msg = msg[:200] + "..."
+	}
+	return msg
+}
+
 // GetProviderName returns the provider name
 func (c *OpenAICompatibleClient) GetProviderName() string {
 	return c.providerName
@@ -0,0 +1,84 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReviewContext_CancelAbortsRequest(t *testing.T) {
+	// Provider that never answers within the test
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewOpenAICompatibleClient("test", Config{
+		BaseURL:   server.URL,
+		ModelName: "test-model",
+		Timeout:   60,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.ReviewContext(ctx, ReviewRequest{Prompt: "review"})
+	if err == nil {
+		t.Fatal("Expected an error from a cancelled request")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Request was not aborted by the context (took %v)", elapsed)
+	}
+}
+
+func TestReviewContext_RateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "12")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":{"message":"Rate limit reached for requests","type":"requests"}}`))
+	}))
+	defer server.Close()
+
+	client := NewOpenAICompatibleClient("test", Config{BaseURL: server.URL, ModelName: "test-model", Timeout: 5})
+
+	_, err := client.ReviewContext(context.Background(), ReviewRequest{Prompt: "review"})
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("Expected *RateLimitError, got %v", err)
+	}
+	if rateErr.RetryAfter != 12*time.Second {
+		t.Errorf("Expected RetryAfter 12s, got %v", rateErr.RetryAfter)
+	}
+	if rateErr.Message != "Rate limit reached for requests" || rateErr.Local {
+		t.Errorf("Unexpected rate limit error: %+v", rateErr)
+	}
+}
+
+func TestReviewContext_QuotaExceeded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":{"message":"You exceeded your current quota","type":"insufficient_quota","code":"insufficient_quota"}}`))
+	}))
+	defer server.Close()
+
+	client := NewOpenAICompatibleClient("test", Config{BaseURL: server.URL, ModelName: "test-model", Timeout: 5})
+
+	// Waiting does not help: the task must fail and use up its retries
+	_, err := client.ReviewContext(context.Background(), ReviewRequest{Prompt: "review"})
+	var rateErr *RateLimitError
+	if err == nil || errors.As(err, &rateErr) {
+		t.Fatalf("Expected a plain error for an exhausted quota, got %v", err)
+	}
+}
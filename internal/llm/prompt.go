@@ -1,6 +1,8 @@
 package llm
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"strings"
 )
@@ -54,14 +56,28 @@ type PromptData struct {
 	SourceBranch  string
 	TargetBranch  string
 	CommitMessage string
+
+	// Static analyzer findings on the changed lines, one per line ("" = none)
+	AnalyzerFindings string
 }
 
+// analyzerSection introduces static analyzer findings in templates that do not place them
+const analyzerSection = `## Static Analyzer Findings
+Static analyzers reported these issues on the changed lines. They are already part of the review results:
+do not report them again, but take them into account (e.g. for related problems they point to).
+{{.AnalyzerFindings}}`
+
 // RenderPrompt renders the prompt template with data
 func RenderPrompt(template string, data PromptData) string {
 	if template == "" {
 		template = DefaultPromptTemplate
 	}
 
+	// Templates without the placeholder get analyzer findings right after the diff
+	if data.AnalyzerFindings != "" && !strings.Contains(template, "{{.AnalyzerFindings}}") {
+		template = strings.Replace(template, "{{.Diff}}", "{{.Diff}}\n\n"+analyzerSection, 1)
+	}
+
 	// Simple template variable replacement
 	result := template
 	result = strings.ReplaceAll(result, "{{.Diff}}", data.Diff)
@@ -70,6 +86,7 @@ func RenderPrompt(template string, data PromptData) string {
 	result = strings.ReplaceAll(result, "{{.SourceBranch}}", data.SourceBranch)
 	result = strings.ReplaceAll(result, "{{.TargetBranch}}", data.TargetBranch)
 	result = strings.ReplaceAll(result, "{{.CommitMessage}}", data.CommitMessage)
+	result = strings.ReplaceAll(result, "{{.AnalyzerFindings}}", data.AnalyzerFindings)
 
 	return result
 }
@@ -88,6 +105,27 @@ func ValidatePromptTemplate(template string) error {
 	return nil
 }
 
+// FocusPrompt narrows a prompt template to one category of issues, for a
+// reviewer profile whose pass runs alongside others covering the rest
+func FocusPrompt(template, category string) string {
+	if template == "" {
+		template = DefaultPromptTemplate
+	}
+	return fmt.Sprintf("You are the %[1]s reviewer in a team of specialized reviewers. "+
+		"Report only %[1]s issues and set \"category\" to \"%[1]s\" on every suggestion; "+
+		"the other reviewers cover everything else.\n\n", category) + template
+}
+
+// PromptVersion identifies a prompt template by content, so feedback statistics
+// can tell prompt revisions apart without relying on a hand-maintained version
+func PromptVersion(template string) string {
+	if template == "" {
+		template = DefaultPromptTemplate
+	}
+	sum := sha256.Sum256([]byte(template))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
 // GetDefaultPrompt returns the default prompt template
 func GetDefaultPrompt() string {
 	return DefaultPromptTemplate
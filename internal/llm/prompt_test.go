@@ -0,0 +1,29 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderPromptAnalyzerFindings(t *testing.T) {
+	findings := "- main.go:7 [go vet] bad format\n"
+
+	// Without a placeholder the findings follow the diff
+	prompt := RenderPrompt(DefaultPromptTemplate, PromptData{Diff: "+x", AnalyzerFindings: findings})
+	diffAt := strings.Index(prompt, "+x")
+	sectionAt := strings.Index(prompt, "## Static Analyzer Findings")
+	if diffAt < 0 || sectionAt < diffAt || !strings.Contains(prompt, findings) {
+		t.Errorf("findings not placed after the diff:\n%s", prompt)
+	}
+
+	// A template with the placeholder places them itself
+	prompt = RenderPrompt("Lint: {{.AnalyzerFindings}}\n{{.Diff}}", PromptData{Diff: "+x", AnalyzerFindings: findings})
+	if prompt != "Lint: "+findings+"\n+x" {
+		t.Errorf("RenderPrompt() = %q", prompt)
+	}
+
+	// No findings, no section
+	if prompt := RenderPrompt(DefaultPromptTemplate, PromptData{Diff: "+x"}); strings.Contains(prompt, "Static Analyzer") {
+		t.Error("empty findings should not add a section")
+	}
+}
@@ -0,0 +1,92 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/pkg/ratelimit"
+)
+
+// RateLimitError is returned when a request is throttled, either by the provider
+// (HTTP 429) or by the configured per-provider limits
+type RateLimitError struct {
+	Provider   string
+	RetryAfter time.Duration // How long to wait before retrying (0 if unknown)
+	Message    string
+	Local      bool // Rejected by the configured limits; the request was never sent
+}
+
+func (e *RateLimitError) Error() string {
+	msg := fmt.Sprintf("%s rate limited", e.Provider)
+	if e.RetryAfter > 0 {
+		msg += fmt.Sprintf(", retry after %s", e.RetryAfter)
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// quotaErrorCodes are the error codes or types of 429 responses that reject requests
+// over a billing quota rather than throttle them
+var quotaErrorCodes = []string{"insufficient_quota", "billing_hard_limit_reached", "quota_exceeded"}
+
+// isQuotaExceeded reports whether a 429 response body rejects the request over quota
+func isQuotaExceeded(body []byte) bool {
+	var apiResp compatibleResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil || apiResp.Error == nil {
+		return false
+	}
+	for _, code := range quotaErrorCodes {
+		if apiResp.Error.Code == code || apiResp.Error.Type == code {
+			return true
+		}
+	}
+	return false
+}
+
+// ProviderRateLimitKey returns the limiter key shared by every client of the provider
+func ProviderRateLimitKey(provider *model.LLMProvider) string {
+	return fmt.Sprintf("llm_provider:%d", provider.ID)
+}
+
+// ProviderLimits returns the provider's configured rate limits
+func ProviderLimits(provider *model.LLMProvider) ratelimit.Limits {
+	return ratelimit.Limits{
+		RequestsPerMinute: provider.RPMLimit,
+		TokensPerMinute:   provider.TPMLimit,
+	}
+}
+
+// EstimateTokens estimates what a request counts against a tokens-per-minute limit:
+// the prompt (about 4 characters per token) plus the completion budget.
+// Providers reserve max_tokens up front as well, so this matches how they throttle.
+func EstimateTokens(req ReviewRequest) int {
+	return (len(req.Prompt)+3)/4 + req.MaxTokens
+}
+
+// ParseRetryAfter parses a Retry-After header, given either in seconds or as an HTTP date.
+// Returns 0 when the header is missing or invalid.
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds * float64(time.Second))
+	}
+
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,40 @@
+package llm
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{"Seconds", "30", 30 * time.Second},
+		{"Fractional seconds", "1.5", 1500 * time.Millisecond},
+		{"HTTP date", now.Add(45 * time.Second).Format(http.TimeFormat), 45 * time.Second},
+		{"Date in the past", now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"Zero", "0", 0},
+		{"Empty", "", 0},
+		{"Invalid", "soon", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseRetryAfter(tt.value, now); got != tt.expected {
+				t.Errorf("ParseRetryAfter(%q) = %v, want %v", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	req := ReviewRequest{Prompt: "0123456789", MaxTokens: 100}
+	if got := EstimateTokens(req); got != 103 {
+		t.Errorf("EstimateTokens() = %d, want 103", got)
+	}
+}
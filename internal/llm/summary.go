@@ -0,0 +1,56 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxSummaryCommits caps the commits listed in the MR summary prompt
+const maxSummaryCommits = 50
+
+// DefaultMRSummaryPromptTemplate is the default prompt for generated merge request summaries
+const DefaultMRSummaryPromptTemplate = `Write a description for the merge request below, for the reviewers who will read it before the code.
+
+## Merge Request
+- Title: {{.MRTitle}}
+- Author: {{.MRAuthor}}
+- Branches: {{.SourceBranch}} -> {{.TargetBranch}}
+
+## Commits
+{{.CommitMessage}}
+
+## Code Changes (Git Diff)
+{{.Diff}}
+
+## Format
+Respond in Markdown with exactly these sections, each a few short bullet points:
+### What changed
+### Why it matters
+### Risk areas
+### Testing notes
+
+Describe only what the diff and commits show; do not guess at intent they do not support.
+Do not review the code or suggest changes. Do not wrap the answer in a code block.`
+
+// SummaryCommit is a commit of the merge request, as listed in the MR summary prompt
+type SummaryCommit struct {
+	ShortID string
+	Title   string
+}
+
+// FormatCommitLog lists commits one per line, oldest first, for {{.CommitMessage}}
+func FormatCommitLog(commits []SummaryCommit) string {
+	if len(commits) == 0 {
+		return "(no commits)"
+	}
+
+	var sb strings.Builder
+	for i, c := range commits {
+		if i == maxSummaryCommits {
+			sb.WriteString(fmt.Sprintf("- ... and %d more\n", len(commits)-maxSummaryCommits))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("- %s %s\n", c.ShortID, c.Title))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,33 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatCommitLog(t *testing.T) {
+	if got := FormatCommitLog(nil); got != "(no commits)" {
+		t.Errorf("FormatCommitLog(nil) = %q", got)
+	}
+
+	commits := []SummaryCommit{{ShortID: "a1b2c3d4", Title: "Add cache"}, {ShortID: "e5f6a7b8", Title: "Fix tests"}}
+	if got, want := FormatCommitLog(commits), "- a1b2c3d4 Add cache\n- e5f6a7b8 Fix tests"; got != want {
+		t.Errorf("FormatCommitLog() = %q, want %q", got, want)
+	}
+
+	many := make([]SummaryCommit, maxSummaryCommits+5)
+	if got := FormatCommitLog(many); !strings.HasSuffix(got, "- ... and 5 more") {
+		t.Errorf("FormatCommitLog() of %d commits does not end with the remainder: %q", len(many), got[len(got)-30:])
+	}
+}
+
+func TestDefaultMRSummaryPromptTemplate(t *testing.T) {
+	if err := ValidatePromptTemplate(DefaultMRSummaryPromptTemplate); err != nil {
+		t.Fatalf("default MR summary template is invalid: %v", err)
+	}
+
+	prompt := RenderPrompt(DefaultMRSummaryPromptTemplate, PromptData{Diff: "+x", MRTitle: "Add cache", CommitMessage: "- a1 Add cache"})
+	if strings.Contains(prompt, "{{.") {
+		t.Errorf("rendered prompt has unreplaced placeholders:\n%s", prompt)
+	}
+}
@@ -1,6 +1,9 @@
 package llm
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // ReviewRequest represents a code review request
 type ReviewRequest struct {
@@ -20,6 +23,9 @@ type ReviewResponse struct {
 	ModelUsed   string           `json:"model_used"`   // Model that generated this
 	TokensUsed  int              `json:"tokens_used"`  // Tokens consumed (deprecated, use TokenUsage)
 	Duration    time.Duration    `json:"duration"`     // Time taken
+	Cached      bool             `json:"-"`            // Served from the response cache
+	Progress    *IssueProgress   `json:"-"`            // Change since the previous review, set when saved
+	Reviewers   []ReviewerSuggestion `json:"-"`        // Recommended human reviewers (reviewer recommendation on)
 
 	// Detailed Token Usage (for operations analytics)
 	TokenUsage TokenUsage `json:"token_usage"`
@@ -32,6 +38,19 @@ type TokenUsage struct {
 	TotalTokens      int `json:"total_tokens"`
 }
 
+// ReviewerSuggestion is a human reviewer recommended for the merge request
+type ReviewerSuggestion struct {
+	Username string
+	Reason   string // e.g. "code owner of 2 changed files"
+}
+
+// IssueProgress compares a review's findings with the previous review of the merge request
+type IssueProgress struct {
+	New        int // Reported for the first time, or again after being resolved
+	Persisting int // Also reported by the previous review
+	Resolved   int // Reported before, gone now
+}
+
 // FixSuggestion represents a single code fix suggestion
 type FixSuggestion struct {
 	FilePath    string `json:"file_path"`    // File path
@@ -42,15 +61,32 @@ type FixSuggestion struct {
 	Description string `json:"description"`  // Issue description
 	Suggestion  string `json:"suggestion"`   // Fix recommendation
 	CodeSnippet string `json:"code_snippet"` // Original code snippet
+
+	// Consensus mode: set when a second model verified the finding
+	Verdict           string  `json:"verdict,omitempty"`            // confirmed, rejected, downgraded
+	VerdictConfidence float64 `json:"verdict_confidence,omitempty"` // 0-1
+	VerdictReason     string  `json:"verdict_reason,omitempty"`
+
+	// Set by the review worker
+	Number        int    `json:"number,omitempty"`         // Position in the MR comment (#N)
+	ModelName     string `json:"model_name,omitempty"`     // Model that reported the finding
+	PromptVersion string `json:"prompt_version,omitempty"` // See PromptVersion
+	Source        string `json:"-"`                        // Analyzer or builtin check that reported it ("" = the LLM)
 }
 
 // Client interface for LLM providers
 type Client interface {
 	// Review performs code review using LLM
 	Review(req ReviewRequest) (*ReviewResponse, error)
+
+	// ReviewContext performs code review; the HTTP request is aborted when ctx is done
+	ReviewContext(ctx context.Context, req ReviewRequest) (*ReviewResponse, error)
 	
 	// TestConnection tests the LLM API connection
 	TestConnection() error
+
+	// TestConnectionContext tests the LLM API connection, aborting when ctx is done
+	TestConnectionContext(ctx context.Context) error
 	
 	// GetProviderName returns the provider name
 	GetProviderName() string
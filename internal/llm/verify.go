@@ -0,0 +1,156 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/handsoff/handsoff/internal/model"
+)
+
+// maxContextLines caps the diff context sent with one finding
+const maxContextLines = 120
+
+var verifyHunkHeader = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// Verdict is the verifier's judgement of one finding
+type Verdict struct {
+	Index      int     `json:"index"`      // 1-based position of the finding in the prompt
+	Verdict    string  `json:"verdict"`    // confirmed, rejected
+	Confidence float64 `json:"confidence"` // 0-1
+	Reason     string  `json:"reason"`
+}
+
+// NeedsVerification reports whether a finding is severe enough to be cross-checked
+func NeedsVerification(s FixSuggestion) bool {
+	return s.Severity == "critical" || s.Severity == "high"
+}
+
+// Rejected reports whether the verifier rejected the finding; rejected findings
+// are stored but left out of the MR comment and the review's issue counts
+func (s FixSuggestion) Rejected() bool {
+	return s.Verdict == model.VerdictRejected
+}
+
+// BuildVerificationPrompt asks a second model to confirm or reject each finding,
+// showing it the diff hunks the finding points at
+func BuildVerificationPrompt(diff string, findings []FixSuggestion) string {
+	var sb strings.Builder
+	sb.WriteString("Another code reviewer reported the issues below on a merge request. " +
+		"Check each one against its code and decide whether it is a real problem.\n\n")
+
+	for i, f := range findings {
+		sb.WriteString(fmt.Sprintf("## Finding %d\n", i+1))
+		sb.WriteString(fmt.Sprintf("- File: %s\n", f.FilePath))
+		if f.LineStart > 0 {
+			sb.WriteString(fmt.Sprintf("- Lines: %d-%d\n", f.LineStart, max(f.LineStart, f.LineEnd)))
+		}
+		sb.WriteString(fmt.Sprintf("- Severity: %s\n- Category: %s\n- Issue: %s\n", f.Severity, f.Category, f.Description))
+		if f.Suggestion != "" {
+			sb.WriteString(fmt.Sprintf("- Suggested fix: %s\n", f.Suggestion))
+		}
+		sb.WriteString("\nCode context:\n```diff\n")
+		sb.WriteString(FindingContext(diff, f))
+		sb.WriteString("\n```\n\n")
+	}
+
+	sb.WriteString(`## Response
+Reject findings that are wrong, already handled in the shown code, or too speculative to act on.
+Respond ONLY with valid JSON in this format:
+{
+  "verdicts": [
+    {"index": 1, "verdict": "confirmed", "confidence": 0.9, "reason": "One sentence"}
+  ]
+}
+"verdict" is "confirmed" or "rejected"; "confidence" is between 0 and 1.`)
+	return sb.String()
+}
+
+// ParseVerdicts parses the verifier's response. Verdicts other than confirmed or
+// rejected, and indexes outside 1..count, are dropped.
+func ParseVerdicts(content string, count int) ([]Verdict, error) {
+	var parsed struct {
+		Verdicts []Verdict `json:"verdicts"`
+	}
+	if err := json.Unmarshal([]byte(extractJSONFromMarkdown(content)), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse verdicts: %w", err)
+	}
+
+	verdicts := make([]Verdict, 0, len(parsed.Verdicts))
+	for _, v := range parsed.Verdicts {
+		v.Verdict = strings.ToLower(strings.TrimSpace(v.Verdict))
+		if v.Index < 1 || v.Index > count {
+			continue
+		}
+		if v.Verdict != model.VerdictConfirmed && v.Verdict != model.VerdictRejected {
+			continue
+		}
+		if v.Confidence < 0 {
+			v.Confidence = 0
+		}
+		if v.Confidence > 1 {
+			v.Confidence = 1
+		}
+		verdicts = append(verdicts, v)
+	}
+	return verdicts, nil
+}
+
+// FindingContext returns the hunks of a finding's file that overlap its lines,
+// or the file's whole diff when the finding has no lines or none overlap
+func FindingContext(diff string, f FixSuggestion) string {
+	var file, hunks []string
+	var hunk []string
+	hunkStart, hunkEnd := 0, 0
+	inFile := false
+
+	flush := func() {
+		if len(hunk) > 0 && f.LineStart > 0 && hunkStart <= max(f.LineStart, f.LineEnd) && f.LineStart <= hunkEnd {
+			hunks = append(hunks, hunk...)
+		}
+		hunk = nil
+	}
+
+	lines := strings.Split(diff, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ ") {
+			flush()
+			if inFile {
+				break
+			}
+			path := strings.TrimPrefix(strings.TrimPrefix(lines[i+1], "+++ "), "b/")
+			inFile = path == f.FilePath
+			continue
+		}
+		if !inFile || strings.HasPrefix(line, "+++ ") {
+			continue
+		}
+
+		file = append(file, line)
+		if m := verifyHunkHeader.FindStringSubmatch(line); m != nil {
+			flush()
+			hunkStart, _ = strconv.Atoi(m[1])
+			count := 1
+			if m[2] != "" {
+				count, _ = strconv.Atoi(m[2])
+			}
+			hunkEnd = hunkStart + count - 1
+		}
+		hunk = append(hunk, line)
+	}
+	flush()
+
+	context := hunks
+	if len(context) == 0 {
+		context = file
+	}
+	if len(context) == 0 {
+		return "(no diff for this file)"
+	}
+	if len(context) > maxContextLines {
+		context = append(context[:maxContextLines:maxContextLines], "...")
+	}
+	return strings.Join(context, "\n")
+}
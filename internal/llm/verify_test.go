@@ -0,0 +1,77 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseVerdicts(t *testing.T) {
+	content := "```json\n" + `{"verdicts": [
+		{"index": 1, "verdict": "Confirmed", "confidence": 0.9, "reason": "Query is built from input"},
+		{"index": 2, "verdict": "rejected", "confidence": 1.4, "reason": "Input is validated above"},
+		{"index": 3, "verdict": "unsure", "confidence": 0.5},
+		{"index": 7, "verdict": "rejected", "confidence": 0.8}
+	]}` + "\n```"
+
+	verdicts, err := ParseVerdicts(content, 3)
+	if err != nil {
+		t.Fatalf("ParseVerdicts failed: %v", err)
+	}
+	if len(verdicts) != 2 {
+		t.Fatalf("Expected 2 verdicts (unknown verdict and out of range index dropped), got %+v", verdicts)
+	}
+	if verdicts[0].Verdict != "confirmed" || verdicts[0].Index != 1 {
+		t.Errorf("Unexpected first verdict: %+v", verdicts[0])
+	}
+	if verdicts[1].Verdict != "rejected" || verdicts[1].Confidence != 1 {
+		t.Errorf("Expected rejected verdict with confidence capped at 1, got %+v", verdicts[1])
+	}
+
+	if _, err := ParseVerdicts("I agree with all findings.", 3); err == nil {
+		t.Error("Expected an error for a response without JSON")
+	}
+}
+
+func TestFindingContext(t *testing.T) {
+	diff := strings.Join([]string{
+		"--- a/main.go",
+		"+++ b/main.go",
+		"@@ -1,3 +1,3 @@",
+		" package main",
+		"-var a = 1",
+		"+var a = 2",
+		"@@ -40,2 +40,3 @@ func run() {",
+		" \tdb.Open()",
+		"+\tdb.Query(input)",
+		" }",
+		"--- a/util.go",
+		"+++ b/util.go",
+		"@@ -1 +1 @@",
+		"-package old",
+		"+package util",
+	}, "\n")
+
+	tests := []struct {
+		name     string
+		finding  FixSuggestion
+		contains string
+		excludes string
+	}{
+		{"Overlapping hunk only", FixSuggestion{FilePath: "main.go", LineStart: 41, LineEnd: 41}, "db.Query(input)", "var a = 2"},
+		{"Whole file without lines", FixSuggestion{FilePath: "main.go"}, "var a = 2", "package util"},
+		{"Other file", FixSuggestion{FilePath: "util.go", LineStart: 1}, "+package util", "db.Query"},
+		{"Unknown file", FixSuggestion{FilePath: "missing.go", LineStart: 1}, "no diff for this file", "package"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			context := FindingContext(diff, tt.finding)
+			if !strings.Contains(context, tt.contains) {
+				t.Errorf("Expected context to contain %q, got:\n%s", tt.contains, context)
+			}
+			if strings.Contains(context, tt.excludes) {
+				t.Errorf("Expected context not to contain %q, got:\n%s", tt.excludes, context)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package model
+
+import "time"
+
+// APITokenType distinguishes personal tokens from project (service) tokens
+type APITokenType string
+
+const (
+	APITokenTypePersonal APITokenType = "personal" // Acts on behalf of its creator, visible only to them
+	APITokenTypeProject  APITokenType = "project"  // Shared service identity of a project (CI, scripts)
+)
+
+// API token scopes
+const (
+	ScopeReviewsRead  = "reviews:read"  // Read reviews, statistics and dashboards
+	ScopeReviewsWrite = "reviews:write" // Trigger reviews (implies reviews:read)
+	ScopeAdmin        = "admin"         // Full access to project configuration (implies all scopes)
+)
+
+// ValidScopes lists all scopes that can be granted to an API token
+var ValidScopes = []string{ScopeReviewsRead, ScopeReviewsWrite, ScopeAdmin}
+
+// APITokenPrefix marks HandsOff API tokens so they can be told apart from JWTs
+const APITokenPrefix = "hof_"
+
+// APIToken is a long-lived, scoped credential for CI jobs and scripts.
+// Only the SHA-256 hash of the token is stored; the raw value is shown once at creation.
+type APIToken struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Name        string       `gorm:"not null;size:100" json:"name"`
+	Type        APITokenType `gorm:"not null;size:20;type:varchar(20);default:'personal'" json:"type"`
+	TokenHash   string       `gorm:"not null;uniqueIndex;size:64" json:"-"`
+	TokenPrefix string       `gorm:"size:16" json:"token_prefix"` // First characters of the token, for identification
+	Scopes      []string     `gorm:"type:text;serializer:json" json:"scopes"`
+
+	ExpiresAt  *time.Time `gorm:"index" json:"expires_at"` // nil = never expires
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP string     `gorm:"size:64" json:"last_used_ip"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+
+	// Ownership
+	ProjectID uint `gorm:"not null;index" json:"project_id"` // Token is bound to this project
+	UserID    uint `gorm:"not null;index" json:"user_id"`    // Creator
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName specifies the table name
+func (APIToken) TableName() string {
+	return "api_tokens"
+}
+
+// IsUsable reports whether the token is neither revoked nor expired
+func (t *APIToken) IsUsable(now time.Time) bool {
+	if t.RevokedAt != nil {
+		return false
+	}
+	return t.ExpiresAt == nil || now.Before(*t.ExpiresAt)
+}
+
+// HasScope reports whether the token grants the given scope
+func (t *APIToken) HasScope(scope string) bool {
+	return ScopesGrant(t.Scopes, scope)
+}
+
+// ScopesGrant reports whether a set of scopes grants the given scope.
+// admin implies every scope and reviews:write implies reviews:read.
+func ScopesGrant(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+		if s == ScopeReviewsWrite && scope == ScopeReviewsRead {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// RefreshToken stores a hashed refresh token issued at login.
+// Tokens are single-use: every refresh rotates the token and links the old
+// record to its replacement. All tokens created from the same login share a
+// FamilyID so that reuse of a rotated token can revoke the whole chain.
+type RefreshToken struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID       uint       `gorm:"not null;index" json:"user_id"`
+	TokenHash    string     `gorm:"not null;uniqueIndex;size:64" json:"-"`   // SHA-256 of the raw token
+	FamilyID     string     `gorm:"not null;index;size:64" json:"family_id"` // Shared by all rotations of one login
+	ExpiresAt    time.Time  `gorm:"not null;index" json:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	ReplacedByID *uint      `json:"replaced_by_id"` // Set when rotated
+	UserAgent    string     `gorm:"size:255" json:"user_agent"`
+	ClientIP     string     `gorm:"size:64" json:"client_ip"`
+}
+
+// TableName specifies the table name
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// IsActive reports whether the token can still be exchanged
+func (t *RefreshToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// RevokedToken is a revocation list entry for an access token (by JWT ID).
+// Entries are only needed until the access token would have expired anyway.
+type RevokedToken struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	TokenID   string    `gorm:"not null;uniqueIndex;size:64" json:"token_id"` // JWT "jti" claim
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`
+	Reason    string    `gorm:"size:50" json:"reason"` // logout, password_change, deactivated
+}
+
+// TableName specifies the table name
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
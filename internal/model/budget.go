@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// BudgetMetric is what a budget measures
+type BudgetMetric string
+
+const (
+	BudgetMetricTokens BudgetMetric = "tokens" // Total tokens of LLM calls
+	BudgetMetricCost   BudgetMetric = "cost"   // Cost recorded on LLM usage logs
+)
+
+// Budget state constants
+const (
+	BudgetStateOK       = "ok"
+	BudgetStateWarning  = "warning"  // Soft limit crossed
+	BudgetStateExceeded = "exceeded" // Hard limit reached, reviews are skipped
+)
+
+// Budget limits the monthly LLM usage of a project, or of one repository in it.
+// Usage is summed from llm_usage_logs for the current calendar month.
+type Budget struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	ProjectID    uint  `gorm:"not null;index" json:"project_id"`
+	RepositoryID *uint `gorm:"index" json:"repository_id"` // nil = whole project
+
+	Metric    BudgetMetric `gorm:"not null;size:20;type:varchar(20)" json:"metric"` // tokens, cost
+	SoftLimit float64      `gorm:"not null;default:0" json:"soft_limit"`            // Warn above this (0 = no warning)
+	HardLimit float64      `gorm:"not null;default:0" json:"hard_limit"`            // Skip reviews at this (0 = no limit)
+	Currency  string       `gorm:"size:10" json:"currency"`                         // Display currency of cost budgets
+	IsActive  bool         `gorm:"default:true;not null" json:"is_active"`
+
+	// Month (YYYY-MM) the soft limit warning was last raised, so it is raised once per month
+	WarnedPeriod string `gorm:"size:7" json:"warned_period"`
+
+	// Relationships
+	Repository *Repository `gorm:"foreignKey:RepositoryID;constraint:OnDelete:CASCADE" json:"repository,omitempty"`
+}
+
+// TableName specifies the table name
+func (Budget) TableName() string {
+	return "budgets"
+}
@@ -2,6 +2,34 @@ package model
 
 import "time"
 
+// Verdicts of the second model in consensus mode ("" = not verified)
+const (
+	VerdictConfirmed  = "confirmed"  // The verifier agrees with the finding
+	VerdictRejected   = "rejected"   // The verifier disagrees; hidden from the MR comment
+	VerdictDowngraded = "downgraded" // The verifier disagrees; kept at low severity
+)
+
+// Developer feedback on a suggestion ("" = no feedback yet)
+const (
+	FeedbackAccepted      = "accepted"       // Valid, will be addressed
+	FeedbackFixed         = "fixed"          // Valid and already addressed
+	FeedbackWontFix       = "wont_fix"       // Valid but deliberately left as is
+	FeedbackFalsePositive = "false_positive" // Not an actual issue
+)
+
+// Feedback sources
+const (
+	FeedbackSourceAPI    = "api"    // Set through the HandsOff API or UI
+	FeedbackSourceGitLab = "gitlab" // Replied on the MR comment
+)
+
+// Suggestion sources
+const (
+	SuggestionSourceLLM      = "llm"      // Reported by the review model
+	SuggestionSourceAnalyzer = "analyzer" // Reported by a static analyzer on a changed line
+	SuggestionSourceBuiltin  = "builtin"  // Reported by a builtin check, e.g. secret detection
+)
+
 // FixSuggestion represents a specific fix suggestion
 type FixSuggestion struct {
 	ID             uint      `gorm:"primarykey" json:"id"`
@@ -17,6 +45,37 @@ type FixSuggestion struct {
 	Suggestion     string    `gorm:"type:text" json:"suggestion"`
 	CodeSnippet    string    `gorm:"type:text" json:"code_snippet"` // Original code snippet
 
+	// Consensus mode: verdict of the verifier model on high and critical findings
+	Verdict           string  `gorm:"size:20;index" json:"verdict"` // confirmed, rejected, downgraded ("" = not verified)
+	VerdictConfidence float64 `json:"verdict_confidence"`           // 0-1, as reported by the verifier
+	VerdictReason     string  `gorm:"type:text" json:"verdict_reason"`
+
+	// Identity across reviews and origin, for feedback statistics
+	Number        int    `json:"number"`                              // Position in the MR comment (#N), 0 = not shown
+	Fingerprint   string `gorm:"size:64;index" json:"fingerprint"`    // Stable across commits: file, category and code
+	SimHash       string `gorm:"size:16" json:"sim_hash"`             // Similarity hash of the description, matches reworded findings
+	ModelName     string `gorm:"size:100;index" json:"model_name"`    // Model that reported it ("" = not the LLM)
+	PromptVersion string `gorm:"size:20;index" json:"prompt_version"` // Hash of the prompt template that produced it
+
+	// Who reported it: the LLM, a static analyzer or a builtin check
+	Source string `gorm:"size:20;default:'llm';not null;index" json:"source"`
+
+	// Occurrences across the reviews of the merge request; a finding reported again
+	// on a later commit updates its row instead of adding one (first seen = CreatedAt)
+	FirstSeenSHA string     `gorm:"size:64" json:"first_seen_sha"`
+	LastSeenAt   *time.Time `json:"last_seen_at"`
+	LastSeenSHA  string     `gorm:"size:64" json:"last_seen_sha"`
+	Occurrences  int        `gorm:"default:1;not null" json:"occurrences"` // Reviews that reported it
+	ResolvedAt   *time.Time `gorm:"index" json:"resolved_at"`              // No longer reported by the latest review (nil = open)
+	ResolvedSHA  string     `gorm:"size:64" json:"resolved_sha"`
+
+	// Developer feedback
+	FeedbackStatus string     `gorm:"size:20;index" json:"feedback_status"` // accepted, fixed, wont_fix, false_positive ("" = none)
+	FeedbackBy     string     `gorm:"size:100" json:"feedback_by"`          // HandsOff or GitLab username
+	FeedbackSource string     `gorm:"size:20" json:"feedback_source"`       // api, gitlab
+	FeedbackReason string     `gorm:"type:text" json:"feedback_reason"`
+	FeedbackAt     *time.Time `json:"feedback_at"`
+
 	// Relationships
 	ReviewResult *ReviewResult `gorm:"foreignKey:ReviewResultID" json:"review_result,omitempty"`
 }
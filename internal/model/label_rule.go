@@ -0,0 +1,29 @@
+package model
+
+// Label rule metrics, measured on each completed review
+const (
+	LabelMetricScore             = "score"
+	LabelMetricChangedLines      = "changed_lines"
+	LabelMetricIssues            = "issues"
+	LabelMetricCriticalIssues    = "critical_issues"
+	LabelMetricHighIssues        = "high_issues"
+	LabelMetricSecurityIssues    = "security_issues"
+	LabelMetricPerformanceIssues = "performance_issues"
+)
+
+// LabelRule puts a label on the merge request while a review metric is within
+// [Min, Max], and takes it off once it is not, e.g. "ai:security-risk" for
+// security_issues >= 1, "ai:score-low" for score <= 59, "ai:clean" for
+// issues <= 0 or "size:xl" for changed_lines >= 1000. Stored as a JSON list on
+// the repository.
+type LabelRule struct {
+	Label  string `json:"label"`
+	Metric string `json:"metric"` // score, changed_lines, issues, critical_issues, high_issues, security_issues, performance_issues
+	Min    *int   `json:"min"`    // Lower bound, inclusive (nil = none)
+	Max    *int   `json:"max"`    // Upper bound, inclusive (nil = none)
+}
+
+// Matches reports whether the metric value is within the rule's bounds
+func (r LabelRule) Matches(value int) bool {
+	return (r.Min == nil || value >= *r.Min) && (r.Max == nil || value <= *r.Max)
+}
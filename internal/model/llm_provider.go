@@ -8,15 +8,23 @@ type LLMProvider struct {
 	ID              uint       `gorm:"primarykey" json:"id"`
 	CreatedAt       time.Time  `json:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at"`
-	Name            string     `gorm:"not null;size:100;index" json:"name"`          // User-defined name, e.g., "OpenAI Official", "DeepSeek China"
-	BaseURL         string     `gorm:"not null;size:255" json:"base_url"`            // API endpoint
-	APIKey          string     `gorm:"not null;size:500" json:"-"`                   // Encrypted, never expose in JSON
-	Model           string     `gorm:"not null;size:100" json:"model"`                // Model name, e.g., "gpt-4", "deepseek-chat"
+	Name            string     `gorm:"not null;size:100;index" json:"name"` // User-defined name, e.g., "OpenAI Official", "DeepSeek China"
+	BaseURL         string     `gorm:"not null;size:255" json:"base_url"`   // API endpoint
+	APIKey          string     `gorm:"not null;size:500" json:"-"`          // Encrypted, never expose in JSON
+	Model           string     `gorm:"not null;size:100" json:"model"`      // Model name, e.g., "gpt-4", "deepseek-chat"
 	IsActive        bool       `gorm:"default:true;not null;index" json:"is_active"`
 	LastTestedAt    *time.Time `json:"last_tested_at"`
-	LastTestStatus  string     `gorm:"size:20" json:"last_test_status"`  // success, failed
+	LastTestStatus  string     `gorm:"size:20" json:"last_test_status"` // success, failed
 	LastTestMessage string     `gorm:"size:500" json:"last_test_message"`
 
+	// Rate limits shared by all workers (0 = unlimited)
+	RPMLimit int `gorm:"default:0;not null" json:"rpm_limit"` // Requests per minute
+	TPMLimit int `gorm:"default:0;not null" json:"tpm_limit"` // Tokens per minute
+
+	// Throttle state, filled from the rate limiter (not persisted)
+	Throttled      bool       `gorm:"-" json:"throttled"`
+	ThrottledUntil *time.Time `gorm:"-" json:"throttled_until,omitempty"`
+
 	// Project Relationship
 	ProjectID uint    `gorm:"not null;index;constraint:OnDelete:CASCADE" json:"project_id"`
 	Project   Project `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"project,omitempty"`
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// LLMResponseCache stores an LLM review response for reuse when the same diff
+// is reviewed again with the same prompt and model
+type LLMResponseCache struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	CacheKey      string `gorm:"not null;size:64;uniqueIndex" json:"cache_key"` // See llm.ResponseCacheKey
+	ProjectID     uint   `gorm:"not null;index" json:"project_id"`
+	LLMProviderID uint   `gorm:"not null;index" json:"llm_provider_id"`
+	ModelName     string `gorm:"not null;size:100" json:"model_name"`
+
+	Response  string     `gorm:"type:text;not null" json:"-"` // JSON-encoded llm.ReviewResponse
+	ExpiresAt time.Time  `gorm:"not null;index" json:"expires_at"`
+	HitCount  int        `gorm:"not null;default:0" json:"hit_count"`
+	LastHitAt *time.Time `json:"last_hit_at"`
+}
+
+// TableName specifies the table name
+func (LLMResponseCache) TableName() string {
+	return "llm_response_caches"
+}
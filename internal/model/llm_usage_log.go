@@ -9,6 +9,7 @@ const (
 	UsageStatusSuccess UsageStatus = "success"
 	UsageStatusFailed  UsageStatus = "failed"
 	UsageStatusTimeout UsageStatus = "timeout"
+	UsageStatusCached  UsageStatus = "cached" // Served from the response cache, no tokens spent
 )
 
 // UsageRequestType represents LLM API request type
@@ -17,6 +18,10 @@ type UsageRequestType string
 const (
 	UsageTypeCodeReview     UsageRequestType = "code_review"
 	UsageTypeTestConnection UsageRequestType = "test_connection"
+	UsageTypeVerification   UsageRequestType = "verification" // Consensus mode cross-check of findings
+	UsageTypeExplanation    UsageRequestType = "explanation"  // "/handsoff explain" on a finding
+	UsageTypeFollowUp       UsageRequestType = "follow_up"    // Reply to a developer in a review thread
+	UsageTypeMRSummary      UsageRequestType = "mr_summary"   // Generated merge request summary
 )
 
 // LLMUsageLog records each LLM API request for token tracking and cost analysis
@@ -35,8 +40,8 @@ type LLMUsageLog struct {
 	ModelName     string `gorm:"not null;size:100;index" json:"model_name"` // 实际使用的模型名
 
 	// Request Details
-	RequestType UsageRequestType `gorm:"not null;size:50;index;type:varchar(50)" json:"request_type"` // code_review, test_connection
-	Status      UsageStatus      `gorm:"not null;size:20;index;type:varchar(20)" json:"status"`       // success, failed, timeout
+	RequestType UsageRequestType `gorm:"not null;size:50;index;type:varchar(50)" json:"request_type"` // code_review, test_connection, verification
+	Status      UsageStatus      `gorm:"not null;size:20;index;type:varchar(20)" json:"status"`       // success, failed, timeout, cached
 	ErrorCode   string           `gorm:"size:50" json:"error_code"`                                   // API error code if failed
 	ErrorMsg    string           `gorm:"size:1000" json:"error_msg"`                                  // Error message if failed
 
@@ -45,6 +50,11 @@ type LLMUsageLog struct {
 	CompletionTokens int `gorm:"not null;default:0" json:"completion_tokens"`
 	TotalTokens      int `gorm:"not null;default:0;index" json:"total_tokens"`
 
+	// Cost of the call, from the model price in effect when it was logged
+	// (0 with an empty currency when the model has no price)
+	Cost     float64 `gorm:"not null;default:0" json:"cost"`
+	Currency string  `gorm:"size:10;index" json:"currency"`
+
 	// Performance Metrics
 	DurationMs    int64 `gorm:"not null;default:0" json:"duration_ms"` // API 调用耗时(毫秒)
 	RequestSizeB  int   `gorm:"default:0" json:"request_size_b"`       // 请求体大小（字节）
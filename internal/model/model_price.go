@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// ModelPrice is the price of a model of an LLM provider for a period of time.
+// The cost of each LLM call is computed from the price in effect when it is logged.
+type ModelPrice struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	ProjectID     uint   `gorm:"not null;index" json:"project_id"`
+	LLMProviderID uint   `gorm:"not null;index:idx_model_price_lookup" json:"llm_provider_id"`
+	ModelName     string `gorm:"not null;size:100;index:idx_model_price_lookup" json:"model_name"`
+
+	PromptPrice     float64 `gorm:"not null;default:0" json:"prompt_price"`     // Per million prompt tokens
+	CompletionPrice float64 `gorm:"not null;default:0" json:"completion_price"` // Per million completion tokens
+	Currency        string  `gorm:"not null;size:10" json:"currency"`           // e.g. USD, CNY
+
+	EffectiveFrom time.Time  `gorm:"not null" json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to"` // Exclusive, nil = open-ended
+
+	// Relationships
+	LLMProvider *LLMProvider `gorm:"foreignKey:LLMProviderID;constraint:OnDelete:CASCADE" json:"llm_provider,omitempty"`
+}
+
+// TableName specifies the table name
+func (ModelPrice) TableName() string {
+	return "model_prices"
+}
+
+// Cost returns the cost of a call with the given token counts
+func (p *ModelPrice) Cost(promptTokens, completionTokens int) float64 {
+	return (float64(promptTokens)*p.PromptPrice + float64(completionTokens)*p.CompletionPrice) / 1e6
+}
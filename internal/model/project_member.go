@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+// ProjectRole is a user's role within a project
+type ProjectRole string
+
+const (
+	ProjectRoleOwner  ProjectRole = "owner"  // Project creator (implicit, not stored as member)
+	ProjectRoleAdmin  ProjectRole = "admin"  // Full access to project configuration
+	ProjectRoleMember ProjectRole = "member" // Read reviews and trigger reviews
+	ProjectRoleViewer ProjectRole = "viewer" // Read-only access
+)
+
+// Membership sources
+const (
+	MemberSourceManual = "manual" // Granted by a project owner
+	MemberSourceOIDC   = "oidc"   // Synced from OIDC group claims on every login
+)
+
+// ProjectMember grants a user access to a project owned by someone else
+type ProjectMember struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	ProjectID uint        `gorm:"not null;uniqueIndex:idx_project_member" json:"project_id"`
+	UserID    uint        `gorm:"not null;uniqueIndex:idx_project_member;index" json:"user_id"`
+	Role      ProjectRole `gorm:"not null;size:20;type:varchar(20)" json:"role"`
+	Source    string      `gorm:"not null;size:20;default:'manual'" json:"source"` // manual, oidc
+
+	// Relationships
+	Project *Project `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"project,omitempty"`
+	User    *User    `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+// TableName specifies the table name
+func (ProjectMember) TableName() string {
+	return "project_members"
+}
+
+// IsValidProjectRole reports whether a role can be assigned to a member
+func IsValidProjectRole(role ProjectRole) bool {
+	switch role {
+	case ProjectRoleAdmin, ProjectRoleMember, ProjectRoleViewer:
+		return true
+	}
+	return false
+}
+
+// Scopes returns the API scopes equivalent to the role.
+// Used to apply the same route restrictions to sessions as to API tokens.
+func (r ProjectRole) Scopes() []string {
+	switch r {
+	case ProjectRoleOwner, ProjectRoleAdmin:
+		return []string{ScopeAdmin}
+	case ProjectRoleMember:
+		return []string{ScopeReviewsWrite}
+	case ProjectRoleViewer:
+		return []string{ScopeReviewsRead}
+	}
+	return nil
+}
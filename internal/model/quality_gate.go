@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+// QualityGateStatusName is the name of the commit status HandsOff sets on the MR head
+const QualityGateStatusName = "handsoff/review"
+
+// Quality gate outcomes of a review ("" when the repository has no gate)
+const (
+	GateStatusPassed = "passed"
+	GateStatusFailed = "failed"
+	GateStatusWaived = "waived" // Failed, then overridden through the waiver API
+)
+
+// QualityGate decides whether a review passes, reported as a commit status on the
+// MR head so merges can be blocked. Stored as JSON on the repository; unset means off.
+type QualityGate struct {
+	Enabled          bool `json:"enabled"`
+	MinScore         int  `json:"min_score"`          // Fail below this score (0 = no minimum)
+	NoCritical       bool `json:"no_critical"`        // Fail on any critical finding
+	NoSecurityIssues bool `json:"no_security_issues"` // Fail on any finding in the security category
+}
+
+// EffectiveQualityGate returns the repository's quality gate, or a disabled gate if unset
+func (r *Repository) EffectiveQualityGate() QualityGate {
+	if r.QualityGate == nil {
+		return QualityGate{}
+	}
+	return *r.QualityGate
+}
+
+// GateWaiver records an override of a failed quality gate. Rows are never updated,
+// and only deleted when the commit status could not be set, so the table doubles as
+// the audit trail of waivers.
+type GateWaiver struct {
+	ID             uint      `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time `gorm:"index" json:"created_at"`
+	ReviewResultID uint      `gorm:"not null;index" json:"review_result_id"`
+	RepositoryID   uint      `gorm:"not null;index" json:"repository_id"`
+	MergeRequestID int64     `gorm:"not null" json:"merge_request_id"`
+	HeadSHA        string    `gorm:"size:64;not null" json:"head_sha"`   // The waiver only applies to this commit
+	GateReason     string    `gorm:"type:text" json:"gate_reason"`       // Why the gate had failed
+	Reason         string    `gorm:"type:text;not null" json:"reason"`   // Why it was waived
+	WaivedByID     uint      `gorm:"index" json:"waived_by_id"`          // HandsOff user
+	WaivedBy       string    `gorm:"size:255;not null" json:"waived_by"` // Username at the time of the waiver
+}
+
+// TableName specifies the table name
+func (GateWaiver) TableName() string {
+	return "gate_waivers"
+}
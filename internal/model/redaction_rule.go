@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// RedactionRule is a project-specific secret pattern, redacted from diffs
+// before they are sent to the LLM in addition to the builtin rules
+type RedactionRule struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	ProjectID   uint   `gorm:"not null;index" json:"project_id"`
+	Name        string `gorm:"not null;size:50" json:"name"`     // Shown in placeholders, e.g. [REDACTED:name:1]
+	Pattern     string `gorm:"not null;size:500" json:"pattern"` // Go regexp; the first capture group is redacted if any
+	Description string `gorm:"size:255" json:"description"`
+	IsActive    bool   `gorm:"default:true;not null" json:"is_active"`
+}
+
+// TableName specifies the table name
+func (RedactionRule) TableName() string {
+	return "redaction_rules"
+}
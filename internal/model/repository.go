@@ -15,6 +15,26 @@ const (
 	WebhookTestResultFailed  = "failed"  // 测试失败
 )
 
+// MR summary modes
+const (
+	MRSummaryOff         = ""
+	MRSummaryComment     = "comment"     // Post the summary as a comment
+	MRSummaryDescription = "description" // Write it into an empty description, else post a comment
+)
+
+// Reviewer recommendation modes
+const (
+	ReviewerModeOff     = ""
+	ReviewerModeSuggest = "suggest" // List recommended reviewers in the review comment
+	ReviewerModeAssign  = "assign"  // Also set them on merge requests that have no reviewers yet
+)
+
+// Rejected finding actions (consensus mode)
+const (
+	RejectedFindingDrop      = "drop"      // Hide rejected findings from the MR comment
+	RejectedFindingDowngrade = "downgrade" // Keep rejected findings at low severity
+)
+
 // Repository represents a Git repository (project-scoped)
 type Repository struct {
 	ID             uint      `gorm:"primarykey" json:"id"`
@@ -44,6 +64,32 @@ type Repository struct {
 	// Custom Review Prompt (optional, overrides global config)
 	CustomReviewPrompt *string `gorm:"type:text" json:"custom_review_prompt"`
 
+	// Review trigger rules (optional, defaults skip drafts only)
+	TriggerRules *ReviewTriggerRules `gorm:"type:text;serializer:json" json:"trigger_rules"`
+
+	// Always call the LLM, even when an identical diff was reviewed recently
+	ResponseCacheDisabled bool `gorm:"default:false;not null" json:"response_cache_disabled"`
+
+	// Consensus mode (optional): a second provider verifies high and critical findings
+	VerifierLLMProviderID *uint  `gorm:"index" json:"verifier_llm_provider_id"`
+	RejectedFindingAction string `gorm:"size:20;default:'drop';not null" json:"rejected_finding_action"` // drop, downgrade
+
+	// Quality gate (optional): commit status on the MR head, failed when the review does not pass
+	QualityGate *QualityGate `gorm:"type:text;serializer:json" json:"quality_gate"`
+
+	// MR labels kept in sync with each review's findings (optional)
+	LabelRules []LabelRule `gorm:"type:text;serializer:json" json:"label_rules"`
+
+	// Generated MR summary for new merge requests (optional)
+	MRSummaryMode string `gorm:"size:20;default:'';not null" json:"mr_summary_mode"` // "" (off), comment, description
+
+	// Human reviewer recommendation from CODEOWNERS and path history (optional)
+	ReviewerMode string `gorm:"size:20;default:'';not null" json:"reviewer_mode"` // "" (off), suggest, assign
+	MaxReviewers int    `gorm:"default:2;not null" json:"max_reviewers"`
+
+	// Static analyzers run on a checkout before the LLM review, e.g. ["go_vet", "semgrep"] (empty = off)
+	Analyzers []string `gorm:"type:text;serializer:json" json:"analyzers"`
+
 	// Project Relationship
 	ProjectID uint    `gorm:"not null;index;constraint:OnDelete:CASCADE" json:"project_id"`
 	Project   Project `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"project,omitempty"`
@@ -51,6 +97,8 @@ type Repository struct {
 	// Relationships
 	Platform    GitPlatformConfig `gorm:"foreignKey:PlatformID;constraint:OnDelete:CASCADE" json:"platform,omitempty"`
 	LLMProvider *LLMProvider      `gorm:"foreignKey:LLMProviderID" json:"llm_provider,omitempty"`
+
+	VerifierLLMProvider *LLMProvider `gorm:"foreignKey:VerifierLLMProviderID" json:"verifier_llm_provider,omitempty"`
 }
 
 // TableName specifies the table name
@@ -2,40 +2,76 @@ package model
 
 import "time"
 
+// Review status constants
+const (
+	ReviewStatusPending        = "pending"
+	ReviewStatusProcessing     = "processing"
+	ReviewStatusCompleted      = "completed"
+	ReviewStatusFailed         = "failed"
+	ReviewStatusSkipped        = "skipped"         // Excluded by trigger rules (e.g. too many changed lines)
+	ReviewStatusSuperseded     = "superseded"      // The MR moved to a newer commit while the review was running
+	ReviewStatusBudgetExceeded = "budget_exceeded" // Skipped: a hard budget limit was reached
+)
+
 // ReviewResult represents a code review result
 type ReviewResult struct {
-	ID             uint      `gorm:"primarykey" json:"id"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
-	RepositoryID   uint      `gorm:"not null;uniqueIndex:idx_repo_mr" json:"repository_id"`   // Foreign key to repositories
-	MergeRequestID int64     `gorm:"not null;uniqueIndex:idx_repo_mr" json:"merge_request_id"` // GitLab MR ID (unique per repository)
-	MRTitle        string    `gorm:"size:500" json:"mr_title"`
-	MRAuthor       string    `gorm:"size:100;index" json:"mr_author"`
-	SourceBranch   string    `gorm:"size:255" json:"source_branch"`
-	TargetBranch   string    `gorm:"size:255" json:"target_branch"`
-	MRWebURL       string    `gorm:"size:500" json:"mr_web_url"`
-	LLMProviderID  uint      `gorm:"not null;index" json:"llm_provider_id"`  // Foreign key to llm_providers
-	Score          int       `gorm:"index" json:"score"`                  // 0-100
-	Summary        string    `gorm:"type:text" json:"summary"`            // AI summary
-	RawResult      string    `gorm:"type:text" json:"raw_result"`         // Raw AI response (JSON)
-	Status         string    `gorm:"size:20;index" json:"status"`         // pending, processing, completed, failed
-	ErrorMessage   string    `gorm:"size:1000" json:"error_message"`
+	ID             uint       `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	RepositoryID   uint       `gorm:"not null;uniqueIndex:idx_repo_mr" json:"repository_id"`    // Foreign key to repositories
+	MergeRequestID int64      `gorm:"not null;uniqueIndex:idx_repo_mr" json:"merge_request_id"` // GitLab MR ID (unique per repository)
+	MRTitle        string     `gorm:"size:500" json:"mr_title"`
+	MRAuthor       string     `gorm:"size:100;index" json:"mr_author"`
+	SourceBranch   string     `gorm:"size:255" json:"source_branch"`
+	TargetBranch   string     `gorm:"size:255" json:"target_branch"`
+	MRWebURL       string     `gorm:"size:500" json:"mr_web_url"`
+	LLMProviderID  uint       `gorm:"not null;index" json:"llm_provider_id"` // Foreign key to llm_providers
+	Score          int        `gorm:"index" json:"score"`                    // 0-100
+	Summary        string     `gorm:"type:text" json:"summary"`              // AI summary
+	RawResult      string     `gorm:"type:text" json:"raw_result"`           // Raw AI response (JSON)
+	Status         string     `gorm:"size:20;index" json:"status"`           // pending, processing, completed, failed
+	ErrorMessage   string     `gorm:"size:1000" json:"error_message"`
 	ReviewedAt     *time.Time `json:"reviewed_at"`
 	CommentPosted  bool       `gorm:"default:false;not null" json:"comment_posted"` // Whether comment was posted to GitLab
 	CommentURL     string     `gorm:"size:500" json:"comment_url"`
 
+	// The bot's review thread on the MR; its note is edited in place on re-review
+	CommentNoteID       int64  `gorm:"default:0" json:"comment_note_id"`
+	CommentDiscussionID string `gorm:"size:64" json:"comment_discussion_id"`
+
+	// Quality gate outcome for HeadSHA ("" when the repository has no gate)
+	GateStatus string `gorm:"size:20;index" json:"gate_status"` // passed, failed, waived
+	GateReason string `gorm:"type:text" json:"gate_reason"`     // Why the gate failed
+
+	// When the generated MR summary was posted (once per merge request)
+	MRSummaryAt *time.Time `json:"mr_summary_at"`
+
+	// Files changed at HeadSHA (path history for reviewer recommendations) and the
+	// reviewers recommended for them
+	ChangedFiles       []string `gorm:"type:text;serializer:json" json:"changed_files"`
+	SuggestedReviewers []string `gorm:"type:text;serializer:json" json:"suggested_reviewers"`
+
+	// Commit being reviewed and its queue task; a newer commit supersedes both
+	HeadSHA string `gorm:"size:64" json:"head_sha"`
+	TaskID  string `gorm:"size:200" json:"task_id"`
+
 	// Webhook event relationship (optional, for tracing which webhook triggered this review)
 	WebhookEventID *uint `gorm:"index" json:"webhook_event_id"` // Foreign key to webhook_events
 
 	// Statistics fields
-	IssuesFound            int `gorm:"default:0" json:"issues_found"`              // Total number of issues found
+	IssuesFound            int `gorm:"default:0" json:"issues_found"`                // Total number of issues found
 	CriticalIssuesCount    int `gorm:"default:0;index" json:"critical_issues_count"` // Number of critical severity issues
-	HighIssuesCount        int `gorm:"default:0" json:"high_issues_count"`         // Number of high severity issues
-	MediumIssuesCount      int `gorm:"default:0" json:"medium_issues_count"`       // Number of medium severity issues
-	LowIssuesCount         int `gorm:"default:0" json:"low_issues_count"`          // Number of low severity issues
-	SecurityIssuesCount    int `gorm:"default:0;index" json:"security_issues_count"`  // Number of security issues
-	PerformanceIssuesCount int `gorm:"default:0" json:"performance_issues_count"` // Number of performance issues
-	QualityIssuesCount     int `gorm:"default:0" json:"quality_issues_count"`      // Number of quality issues
+	HighIssuesCount        int `gorm:"default:0" json:"high_issues_count"`           // Number of high severity issues
+	MediumIssuesCount      int `gorm:"default:0" json:"medium_issues_count"`         // Number of medium severity issues
+	LowIssuesCount         int `gorm:"default:0" json:"low_issues_count"`            // Number of low severity issues
+	SecurityIssuesCount    int `gorm:"default:0;index" json:"security_issues_count"` // Number of security issues
+	PerformanceIssuesCount int `gorm:"default:0" json:"performance_issues_count"`    // Number of performance issues
+	QualityIssuesCount     int `gorm:"default:0" json:"quality_issues_count"`        // Number of quality issues
+
+	// Change since the previous review of the merge request
+	NewIssuesCount        int `gorm:"default:0" json:"new_issues_count"`        // Reported for the first time (or again after being resolved)
+	PersistingIssuesCount int `gorm:"default:0" json:"persisting_issues_count"` // Also reported by the previous review
+	ResolvedIssuesCount   int `gorm:"default:0" json:"resolved_issues_count"`   // Reported before, gone now
 
 	// Token Usage Summary (denormalized for fast access, source of truth is llm_usage_logs)
 	PromptTokens     int   `gorm:"default:0" json:"prompt_tokens"`
@@ -44,9 +80,9 @@ type ReviewResult struct {
 	LLMDurationMs    int64 `gorm:"default:0" json:"llm_duration_ms"` // LLM API call duration in milliseconds
 
 	// Relationships
-	Repository     *Repository      `gorm:"foreignKey:RepositoryID" json:"repository,omitempty"`
-	LLMProvider    *LLMProvider     `gorm:"foreignKey:LLMProviderID" json:"llm_provider,omitempty"`
-	FixSuggestions []FixSuggestion  `gorm:"foreignKey:ReviewResultID" json:"fix_suggestions,omitempty"`
+	Repository     *Repository     `gorm:"foreignKey:RepositoryID" json:"repository,omitempty"`
+	LLMProvider    *LLMProvider    `gorm:"foreignKey:LLMProviderID" json:"llm_provider,omitempty"`
+	FixSuggestions []FixSuggestion `gorm:"foreignKey:ReviewResultID" json:"fix_suggestions,omitempty"`
 }
 
 // TableName specifies the table name
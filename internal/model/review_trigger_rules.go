@@ -0,0 +1,25 @@
+package model
+
+// ReviewTriggerRules decides which merge request events of a repository trigger a review.
+// Stored as JSON on the repository; an unset value means DefaultReviewTriggerRules.
+type ReviewTriggerRules struct {
+	SkipDrafts      bool     `json:"skip_drafts"`       // Skip Draft/WIP merge requests
+	IncludeLabels   []string `json:"include_labels"`    // Opt-in: review only MRs with at least one of these labels (empty = all)
+	ExcludeLabels   []string `json:"exclude_labels"`    // Opt-out: never review MRs with any of these labels
+	TargetBranches  []string `json:"target_branches"`   // Glob patterns such as "main" or "release/*" (empty = all)
+	ExcludedAuthors []string `json:"excluded_authors"`  // Username glob patterns such as "renovate" or "*-bot"
+	MaxChangedLines int      `json:"max_changed_lines"` // Skip MRs changing more lines than this (0 = unlimited)
+}
+
+// DefaultReviewTriggerRules returns the rules used when a repository has none configured
+func DefaultReviewTriggerRules() ReviewTriggerRules {
+	return ReviewTriggerRules{SkipDrafts: true}
+}
+
+// EffectiveTriggerRules returns the repository's trigger rules, or the defaults if unset
+func (r *Repository) EffectiveTriggerRules() ReviewTriggerRules {
+	if r.TriggerRules == nil {
+		return DefaultReviewTriggerRules()
+	}
+	return *r.TriggerRules
+}
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// Reviewer profile categories, matching the categories counted on review results
+const (
+	ReviewCategorySecurity    = "security"
+	ReviewCategoryPerformance = "performance"
+	ReviewCategoryCorrectness = "correctness"
+	ReviewCategoryQuality     = "quality"
+	ReviewCategoryStyle       = "style"
+)
+
+// ReviewerProfile is a specialized review pass. When a project has active profiles,
+// each merge request is reviewed by all of them in parallel and their suggestions are
+// merged; without profiles a single general review is made.
+type ReviewerProfile struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	ProjectID uint   `gorm:"not null;index" json:"project_id"`
+	Name      string `gorm:"not null;size:100" json:"name"`
+	Category  string `gorm:"not null;size:30" json:"category"` // Set on every suggestion of the pass
+
+	// Prompt template (must contain {{.Diff}}); empty = the project prompt focused on Category
+	PromptTemplate string `gorm:"type:text" json:"prompt_template"`
+	// Provider and model of the pass; nil = the repository's provider
+	LLMProviderID *uint `gorm:"index" json:"llm_provider_id"`
+	IsActive      bool  `gorm:"default:true;not null" json:"is_active"`
+
+	// Relationships
+	LLMProvider *LLMProvider `gorm:"foreignKey:LLMProviderID;constraint:OnDelete:SET NULL" json:"llm_provider,omitempty"`
+}
+
+// TableName specifies the table name
+func (ReviewerProfile) TableName() string {
+	return "reviewer_profiles"
+}
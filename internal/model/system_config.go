@@ -25,4 +25,5 @@ const (
 	ConfigKeyWebhookURL           = "webhook_callback_url"   // System Webhook URL
 	ConfigKeyReviewPromptTemplate = "review_prompt_template" // Review Prompt template
 	ConfigKeyReviewPromptVersion  = "review_prompt_version"  // Review Prompt version
+	ConfigKeyMRSummaryPrompt      = "mr_summary_prompt"      // MR summary prompt template
 )
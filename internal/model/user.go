@@ -17,10 +17,24 @@ type User struct {
 	Email     string         `gorm:"uniqueIndex;size:100" json:"email"`
 	IsActive  bool           `gorm:"default:true;not null" json:"is_active"`
 
+	// External identity (OIDC). Local users have AuthProvider "local".
+	AuthProvider string  `gorm:"not null;size:20;default:'local'" json:"auth_provider"`
+	ExternalID   *string `gorm:"uniqueIndex;size:255" json:"-"` // OIDC "iss|sub", nil for local users
+
+	// TokensRevokedAt invalidates every access token issued before it
+	// (set on password change and deactivation)
+	TokensRevokedAt *time.Time `json:"-"`
+
 	// Relationships
 	Projects []Project `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"projects,omitempty"`
 }
 
+// Authentication providers
+const (
+	AuthProviderLocal = "local"
+	AuthProviderOIDC  = "oidc"
+)
+
 // TableName specifies the table name
 func (User) TableName() string {
 	return "users"
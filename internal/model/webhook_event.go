@@ -8,6 +8,7 @@ type EventType string
 const (
 	EventTypePush         EventType = "push"
 	EventTypeMergeRequest EventType = "merge_request"
+	EventTypeNote         EventType = "note"
 )
 
 // EventStatus represents webhook event processing status
@@ -38,20 +39,22 @@ type WebhookEvent struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 
-	RepositoryID uint      `gorm:"not null;index:idx_webhook_repo_type" json:"repository_id"`
+	RepositoryID uint      `gorm:"not null;index:idx_webhook_repo_type;uniqueIndex:idx_webhook_repo_commit" json:"repository_id"`
 	EventType    EventType `gorm:"not null;size:30;index:idx_webhook_repo_type;index;type:varchar(30)" json:"event_type"` // push, merge_request
-	Action       MRAction  `gorm:"size:30;index;type:varchar(30)" json:"action"`                                           // open, update, merge (for MR)
+	Action       MRAction  `gorm:"size:30;index;type:varchar(30)" json:"action"`                                          // open, update, merge (for MR)
 
 	// Event metadata
-	SourceBranch string `gorm:"size:255" json:"source_branch"`
-	TargetBranch string `gorm:"size:255" json:"target_branch"`                                 // for MR
-	MRIID        *int64 `gorm:"index:idx_webhook_mr" json:"mr_iid"`                            // GitLab MR IID (nullable for push events)
-	CommitSHA    string `gorm:"size:100;uniqueIndex:idx_repo_commit;index" json:"commit_sha"` // Unique per repository for commit-based deduplication
+	SourceBranch string  `gorm:"size:255" json:"source_branch"`
+	TargetBranch string  `gorm:"size:255" json:"target_branch"`                                        // for MR
+	MRIID        *int64  `gorm:"index:idx_webhook_mr" json:"mr_iid"`                                   // GitLab MR IID (nullable for push events)
+	CommitSHA    *string `gorm:"size:100;uniqueIndex:idx_webhook_repo_commit;index" json:"commit_sha"` // Unique per repository for commit-based deduplication (set only for review-triggering events)
 
 	// Processing status
 	Status       EventStatus `gorm:"size:20;index;not null;default:'pending'" json:"status"` // pending, processing, completed, failed, ignored
+	IgnoreReason string      `gorm:"size:255" json:"ignore_reason"`                          // Why the event did not trigger a review
 	ProcessedAt  *time.Time  `json:"processed_at"`
 	ErrorMessage string      `gorm:"type:text" json:"error_message"`
+	ReplayCount  int         `gorm:"default:0" json:"replay_count"` // Number of manual replays
 
 	// Raw data for debugging
 	RawPayload string `gorm:"type:text" json:"-"` // Store complete JSON
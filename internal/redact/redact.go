@@ -0,0 +1,284 @@
+// Package redact removes secrets from diffs before they are sent to an LLM provider.
+package redact
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Rule detects one kind of secret
+type Rule struct {
+	Name       string // Used in placeholders, e.g. [REDACTED:aws_access_key:1]
+	Label      string // Human readable, e.g. "AWS access key"
+	Pattern    *regexp.Regexp
+	Group      int  // Submatch holding the secret (0 = whole match)
+	ConfigOnly bool // Only applies to configuration files
+}
+
+// Finding is a secret found on an added line. It never holds the secret itself.
+type Finding struct {
+	Rule        string
+	Label       string
+	FilePath    string
+	Line        int    // Line in the new file
+	Placeholder string // What the secret was replaced with
+	Snippet     string // The redacted line
+}
+
+// PrivateKeyRule names the builtin rule for PEM private key blocks, which span lines
+const PrivateKeyRule = "private_key"
+
+var (
+	validRuleName = regexp.MustCompile(`^[a-z0-9_]{1,50}$`)
+
+	privateKeyBegin = regexp.MustCompile(`-----BEGIN [A-Z0-9 ]*PRIVATE KEY( BLOCK)?-----`)
+	privateKeyEnd   = regexp.MustCompile(`-----END [A-Z0-9 ]*PRIVATE KEY( BLOCK)?-----`)
+
+	hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+)
+
+// configExtensions are file types where "password: value" style assignments are secrets
+var configExtensions = map[string]bool{
+	".env": true, ".yml": true, ".yaml": true, ".json": true, ".ini": true, ".properties": true,
+	".conf": true, ".cfg": true, ".toml": true, ".xml": true,
+}
+
+// BuiltinRules returns the rules applied to every project
+func BuiltinRules() []Rule {
+	return []Rule{
+		{Name: "aws_access_key", Label: "AWS access key", Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+		{Name: "github_token", Label: "GitHub token", Pattern: regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+		{Name: "gitlab_token", Label: "GitLab token", Pattern: regexp.MustCompile(`\bglpat-[A-Za-z0-9_\-]{20,}`)},
+		{Name: "slack_token", Label: "Slack token", Pattern: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9\-]{10,}`)},
+		{Name: "openai_key", Label: "OpenAI API key", Pattern: regexp.MustCompile(`\bsk-(?:proj-)?[A-Za-z0-9_\-]{20,}`)},
+		{Name: "google_api_key", Label: "Google API key", Pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`)},
+		{Name: "jwt", Label: "JSON Web Token", Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]{10,}\.eyJ[A-Za-z0-9_\-]{10,}\.[A-Za-z0-9_\-]{10,}`)},
+		{
+			Name:       "config_password",
+			Label:      "password in a configuration file",
+			Pattern:    regexp.MustCompile(`(?i)(?:password|passwd|pwd|secret|api[_-]?key|access[_-]?key|auth[_-]?token|token)["']?\s*[:=]\s*["']?([^\s"',;#]{4,})`),
+			Group:      1,
+			ConfigOnly: true,
+		},
+	}
+}
+
+// IsBuiltin reports whether a rule name is taken by a builtin rule
+func IsBuiltin(name string) bool {
+	if name == PrivateKeyRule {
+		return true
+	}
+	for _, rule := range BuiltinRules() {
+		if rule.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// CompileRule builds a custom rule. If the pattern has a capture group,
+// only the first group is redacted, otherwise the whole match.
+func CompileRule(name, pattern string) (Rule, error) {
+	if !validRuleName.MatchString(name) {
+		return Rule{}, fmt.Errorf("rule name must be 1-50 lowercase letters, digits or underscores")
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid pattern: %w", err)
+	}
+	if re.MatchString("") {
+		return Rule{}, fmt.Errorf("pattern must not match an empty string")
+	}
+
+	rule := Rule{Name: name, Label: name, Pattern: re}
+	if re.NumSubexp() > 0 {
+		rule.Group = 1
+	}
+	return rule, nil
+}
+
+// Redactor replaces secrets in diffs with stable placeholders
+type Redactor struct {
+	rules []Rule
+}
+
+// New creates a redactor with the builtin rules and the given custom rules
+func New(custom ...Rule) *Redactor {
+	return &Redactor{rules: append(BuiltinRules(), custom...)}
+}
+
+// Redact returns the diff with every secret replaced by a placeholder, and the
+// secrets found on added lines. The same secret always gets the same placeholder
+// within a diff, and placeholders are numbered in order of appearance, so the
+// same diff always redacts to the same text.
+func (r *Redactor) Redact(diff string) (string, []Finding) {
+	state := &redaction{placeholders: map[string]string{}, counts: map[string]int{}}
+
+	lines := strings.Split(diff, "\n")
+	var findings []Finding
+	reported := map[string]bool{} // Each secret is reported once per file
+	var filePath string
+	newLine := 0
+	inPrivateKey := false
+	// Lines left in the current hunk: inside a hunk, "+++ " and "--- " lines are
+	// content ("++ x" added, "-- x" removed), not file headers
+	oldLeft, newLeft := 0, 0
+
+	for i, line := range lines {
+		inHunk := oldLeft > 0 || newLeft > 0
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			oldLeft, newLeft = 0, 0
+			continue
+		case !inHunk && strings.HasPrefix(line, "+++ "):
+			filePath = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+			continue
+		case !inHunk && strings.HasPrefix(line, "--- "):
+			continue
+		case !inHunk && strings.HasPrefix(line, "@@"):
+			if m := hunkHeader.FindStringSubmatch(line); m != nil {
+				newLine, _ = strconv.Atoi(m[2])
+				oldLeft, newLeft = hunkCount(m[1]), hunkCount(m[3])
+			}
+			continue
+		}
+
+		prefix, content := "", line
+		if line != "" && strings.ContainsRune("+- \\", rune(line[0])) {
+			prefix, content = line[:1], line[1:]
+		}
+		lineNo := 0
+		if prefix == "+" || prefix == " " {
+			lineNo = newLine
+			newLine++
+			newLeft--
+		}
+		if prefix == "-" || prefix == " " {
+			oldLeft--
+		}
+
+		var found []Finding
+		content, found, inPrivateKey = r.redactLine(state, content, filePath, inPrivateKey)
+		lines[i] = prefix + content
+
+		if prefix == "+" {
+			for _, f := range found {
+				if reported[filePath+"\x00"+f.Placeholder] {
+					continue
+				}
+				reported[filePath+"\x00"+f.Placeholder] = true
+				f.FilePath, f.Line, f.Snippet = filePath, lineNo, strings.TrimSpace(content)
+				findings = append(findings, f)
+			}
+		}
+	}
+
+	return strings.Join(lines, "\n"), findings
+}
+
+// hunkCount reads a line count of a hunk header, which defaults to 1 when omitted
+func hunkCount(s string) int {
+	if s == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// RedactText returns text that is not a diff, e.g. an analyzer message quoting
+// code of filePath, with every secret replaced by a placeholder
+func (r *Redactor) RedactText(text, filePath string) string {
+	state := &redaction{placeholders: map[string]string{}, counts: map[string]int{}}
+	lines := strings.Split(text, "\n")
+	inPrivateKey := false
+	for i, line := range lines {
+		lines[i], _, inPrivateKey = r.redactLine(state, line, filePath, inPrivateKey)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// redaction tracks placeholders across a diff
+type redaction struct {
+	placeholders map[string]string // rule + secret -> placeholder
+	counts       map[string]int    // rule -> placeholders issued
+	privateKeys  int
+}
+
+func (s *redaction) placeholder(rule, secret string) string {
+	key := rule + "\x00" + secret
+	if p, ok := s.placeholders[key]; ok {
+		return p
+	}
+	s.counts[rule]++
+	p := fmt.Sprintf("[REDACTED:%s:%d]", rule, s.counts[rule])
+	s.placeholders[key] = p
+	return p
+}
+
+// redactLine redacts one line's content. Private key blocks span lines, so whether
+// the line is inside one is threaded through.
+func (r *Redactor) redactLine(s *redaction, content, filePath string, inPrivateKey bool) (string, []Finding, bool) {
+	var findings []Finding
+
+	if inPrivateKey {
+		placeholder := fmt.Sprintf("[REDACTED:%s:%d]", PrivateKeyRule, s.privateKeys)
+		if loc := privateKeyEnd.FindStringIndex(content); loc != nil {
+			return placeholder + content[loc[1]:], nil, false
+		}
+		return placeholder, nil, true
+	}
+	if loc := privateKeyBegin.FindStringIndex(content); loc != nil {
+		s.privateKeys++
+		placeholder := fmt.Sprintf("[REDACTED:%s:%d]", PrivateKeyRule, s.privateKeys)
+		findings = append(findings, Finding{Rule: PrivateKeyRule, Label: "private key", Placeholder: placeholder})
+		if end := privateKeyEnd.FindStringIndex(content[loc[1]:]); end != nil {
+			return content[:loc[0]] + placeholder + content[loc[1]+end[1]:], findings, false
+		}
+		return content[:loc[0]] + placeholder, findings, true
+	}
+
+	configFile := isConfigFile(filePath)
+	for _, rule := range r.rules {
+		if rule.ConfigOnly && !configFile {
+			continue
+		}
+
+		matches := rule.Pattern.FindAllStringSubmatchIndex(content, -1)
+		// Replace right to left so earlier offsets stay valid
+		for j := len(matches) - 1; j >= 0; j-- {
+			start, end := matches[j][2*rule.Group], matches[j][2*rule.Group+1]
+			if start < 0 || start == end {
+				continue
+			}
+			secret := content[start:end]
+			if ignoredValue(secret) {
+				continue
+			}
+
+			placeholder := s.placeholder(rule.Name, secret)
+			content = content[:start] + placeholder + content[end:]
+			findings = append(findings, Finding{Rule: rule.Name, Label: rule.Label, Placeholder: placeholder})
+		}
+	}
+	return content, findings, false
+}
+
+// ignoredValue reports values that are references or earlier placeholders, not secrets
+func ignoredValue(value string) bool {
+	if value == "" || strings.Contains(value, "[REDACTED:") {
+		return true
+	}
+	switch strings.ToLower(value) {
+	case "true", "false", "null", "none", "changeme":
+		return true
+	}
+	return strings.ContainsAny(value[:1], "$<{%*")
+}
+
+func isConfigFile(filePath string) bool {
+	base := path.Base(filePath)
+	return strings.HasPrefix(base, ".env") || configExtensions[strings.ToLower(path.Ext(base))]
+}
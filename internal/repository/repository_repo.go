@@ -101,6 +101,18 @@ func (r *RepositoryRepo) GetByPlatformRepoID(projectID uint, platformID uint, pl
 	return &repo, nil
 }
 
+// FindByWebhook retrieves a repository, of any project, that receives events through
+// the given GitLab webhook and knows its secret
+func (r *RepositoryRepo) FindByWebhook(platformRepoID int64, webhookID int64, webhookURL string) (*model.Repository, error) {
+	var repo model.Repository
+	err := r.db.Where("platform_repo_id = ? AND webhook_id = ? AND webhook_url = ? AND webhook_secret <> ''",
+		platformRepoID, webhookID, webhookURL).First(&repo).Error
+	if err != nil {
+		return nil, err
+	}
+	return &repo, nil
+}
+
 // Create creates a new repository
 func (r *RepositoryRepo) Create(repo *model.Repository) error {
 	return r.db.Create(repo).Error
@@ -126,6 +138,69 @@ func (r *RepositoryRepo) UpdateLLMModel(id uint, llmModelID *uint) error {
 	return r.db.Model(&model.Repository{}).Where("id = ?", id).Update("llm_model_id", llmModelID).Error
 }
 
+// UpdateTriggerRules updates the review trigger rules of a repository
+func (r *RepositoryRepo) UpdateTriggerRules(id uint, rules *model.ReviewTriggerRules) error {
+	// Select + struct so the JSON serializer is applied
+	return r.db.Model(&model.Repository{ID: id}).Select("trigger_rules").
+		Updates(&model.Repository{TriggerRules: rules}).Error
+}
+
+// UpdateQualityGate updates the quality gate of a repository
+func (r *RepositoryRepo) UpdateQualityGate(id uint, gate *model.QualityGate) error {
+	// Select + struct so the JSON serializer is applied
+	return r.db.Model(&model.Repository{ID: id}).Select("quality_gate").
+		Updates(&model.Repository{QualityGate: gate}).Error
+}
+
+// UpdateLabelRules updates the MR label rules of a repository
+func (r *RepositoryRepo) UpdateLabelRules(id uint, rules []model.LabelRule) error {
+	// Select + struct so the JSON serializer is applied
+	return r.db.Model(&model.Repository{ID: id}).Select("label_rules").
+		Updates(&model.Repository{LabelRules: rules}).Error
+}
+
+// UpdateMRSummaryMode sets how generated MR summaries are posted for a repository
+func (r *RepositoryRepo) UpdateMRSummaryMode(id uint, mode string) error {
+	return r.db.Model(&model.Repository{}).Where("id = ?", id).Update("mr_summary_mode", mode).Error
+}
+
+// UpdateReviewers sets the reviewer recommendation mode and reviewer count of a repository
+func (r *RepositoryRepo) UpdateReviewers(id uint, mode string, maxReviewers int) error {
+	return r.db.Model(&model.Repository{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"reviewer_mode": mode,
+		"max_reviewers": maxReviewers,
+	}).Error
+}
+
+// UpdateAnalyzers updates the static analyzers of a repository
+func (r *RepositoryRepo) UpdateAnalyzers(id uint, analyzers []string) error {
+	// Select + struct so the JSON serializer is applied
+	return r.db.Model(&model.Repository{ID: id}).Select("analyzers").
+		Updates(&model.Repository{Analyzers: analyzers}).Error
+}
+
+// UpdateResponseCacheDisabled sets whether reviews of a repository bypass the LLM response cache
+func (r *RepositoryRepo) UpdateResponseCacheDisabled(id uint, disabled bool) error {
+	return r.db.Model(&model.Repository{}).Where("id = ?", id).Update("response_cache_disabled", disabled).Error
+}
+
+// UpdateConsensus sets the verifier provider and rejected finding action of a repository
+func (r *RepositoryRepo) UpdateConsensus(id uint, verifierID *uint, rejectedAction string) error {
+	return r.db.Model(&model.Repository{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"verifier_llm_provider_id": verifierID,
+		"rejected_finding_action":  rejectedAction,
+	}).Error
+}
+
+// HasLLMProvider reports whether an LLM provider belongs to the project
+func (r *RepositoryRepo) HasLLMProvider(projectID, providerID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.LLMProvider{}).
+		Where("id = ? AND project_id = ?", providerID, projectID).
+		Count(&count).Error
+	return count > 0, err
+}
+
 // SetWebhookStatus is the centralized function for updating webhook status
 // All webhook status changes should go through this function to maintain consistency
 func (r *RepositoryRepo) SetWebhookStatus(id uint, status string, errorMsg string) error {
@@ -142,10 +217,11 @@ func (r *RepositoryRepo) SetWebhookStatus(id uint, status string, errorMsg strin
 }
 
 // UpdateWebhook updates webhook information after successful creation
-func (r *RepositoryRepo) UpdateWebhook(id uint, webhookID int64, webhookURL string) error {
+func (r *RepositoryRepo) UpdateWebhook(id uint, webhookID int64, webhookURL string, webhookSecret string) error {
 	updates := map[string]interface{}{
 		"webhook_id":               &webhookID,
 		"webhook_url":              webhookURL,
+		"webhook_secret":           webhookSecret,
 		"webhook_status":           model.WebhookStatusActive,
 		"last_webhook_test_status": model.WebhookTestResultSuccess,
 		"last_webhook_test_at":     time.Now(),
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/pkg/crypto"
+	"gorm.io/gorm"
+)
+
+// apiTokenBytes is the entropy of an API token (excluding prefix)
+const apiTokenBytes = 32
+
+// lastUsedResolution limits how often last_used_at is written for busy tokens
+const lastUsedResolution = time.Minute
+
+// ErrInvalidScope is returned when an unknown scope is requested
+var ErrInvalidScope = errors.New("invalid scope")
+
+// CreateAPITokenInput holds parameters for creating an API token
+type CreateAPITokenInput struct {
+	Name      string
+	Type      model.APITokenType
+	Scopes    []string
+	ExpiresAt *time.Time
+	ProjectID uint
+	UserID    uint
+}
+
+// APITokenService manages scoped API tokens for CI jobs and scripts
+type APITokenService struct {
+	db *gorm.DB
+}
+
+// NewAPITokenService creates a new API token service
+func NewAPITokenService(db *gorm.DB) *APITokenService {
+	return &APITokenService{db: db}
+}
+
+// Create issues a new API token. The raw token is returned only here;
+// the database keeps its hash.
+func (s *APITokenService) Create(input CreateAPITokenInput) (*model.APIToken, string, error) {
+	if strings.TrimSpace(input.Name) == "" {
+		return nil, "", fmt.Errorf("token name is required")
+	}
+	if input.Type == "" {
+		input.Type = model.APITokenTypePersonal
+	}
+	if input.Type != model.APITokenTypePersonal && input.Type != model.APITokenTypeProject {
+		return nil, "", fmt.Errorf("invalid token type: %s", input.Type)
+	}
+	if len(input.Scopes) == 0 {
+		return nil, "", fmt.Errorf("at least one scope is required")
+	}
+	for _, scope := range input.Scopes {
+		if !isValidScope(scope) {
+			return nil, "", fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return nil, "", fmt.Errorf("expiry must be in the future")
+	}
+
+	secret, err := crypto.GenerateToken(apiTokenBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	raw := model.APITokenPrefix + secret
+
+	token := &model.APIToken{
+		Name:        strings.TrimSpace(input.Name),
+		Type:        input.Type,
+		TokenHash:   crypto.HashToken(raw),
+		TokenPrefix: raw[:len(model.APITokenPrefix)+6],
+		Scopes:      input.Scopes,
+		ExpiresAt:   input.ExpiresAt,
+		ProjectID:   input.ProjectID,
+		UserID:      input.UserID,
+	}
+	if err := s.db.Create(token).Error; err != nil {
+		return nil, "", fmt.Errorf("failed to create API token: %w", err)
+	}
+
+	return token, raw, nil
+}
+
+// List returns the tokens visible to a user in a project:
+// all project tokens plus the user's own personal tokens
+func (s *APITokenService) List(projectID, userID uint) ([]model.APIToken, error) {
+	var tokens []model.APIToken
+	err := s.db.Where("project_id = ?", projectID).
+		Where("type = ? OR user_id = ?", model.APITokenTypeProject, userID).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// Revoke revokes a token visible to the user in the project
+func (s *APITokenService) Revoke(id, projectID, userID uint) error {
+	result := s.db.Model(&model.APIToken{}).
+		Where("id = ? AND project_id = ? AND revoked_at IS NULL", id, projectID).
+		Where("type = ? OR user_id = ?", model.APITokenTypeProject, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke API token: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Authenticate resolves a raw API token and records its usage.
+// Tokens whose creator has been deactivated are rejected.
+func (s *APITokenService) Authenticate(raw, clientIP string) (*model.APIToken, error) {
+	var token model.APIToken
+	if err := s.db.Preload("User").Where("token_hash = ?", crypto.HashToken(raw)).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, fmt.Errorf("failed to query API token: %w", err)
+	}
+
+	now := time.Now()
+	if token.RevokedAt != nil {
+		return nil, ErrTokenRevoked
+	}
+	if !token.IsUsable(now) {
+		return nil, ErrInvalidToken
+	}
+	if token.User == nil || !token.User.IsActive {
+		return nil, ErrUserInactive
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedResolution || token.LastUsedIP != clientIP {
+		// Best-effort: a failed usage update must not block the request
+		s.db.Model(&model.APIToken{}).Where("id = ?", token.ID).UpdateColumns(map[string]interface{}{
+			"last_used_at": now,
+			"last_used_ip": truncate(clientIP, 64),
+		})
+	}
+
+	return &token, nil
+}
+
+// isValidScope checks a scope against model.ValidScopes
+func isValidScope(scope string) bool {
+	for _, s := range model.ValidScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
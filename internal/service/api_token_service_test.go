@@ -0,0 +1,90 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/handsoff/handsoff/internal/model"
+)
+
+func TestAPITokenAuthenticate(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.User{}, &model.APIToken{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	user := &model.User{Username: "ci", Password: "secret123", IsActive: true}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	svc := NewAPITokenService(db)
+	token, raw, err := svc.Create(CreateAPITokenInput{
+		Name:      "ci",
+		Type:      model.APITokenTypeProject,
+		Scopes:    []string{model.ScopeReviewsWrite},
+		ProjectID: 7,
+		UserID:    user.ID,
+	})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if !strings.HasPrefix(raw, model.APITokenPrefix) {
+		t.Errorf("Expected token prefix %q, got %q", model.APITokenPrefix, raw)
+	}
+	if token.TokenHash == raw {
+		t.Error("Token must not be stored in plain text")
+	}
+
+	authed, err := svc.Authenticate(raw, "10.0.0.1")
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	if authed.ProjectID != 7 {
+		t.Errorf("Expected project 7, got %d", authed.ProjectID)
+	}
+	if !authed.HasScope(model.ScopeReviewsRead) || authed.HasScope(model.ScopeAdmin) {
+		t.Errorf("Unexpected scopes: %v", authed.Scopes)
+	}
+
+	var stored model.APIToken
+	db.First(&stored, token.ID)
+	if stored.LastUsedAt == nil || stored.LastUsedIP != "10.0.0.1" {
+		t.Error("Expected last used timestamp and IP to be recorded")
+	}
+
+	if err := svc.Revoke(token.ID, 7, user.ID); err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+	if _, err := svc.Authenticate(raw, "10.0.0.1"); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("Expected ErrTokenRevoked, got %v", err)
+	}
+}
+
+func TestAPITokenCreateValidation(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.APIToken{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	svc := NewAPITokenService(db)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name  string
+		input CreateAPITokenInput
+	}{
+		{"missing name", CreateAPITokenInput{Scopes: []string{model.ScopeAdmin}}},
+		{"no scopes", CreateAPITokenInput{Name: "x"}},
+		{"unknown scope", CreateAPITokenInput{Name: "x", Scopes: []string{"repos:delete"}}},
+		{"expired", CreateAPITokenInput{Name: "x", Scopes: []string{model.ScopeAdmin}, ExpiresAt: &past}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := svc.Create(tt.input); err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/pkg/config"
+	"github.com/handsoff/handsoff/pkg/crypto"
+	"github.com/handsoff/handsoff/pkg/jwt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Token revocation reasons
+const (
+	RevokeReasonLogout = "logout"
+)
+
+var (
+	// ErrInvalidToken is returned for unknown, malformed or expired tokens
+	ErrInvalidToken = errors.New("invalid or expired token")
+	// ErrTokenRevoked is returned when a token has been revoked
+	ErrTokenRevoked = errors.New("token has been revoked")
+	// ErrRefreshTokenReused is returned when an already rotated refresh token is presented again
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+	// ErrUserInactive is returned when the token owner is disabled or missing
+	ErrUserInactive = errors.New("user account is disabled")
+)
+
+// refreshTokenBytes is the entropy of a refresh token
+const refreshTokenBytes = 32
+
+// TokenPair is the credential set returned on login and refresh
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"` // Access token lifetime in seconds
+}
+
+// ClientMeta describes the client a refresh token was issued to
+type ClientMeta struct {
+	UserAgent string
+	ClientIP  string
+}
+
+// AuthTokenService issues, rotates and revokes authentication tokens.
+// Access tokens are short-lived JWTs; refresh tokens are opaque, stored hashed
+// and rotated on every use.
+type AuthTokenService struct {
+	db            *gorm.DB
+	jwtGen        *jwt.Generator
+	refreshExpiry time.Duration
+}
+
+// NewAuthTokenService creates a new auth token service
+func NewAuthTokenService(db *gorm.DB, cfg *config.Config) *AuthTokenService {
+	return &AuthTokenService{
+		db:            db,
+		jwtGen:        jwt.NewGenerator(cfg.Security.JWTSecret, cfg.Security.JWTExpiry),
+		refreshExpiry: cfg.Security.JWTRefreshExpiry,
+	}
+}
+
+// IssueTokenPair creates a new access token and starts a new refresh token family
+func (s *AuthTokenService) IssueTokenPair(user *model.User, meta ClientMeta) (*TokenPair, error) {
+	familyID, err := crypto.GenerateToken(24)
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(s.db, user, familyID, meta, nil)
+}
+
+// issue generates a token pair inside the given transaction.
+// If previous is set, it is marked as rotated into the new refresh token.
+func (s *AuthTokenService) issue(tx *gorm.DB, user *model.User, familyID string, meta ClientMeta, previous *model.RefreshToken) (*TokenPair, error) {
+	accessToken, err := s.jwtGen.GenerateIssuedAt(user.ID, user.Username, accessTokenIssuedAt(user, time.Now()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	rawRefresh, err := crypto.GenerateToken(refreshTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh := &model.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: crypto.HashToken(rawRefresh),
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(s.refreshExpiry),
+		UserAgent: truncate(meta.UserAgent, 255),
+		ClientIP:  truncate(meta.ClientIP, 64),
+	}
+	if err := tx.Create(refresh).Error; err != nil {
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	if previous != nil {
+		now := time.Now()
+		if err := tx.Model(previous).Updates(map[string]interface{}{
+			"revoked_at":     now,
+			"replaced_by_id": refresh.ID,
+		}).Error; err != nil {
+			return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+		}
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: rawRefresh,
+		ExpiresIn:    int64(s.jwtGen.Expiry().Seconds()),
+	}, nil
+}
+
+// accessTokenIssuedAt returns the "iat" of an access token issued now. JWT timestamps
+// have second precision, so a token issued in the second the user's sessions were
+// revoked (e.g. right after a password change) is stamped with the next second,
+// otherwise it would be rejected along with the revoked ones.
+func accessTokenIssuedAt(user *model.User, now time.Time) time.Time {
+	if user.TokensRevokedAt != nil {
+		if next := user.TokensRevokedAt.Truncate(time.Second).Add(time.Second); now.Before(next) {
+			return next
+		}
+	}
+	return now
+}
+
+// Refresh exchanges a refresh token for a new token pair (rotation).
+// Presenting a token that was already rotated revokes the whole family,
+// since it means the token was copied by someone else.
+func (s *AuthTokenService) Refresh(rawRefresh string, meta ClientMeta) (*TokenPair, *model.User, error) {
+	var pair *TokenPair
+	var user model.User
+	var reused bool
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var current model.RefreshToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", crypto.HashToken(rawRefresh)).
+			First(&current).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidToken
+			}
+			return fmt.Errorf("failed to query refresh token: %w", err)
+		}
+
+		if !current.IsActive(time.Now()) {
+			if current.RevokedAt == nil {
+				return ErrInvalidToken
+			}
+			if current.ReplacedByID != nil {
+				reused = true
+			}
+			return ErrTokenRevoked
+		}
+
+		if err := tx.First(&user, current.UserID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserInactive
+			}
+			return fmt.Errorf("failed to query user: %w", err)
+		}
+		if !user.IsActive {
+			return ErrUserInactive
+		}
+
+		var err error
+		pair, err = s.issue(tx, &user, current.FamilyID, meta, &current)
+		return err
+	})
+
+	if reused {
+		// Revoke outside the failed transaction so the revocation is persisted
+		if revokeErr := s.revokeFamilyByToken(rawRefresh); revokeErr != nil {
+			return nil, nil, revokeErr
+		}
+		return nil, nil, ErrRefreshTokenReused
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return pair, &user, nil
+}
+
+// RevokeRefreshToken revokes the family of the given refresh token (logout)
+func (s *AuthTokenService) RevokeRefreshToken(rawRefresh string) error {
+	return s.revokeFamilyByToken(rawRefresh)
+}
+
+func (s *AuthTokenService) revokeFamilyByToken(rawRefresh string) error {
+	var token model.RefreshToken
+	if err := s.db.Where("token_hash = ?", crypto.HashToken(rawRefresh)).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to query refresh token: %w", err)
+	}
+
+	if err := s.db.Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", token.FamilyID).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// RevokeAccessToken adds an access token to the revocation list until it expires
+func (s *AuthTokenService) RevokeAccessToken(claims *jwt.Claims, reason string) error {
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return nil
+	}
+
+	entry := &model.RevokedToken{
+		TokenID:   claims.ID,
+		UserID:    claims.UserID,
+		ExpiresAt: claims.ExpiresAt.Time,
+		Reason:    reason,
+	}
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+
+	// Entries past their expiry are useless, prune them opportunistically
+	s.db.Where("expires_at < ?", time.Now()).Delete(&model.RevokedToken{})
+	return nil
+}
+
+// RevokeAllForUser invalidates every access and refresh token of a user
+func (s *AuthTokenService) RevokeAllForUser(userID uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return revokeUserSessions(tx, userID)
+	})
+}
+
+// revokeUserSessions invalidates all tokens of a user within a transaction.
+// Access tokens are rejected via users.tokens_revoked_at, refresh tokens are revoked directly.
+func revokeUserSessions(tx *gorm.DB, userID uint) error {
+	now := time.Now()
+	if err := tx.Model(&model.User{}).Where("id = ?", userID).
+		Update("tokens_revoked_at", now).Error; err != nil {
+		return fmt.Errorf("failed to revoke access tokens: %w", err)
+	}
+	if err := tx.Model(&model.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now).Error; err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// ValidateAccessToken parses an access token and checks it against the revocation state
+func (s *AuthTokenService) ValidateAccessToken(tokenString string) (*jwt.Claims, error) {
+	claims, err := s.jwtGen.Validate(tokenString)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if claims.ID != "" {
+		var count int64
+		if err := s.db.Model(&model.RevokedToken{}).Where("token_id = ?", claims.ID).Count(&count).Error; err != nil {
+			return nil, fmt.Errorf("failed to check token revocation: %w", err)
+		}
+		if count > 0 {
+			return nil, ErrTokenRevoked
+		}
+	}
+
+	var user model.User
+	if err := s.db.Select("id", "is_active", "tokens_revoked_at").First(&user, claims.UserID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserInactive
+		}
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+	if !user.IsActive {
+		return nil, ErrUserInactive
+	}
+	// JWT timestamps have second precision, so a token issued within the revocation's
+	// second is revoked too; tokens issued after it are stamped with the next second
+	if user.TokensRevokedAt != nil && claims.IssuedAt != nil &&
+		!claims.IssuedAt.Time.After(user.TokensRevokedAt.Truncate(time.Second)) {
+		return nil, ErrTokenRevoked
+	}
+
+	return claims, nil
+}
+
+// truncate limits a string to max bytes
+func truncate(s string, max int) string {
+	if len(s) > max {
+		return s[:max]
+	}
+	return s
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/pkg/config"
+)
+
+func setupAuthTokenService(t *testing.T) (*AuthTokenService, *model.User) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.User{}, &model.RefreshToken{}, &model.RevokedToken{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	user := &model.User{Username: "alice", Password: "secret123", IsActive: true}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	cfg := &config.Config{Security: config.SecurityConfig{
+		JWTSecret:        "test-secret",
+		JWTExpiry:        15 * time.Minute,
+		JWTRefreshExpiry: time.Hour,
+	}}
+	return NewAuthTokenService(db, cfg), user
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	svc, user := setupAuthTokenService(t)
+
+	pair, err := svc.IssueTokenPair(user, ClientMeta{})
+	if err != nil {
+		t.Fatalf("IssueTokenPair failed: %v", err)
+	}
+
+	rotated, _, err := svc.Refresh(pair.RefreshToken, ClientMeta{})
+	if err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if rotated.RefreshToken == pair.RefreshToken {
+		t.Error("Expected refresh token to be rotated")
+	}
+
+	// Reusing the old token revokes the whole family
+	if _, _, err := svc.Refresh(pair.RefreshToken, ClientMeta{}); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Errorf("Expected ErrRefreshTokenReused, got %v", err)
+	}
+	if _, _, err := svc.Refresh(rotated.RefreshToken, ClientMeta{}); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("Expected rotated token to be revoked after reuse, got %v", err)
+	}
+}
+
+func TestRevokeAccessToken(t *testing.T) {
+	svc, user := setupAuthTokenService(t)
+
+	pair, err := svc.IssueTokenPair(user, ClientMeta{})
+	if err != nil {
+		t.Fatalf("IssueTokenPair failed: %v", err)
+	}
+
+	claims, err := svc.ValidateAccessToken(pair.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken failed: %v", err)
+	}
+
+	if err := svc.RevokeAccessToken(claims, RevokeReasonLogout); err != nil {
+		t.Fatalf("RevokeAccessToken failed: %v", err)
+	}
+	if _, err := svc.ValidateAccessToken(pair.AccessToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("Expected ErrTokenRevoked, got %v", err)
+	}
+}
+
+func TestDeactivateUserRevokesSessions(t *testing.T) {
+	svc, user := setupAuthTokenService(t)
+
+	pair, err := svc.IssueTokenPair(user, ClientMeta{})
+	if err != nil {
+		t.Fatalf("IssueTokenPair failed: %v", err)
+	}
+
+	if err := NewUserService(svc.db).DeactivateUser(user.ID); err != nil {
+		t.Fatalf("DeactivateUser failed: %v", err)
+	}
+
+	if _, err := svc.ValidateAccessToken(pair.AccessToken); !errors.Is(err, ErrUserInactive) {
+		t.Errorf("Expected ErrUserInactive, got %v", err)
+	}
+	if _, _, err := svc.Refresh(pair.RefreshToken, ClientMeta{}); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("Expected refresh token to be revoked, got %v", err)
+	}
+}
+
+func TestChangePasswordRevokesSessions(t *testing.T) {
+	svc, user := setupAuthTokenService(t)
+
+	pair, err := svc.IssueTokenPair(user, ClientMeta{})
+	if err != nil {
+		t.Fatalf("IssueTokenPair failed: %v", err)
+	}
+
+	// Issued moments before the revocation, in the same second
+	users := NewUserService(svc.db)
+	if err := users.ChangePassword(user.ID, "secret123", "secret456"); err != nil {
+		t.Fatalf("ChangePassword failed: %v", err)
+	}
+	if _, err := svc.ValidateAccessToken(pair.AccessToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("Expected ErrTokenRevoked, got %v", err)
+	}
+	if _, _, err := svc.Refresh(pair.RefreshToken, ClientMeta{}); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("Expected refresh token to be revoked, got %v", err)
+	}
+
+	// The session the password change hands out right away is not
+	user, err = users.GetUser(user.ID)
+	if err != nil {
+		t.Fatalf("GetUser failed: %v", err)
+	}
+	pair, err = svc.IssueTokenPair(user, ClientMeta{})
+	if err != nil {
+		t.Fatalf("IssueTokenPair failed: %v", err)
+	}
+	if _, err := svc.ValidateAccessToken(pair.AccessToken); err != nil {
+		t.Errorf("ValidateAccessToken after the password change failed: %v", err)
+	}
+	if _, _, err := svc.Refresh(pair.RefreshToken, ClientMeta{}); err != nil {
+		t.Errorf("Refresh after the password change failed: %v", err)
+	}
+}
+
+func TestRefreshExpiredToken(t *testing.T) {
+	svc, user := setupAuthTokenService(t)
+
+	pair, err := svc.IssueTokenPair(user, ClientMeta{})
+	if err != nil {
+		t.Fatalf("IssueTokenPair failed: %v", err)
+	}
+	svc.db.Model(&model.RefreshToken{}).Where("user_id = ?", user.ID).
+		Update("expires_at", time.Now().Add(-time.Minute))
+
+	if _, _, err := svc.Refresh(pair.RefreshToken, ClientMeta{}); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken, got %v", err)
+	}
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/handsoff/handsoff/internal/model"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidBudget is returned when a budget fails validation
+var ErrInvalidBudget = errors.New("invalid budget")
+
+// ErrBudgetNotFound is returned when a budget does not exist in the project
+var ErrBudgetNotFound = errors.New("budget not found")
+
+// BudgetInput holds the editable fields of a budget
+type BudgetInput struct {
+	RepositoryID *uint
+	Metric       model.BudgetMetric
+	SoftLimit    float64
+	HardLimit    float64
+	Currency     string
+	IsActive     bool
+}
+
+// BudgetStatus is a budget with its usage in the current month
+type BudgetStatus struct {
+	model.Budget
+	Used        float64   `json:"used"`
+	Percent     float64   `json:"percent"` // Of the hard limit (soft limit when there is none)
+	State       string    `json:"state"`   // ok, warning, exceeded
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+}
+
+// BudgetService manages monthly token and cost budgets
+type BudgetService struct {
+	db *gorm.DB
+}
+
+// NewBudgetService creates a new budget service
+func NewBudgetService(db *gorm.DB) *BudgetService {
+	return &BudgetService{db: db}
+}
+
+// List returns all budgets of a project
+func (s *BudgetService) List(projectID uint) ([]model.Budget, error) {
+	var budgets []model.Budget
+	err := s.db.Where("project_id = ?", projectID).
+		Preload("Repository").
+		Order("repository_id IS NOT NULL, repository_id, metric").
+		Find(&budgets).Error
+	return budgets, err
+}
+
+// Create adds a budget to the project
+func (s *BudgetService) Create(projectID uint, input BudgetInput) (*model.Budget, error) {
+	budget := &model.Budget{ProjectID: projectID}
+	applyBudgetInput(budget, input)
+	if err := s.validate(budget); err != nil {
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(budget).Error; err != nil {
+			return err
+		}
+		// GORM stores a false is_active as the column default (true)
+		if !input.IsActive {
+			return tx.Model(budget).Update("is_active", false).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return budget, nil
+}
+
+// Update changes a budget of the project
+func (s *BudgetService) Update(id, projectID uint, input BudgetInput) (*model.Budget, error) {
+	budget, err := s.get(id, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	applyBudgetInput(budget, input)
+	budget.WarnedPeriod = "" // Re-arm the soft limit warning for the new limits
+	if err := s.validate(budget); err != nil {
+		return nil, err
+	}
+
+	budget.Repository = nil
+	if err := s.db.Save(budget).Error; err != nil {
+		return nil, err
+	}
+	return budget, nil
+}
+
+// Delete removes a budget of the project
+func (s *BudgetService) Delete(id, projectID uint) error {
+	result := s.db.Where("id = ? AND project_id = ?", id, projectID).Delete(&model.Budget{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrBudgetNotFound
+	}
+	return nil
+}
+
+// ProjectStatus returns the current month's state of every active budget of the project
+func (s *BudgetService) ProjectStatus(projectID uint, now time.Time) ([]BudgetStatus, error) {
+	var budgets []model.Budget
+	err := s.db.Where("project_id = ? AND is_active = ?", projectID, true).
+		Preload("Repository").
+		Order("repository_id IS NOT NULL, repository_id, metric").
+		Find(&budgets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return s.evaluate(budgets, now)
+}
+
+// RepositoryStatus returns the state of the budgets that apply to a repository:
+// the project-wide budgets and the repository's own
+func (s *BudgetService) RepositoryStatus(projectID, repositoryID uint, now time.Time) ([]BudgetStatus, error) {
+	var budgets []model.Budget
+	err := s.db.Where("project_id = ? AND is_active = ? AND (repository_id IS NULL OR repository_id = ?)",
+		projectID, true, repositoryID).
+		Find(&budgets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return s.evaluate(budgets, now)
+}
+
+// MarkWarned records that the soft limit warning was raised for the budget's current month.
+// Returns false if it had already been raised (e.g. by another worker).
+func (s *BudgetService) MarkWarned(status *BudgetStatus) (bool, error) {
+	period := status.PeriodStart.Format("2006-01")
+	result := s.db.Model(&model.Budget{}).
+		Where("id = ? AND (warned_period IS NULL OR warned_period <> ?)", status.ID, period).
+		Update("warned_period", period)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Describe formats a budget status for logs and review error messages,
+// e.g. "monthly tokens budget of repository #3: 1200000 of 1000000 used"
+func (st *BudgetStatus) Describe() string {
+	scope := "project"
+	if st.RepositoryID != nil {
+		scope = fmt.Sprintf("repository #%d", *st.RepositoryID)
+	}
+
+	limit := st.HardLimit
+	if st.State == model.BudgetStateWarning {
+		limit = st.SoftLimit
+	}
+	if st.Metric == model.BudgetMetricCost {
+		return fmt.Sprintf("monthly cost budget of %s: %.2f of %.2f %s used", scope, st.Used, limit, st.Currency)
+	}
+	return fmt.Sprintf("monthly tokens budget of %s: %.0f of %.0f used", scope, st.Used, limit)
+}
+
+// evaluate computes usage and state of the budgets for the month containing now
+func (s *BudgetService) evaluate(budgets []model.Budget, now time.Time) ([]BudgetStatus, error) {
+	start, end := budgetPeriod(now)
+
+	statuses := make([]BudgetStatus, 0, len(budgets))
+	for _, budget := range budgets {
+		used, err := s.usage(&budget, start, end)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, newBudgetStatus(budget, used, start, end))
+	}
+	return statuses, nil
+}
+
+// usage sums the budget's metric over the period. Cost budgets only count the cost
+// of calls priced in their currency.
+func (s *BudgetService) usage(budget *model.Budget, start, end time.Time) (float64, error) {
+	query := s.db.Model(&model.LLMUsageLog{}).
+		Where("project_id = ? AND created_at >= ? AND created_at < ?", budget.ProjectID, start, end)
+	column := "total_tokens"
+	if budget.Metric == model.BudgetMetricCost {
+		column = "cost"
+		query = query.Where("currency = ?", budget.Currency)
+	}
+	if budget.RepositoryID != nil {
+		query = query.Where("repository_id = ?", *budget.RepositoryID)
+	}
+
+	var used float64
+	err := query.Select(fmt.Sprintf("COALESCE(SUM(%s), 0)", column)).Scan(&used).Error
+	return used, err
+}
+
+// newBudgetStatus derives the state of a budget from its usage
+func newBudgetStatus(budget model.Budget, used float64, start, end time.Time) BudgetStatus {
+	status := BudgetStatus{
+		Budget:      budget,
+		Used:        used,
+		State:       model.BudgetStateOK,
+		PeriodStart: start,
+		PeriodEnd:   end,
+	}
+
+	switch {
+	case budget.HardLimit > 0 && used >= budget.HardLimit:
+		status.State = model.BudgetStateExceeded
+	case budget.SoftLimit > 0 && used >= budget.SoftLimit:
+		status.State = model.BudgetStateWarning
+	}
+
+	if limit := budget.HardLimit; limit > 0 {
+		status.Percent = used / limit * 100
+	} else if limit := budget.SoftLimit; limit > 0 {
+		status.Percent = used / limit * 100
+	}
+	return status
+}
+
+// budgetPeriod returns the calendar month containing now
+func budgetPeriod(now time.Time) (time.Time, time.Time) {
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	return start, start.AddDate(0, 1, 0)
+}
+
+func applyBudgetInput(budget *model.Budget, input BudgetInput) {
+	budget.RepositoryID = input.RepositoryID
+	budget.Metric = input.Metric
+	budget.SoftLimit = input.SoftLimit
+	budget.HardLimit = input.HardLimit
+	budget.Currency = strings.ToUpper(strings.TrimSpace(input.Currency))
+	budget.IsActive = input.IsActive
+}
+
+func (s *BudgetService) validate(budget *model.Budget) error {
+	if budget.Metric != model.BudgetMetricTokens && budget.Metric != model.BudgetMetricCost {
+		return fmt.Errorf("%w: metric must be %q or %q", ErrInvalidBudget, model.BudgetMetricTokens, model.BudgetMetricCost)
+	}
+	if budget.SoftLimit < 0 || budget.HardLimit < 0 {
+		return fmt.Errorf("%w: limits must not be negative", ErrInvalidBudget)
+	}
+	if budget.SoftLimit == 0 && budget.HardLimit == 0 {
+		return fmt.Errorf("%w: a soft or hard limit is required", ErrInvalidBudget)
+	}
+	if budget.HardLimit > 0 && budget.SoftLimit > budget.HardLimit {
+		return fmt.Errorf("%w: soft limit must not exceed the hard limit", ErrInvalidBudget)
+	}
+	if budget.Metric == model.BudgetMetricTokens {
+		budget.Currency = ""
+	} else if budget.Currency == "" {
+		// Costs are only comparable within one currency
+		return fmt.Errorf("%w: a currency is required for cost budgets", ErrInvalidBudget)
+	}
+
+	if budget.RepositoryID != nil {
+		var count int64
+		s.db.Model(&model.Repository{}).
+			Where("id = ? AND project_id = ?", *budget.RepositoryID, budget.ProjectID).
+			Count(&count)
+		if count == 0 {
+			return fmt.Errorf("%w: repository not found", ErrInvalidBudget)
+		}
+	}
+
+	// One budget per scope and metric
+	query := s.db.Model(&model.Budget{}).
+		Where("project_id = ? AND metric = ? AND id <> ?", budget.ProjectID, budget.Metric, budget.ID)
+	if budget.RepositoryID != nil {
+		query = query.Where("repository_id = ?", *budget.RepositoryID)
+	} else {
+		query = query.Where("repository_id IS NULL")
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: a %s budget already exists for this scope", ErrInvalidBudget, budget.Metric)
+	}
+	return nil
+}
+
+func (s *BudgetService) get(id, projectID uint) (*model.Budget, error) {
+	var budget model.Budget
+	err := s.db.Where("id = ? AND project_id = ?", id, projectID).First(&budget).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrBudgetNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &budget, nil
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/handsoff/handsoff/internal/model"
+)
+
+func TestBudgetServiceStatus(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.Budget{}, &model.LLMUsageLog{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	svc := NewBudgetService(db)
+
+	repo := model.Repository{Name: "repo", ProjectID: 1}
+	other := model.Repository{Name: "other", ProjectID: 1}
+	db.Create(&repo)
+	db.Create(&other)
+
+	now := time.Date(2025, 3, 15, 12, 0, 0, 0, time.UTC)
+	logs := []model.LLMUsageLog{
+		{CreatedAt: now.AddDate(0, 0, -1), ProjectID: 1, RepositoryID: repo.ID, TotalTokens: 800, Cost: 1.5, Currency: "USD"},
+		{CreatedAt: now.AddDate(0, 0, -2), ProjectID: 1, RepositoryID: other.ID, TotalTokens: 300, Cost: 0.5, Currency: "USD"},
+		{CreatedAt: now.AddDate(0, 0, -2), ProjectID: 1, RepositoryID: other.ID, Cost: 50, Currency: "CNY"}, // Not in the budget's currency
+		{CreatedAt: now.AddDate(0, -1, 0), ProjectID: 1, RepositoryID: repo.ID, TotalTokens: 5000},          // Previous month
+		{CreatedAt: now.AddDate(0, 0, -1), ProjectID: 2, RepositoryID: repo.ID, TotalTokens: 5000},          // Other project
+	}
+	for i := range logs {
+		logs[i].ModelName = "gpt-4"
+		logs[i].RequestType = model.UsageTypeCodeReview
+		logs[i].Status = model.UsageStatusSuccess
+		if err := db.Create(&logs[i]).Error; err != nil {
+			t.Fatalf("Failed to create usage log: %v", err)
+		}
+	}
+
+	repoID := repo.ID
+	if _, err := svc.Create(1, BudgetInput{Metric: model.BudgetMetricTokens, SoftLimit: 1000, HardLimit: 2000, IsActive: true}); err != nil {
+		t.Fatalf("Failed to create project budget: %v", err)
+	}
+	if _, err := svc.Create(1, BudgetInput{RepositoryID: &repoID, Metric: model.BudgetMetricTokens, HardLimit: 800, IsActive: true}); err != nil {
+		t.Fatalf("Failed to create repository budget: %v", err)
+	}
+	if _, err := svc.Create(1, BudgetInput{Metric: model.BudgetMetricCost, HardLimit: 10, Currency: "usd", IsActive: true}); err != nil {
+		t.Fatalf("Failed to create cost budget: %v", err)
+	}
+
+	statuses, err := svc.ProjectStatus(1, now)
+	if err != nil {
+		t.Fatalf("ProjectStatus failed: %v", err)
+	}
+
+	expected := map[string]struct {
+		used  float64
+		state string
+	}{
+		"project/tokens":    {1100, model.BudgetStateWarning},
+		"repository/tokens": {800, model.BudgetStateExceeded},
+		"project/cost":      {2, model.BudgetStateOK},
+	}
+	if len(statuses) != len(expected) {
+		t.Fatalf("Expected %d statuses, got %d", len(expected), len(statuses))
+	}
+	for _, st := range statuses {
+		scope := "project"
+		if st.RepositoryID != nil {
+			scope = "repository"
+		}
+		want := expected[scope+"/"+string(st.Metric)]
+		if st.Used != want.used || st.State != want.state {
+			t.Errorf("%s/%s: got used=%v state=%s, want used=%v state=%s",
+				scope, st.Metric, st.Used, st.State, want.used, want.state)
+		}
+	}
+
+	// Only the project-wide budgets apply to the other repository
+	statuses, err = svc.RepositoryStatus(1, other.ID, now)
+	if err != nil {
+		t.Fatalf("RepositoryStatus failed: %v", err)
+	}
+	for _, st := range statuses {
+		if st.RepositoryID != nil {
+			t.Errorf("Unexpected repository budget %d for another repository", st.ID)
+		}
+	}
+
+	// The soft limit warning is raised once per month
+	warning := statuses[0]
+	for _, st := range statuses {
+		if st.State == model.BudgetStateWarning {
+			warning = st
+		}
+	}
+	if first, err := svc.MarkWarned(&warning); err != nil || !first {
+		t.Errorf("First MarkWarned: first=%v err=%v", first, err)
+	}
+	if first, err := svc.MarkWarned(&warning); err != nil || first {
+		t.Errorf("Second MarkWarned: first=%v err=%v", first, err)
+	}
+}
+
+func TestBudgetServiceValidation(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.Budget{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	svc := NewBudgetService(db)
+
+	foreignRepo := model.Repository{Name: "foreign", ProjectID: 2}
+	db.Create(&foreignRepo)
+
+	if _, err := svc.Create(1, BudgetInput{Metric: model.BudgetMetricTokens, HardLimit: 100, IsActive: true}); err != nil {
+		t.Fatalf("Failed to create budget: %v", err)
+	}
+
+	inactive, err := svc.Create(1, BudgetInput{Metric: model.BudgetMetricCost, HardLimit: 10, Currency: "USD"})
+	if err != nil {
+		t.Fatalf("Failed to create inactive budget: %v", err)
+	}
+	var stored model.Budget
+	db.First(&stored, inactive.ID)
+	if stored.ID == 0 || stored.IsActive {
+		t.Errorf("Expected the budget stored inactive, got id=%d is_active=%v", stored.ID, stored.IsActive)
+	}
+
+	tests := []struct {
+		name  string
+		input BudgetInput
+	}{
+		{"Unknown metric", BudgetInput{Metric: "requests", HardLimit: 10}},
+		{"No limits", BudgetInput{Metric: model.BudgetMetricCost}},
+		{"Negative limit", BudgetInput{Metric: model.BudgetMetricCost, HardLimit: -1}},
+		{"Soft above hard", BudgetInput{Metric: model.BudgetMetricCost, SoftLimit: 20, HardLimit: 10}},
+		{"Cost without currency", BudgetInput{Metric: model.BudgetMetricCost, HardLimit: 10}},
+		{"Repository of another project", BudgetInput{RepositoryID: &foreignRepo.ID, Metric: model.BudgetMetricTokens, HardLimit: 10}},
+		{"Duplicate scope and metric", BudgetInput{Metric: model.BudgetMetricTokens, HardLimit: 500}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.Create(1, tt.input); !errors.Is(err, ErrInvalidBudget) {
+				t.Errorf("Expected ErrInvalidBudget, got %v", err)
+			}
+		})
+	}
+}
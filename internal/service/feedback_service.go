@@ -0,0 +1,234 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/handsoff/handsoff/internal/model"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidFeedback is returned when suggestion feedback fails validation
+var ErrInvalidFeedback = errors.New("invalid feedback")
+
+// ErrSuggestionNotFound is returned when a suggestion does not exist in the project
+var ErrSuggestionNotFound = errors.New("suggestion not found")
+
+// maxFeedbackReason caps the stored reason length
+const maxFeedbackReason = 2000
+
+// FeedbackInput is developer feedback on a suggestion
+type FeedbackInput struct {
+	Status string // accepted, fixed, wont_fix, false_positive ("" clears the feedback)
+	Reason string
+	By     string // HandsOff or GitLab username
+	Source string // api, gitlab
+}
+
+// PrecisionRow counts feedback for one value of a dimension (a category, model or prompt version)
+type PrecisionRow struct {
+	Key           string  `json:"key"`
+	Accepted      int64   `json:"accepted"`
+	Fixed         int64   `json:"fixed"`
+	WontFix       int64   `json:"wont_fix"`
+	FalsePositive int64   `json:"false_positive"`
+	Total         int64   `json:"total"`     // Findings with feedback
+	Precision     float64 `json:"precision"` // Share of findings with feedback that were real issues, in percent
+}
+
+// PrecisionStats reports how many findings developers judged real, by dimension
+type PrecisionStats struct {
+	ByCategory      []PrecisionRow `json:"by_category"`
+	ByModel         []PrecisionRow `json:"by_model"`
+	ByPromptVersion []PrecisionRow `json:"by_prompt_version"`
+}
+
+// FeedbackService records developer feedback on suggestions
+type FeedbackService struct {
+	db *gorm.DB
+}
+
+// NewFeedbackService creates a new feedback service
+func NewFeedbackService(db *gorm.DB) *FeedbackService {
+	return &FeedbackService{db: db}
+}
+
+// SetFeedback records feedback on a suggestion of the project
+func (s *FeedbackService) SetFeedback(id, projectID uint, input FeedbackInput, now time.Time) (*model.FixSuggestion, error) {
+	var suggestion model.FixSuggestion
+	err := s.db.
+		Joins("JOIN review_results ON review_results.id = fix_suggestions.review_result_id").
+		Joins("JOIN repositories ON repositories.id = review_results.repository_id").
+		Where("fix_suggestions.id = ? AND repositories.project_id = ?", id, projectID).
+		First(&suggestion).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSuggestionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.apply(&suggestion, input, now); err != nil {
+		return nil, err
+	}
+	return &suggestion, nil
+}
+
+// SetFeedbackByNumber records feedback on finding #number of the review's latest
+// MR comment, as referred to in a reply
+func (s *FeedbackService) SetFeedbackByNumber(reviewID uint, number int, input FeedbackInput, now time.Time) (*model.FixSuggestion, error) {
+	suggestion, err := s.SuggestionByNumber(reviewID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.apply(suggestion, input, now); err != nil {
+		return nil, err
+	}
+	return suggestion, nil
+}
+
+// SuggestionByNumber returns finding #number of the review's latest MR comment
+func (s *FeedbackService) SuggestionByNumber(reviewID uint, number int) (*model.FixSuggestion, error) {
+	if number <= 0 {
+		return nil, ErrSuggestionNotFound
+	}
+
+	var suggestion model.FixSuggestion
+	err := s.db.Where("review_result_id = ? AND number = ?", reviewID, number).
+		Order("id DESC").
+		First(&suggestion).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSuggestionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &suggestion, nil
+}
+
+func (s *FeedbackService) apply(suggestion *model.FixSuggestion, input FeedbackInput, now time.Time) error {
+	switch input.Status {
+	case "", model.FeedbackAccepted, model.FeedbackFixed, model.FeedbackWontFix, model.FeedbackFalsePositive:
+	default:
+		return fmt.Errorf("%w: status must be one of %s, %s, %s, %s", ErrInvalidFeedback,
+			model.FeedbackAccepted, model.FeedbackFixed, model.FeedbackWontFix, model.FeedbackFalsePositive)
+	}
+	reason := strings.TrimSpace(input.Reason)
+	if len(reason) > maxFeedbackReason {
+		return fmt.Errorf("%w: reason must be at most %d characters", ErrInvalidFeedback, maxFeedbackReason)
+	}
+
+	suggestion.FeedbackStatus = input.Status
+	suggestion.FeedbackReason = reason
+	suggestion.FeedbackBy = input.By
+	suggestion.FeedbackSource = input.Source
+	suggestion.FeedbackAt = &now
+	if input.Status == "" {
+		suggestion.FeedbackReason, suggestion.FeedbackBy, suggestion.FeedbackSource = "", "", ""
+		suggestion.FeedbackAt = nil
+	}
+
+	return s.db.Model(suggestion).Select(
+		"feedback_status", "feedback_reason", "feedback_by", "feedback_source", "feedback_at",
+	).Updates(suggestion).Error
+}
+
+// DismissedFingerprints returns the fingerprints of the review's suggestions that
+// were marked won't fix or false positive
+func (s *FeedbackService) DismissedFingerprints(reviewID uint) (map[string]bool, error) {
+	var fingerprints []string
+	err := s.db.Model(&model.FixSuggestion{}).
+		Where("review_result_id = ? AND fingerprint <> '' AND feedback_status IN ?",
+			reviewID, []string{model.FeedbackWontFix, model.FeedbackFalsePositive}).
+		Distinct().
+		Pluck("fingerprint", &fingerprints).Error
+	if err != nil {
+		return nil, err
+	}
+
+	dismissed := make(map[string]bool, len(fingerprints))
+	for _, fp := range fingerprints {
+		dismissed[fp] = true
+	}
+	return dismissed, nil
+}
+
+// GetPrecision returns feedback counts and precision per category, model and prompt
+// version for suggestions created in the period. Won't fix counts as a real issue.
+func (s *FeedbackService) GetPrecision(projectID uint, repositoryID *uint, startDate, endDate time.Time) (*PrecisionStats, error) {
+	stats := &PrecisionStats{}
+	for _, dimension := range []struct {
+		column string
+		rows   *[]PrecisionRow
+	}{
+		{"category", &stats.ByCategory},
+		{"model_name", &stats.ByModel},
+		{"prompt_version", &stats.ByPromptVersion},
+	} {
+		rows, err := s.precisionBy(dimension.column, projectID, repositoryID, startDate, endDate)
+		if err != nil {
+			return nil, err
+		}
+		*dimension.rows = rows
+	}
+	return stats, nil
+}
+
+func (s *FeedbackService) precisionBy(column string, projectID uint, repositoryID *uint, startDate, endDate time.Time) ([]PrecisionRow, error) {
+	query := s.db.Model(&model.FixSuggestion{}).
+		Joins("JOIN review_results ON review_results.id = fix_suggestions.review_result_id").
+		Joins("JOIN repositories ON repositories.id = review_results.repository_id").
+		Where("repositories.project_id = ? AND fix_suggestions.feedback_status <> ''", projectID).
+		Where("fix_suggestions.created_at >= ? AND fix_suggestions.created_at <= ?", startDate, endDate)
+	if repositoryID != nil {
+		query = query.Where("review_results.repository_id = ?", *repositoryID)
+	}
+
+	var counts []struct {
+		Dimension string
+		Status    string
+		Count     int64
+	}
+	err := query.
+		Select("fix_suggestions." + column + " as dimension, fix_suggestions.feedback_status as status, COUNT(*) as count").
+		Group("fix_suggestions." + column + ", fix_suggestions.feedback_status").
+		Order("dimension").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []PrecisionRow{}
+	index := map[string]int{}
+	for _, c := range counts {
+		i, ok := index[c.Dimension]
+		if !ok {
+			i = len(rows)
+			index[c.Dimension] = i
+			rows = append(rows, PrecisionRow{Key: c.Dimension})
+		}
+
+		row := &rows[i]
+		switch c.Status {
+		case model.FeedbackAccepted:
+			row.Accepted += c.Count
+		case model.FeedbackFixed:
+			row.Fixed += c.Count
+		case model.FeedbackWontFix:
+			row.WontFix += c.Count
+		case model.FeedbackFalsePositive:
+			row.FalsePositive += c.Count
+		}
+		row.Total += c.Count
+	}
+
+	for i := range rows {
+		if rows[i].Total > 0 {
+			rows[i].Precision = float64(rows[i].Total-rows[i].FalsePositive) / float64(rows[i].Total) * 100
+		}
+	}
+	return rows, nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/handsoff/handsoff/internal/model"
+)
+
+func TestFeedbackService(t *testing.T) {
+	db := setupTestDB(t)
+	svc := NewFeedbackService(db)
+	now := time.Now()
+
+	repo := model.Repository{Name: "api", ProjectID: 1}
+	db.Create(&repo)
+	review := model.ReviewResult{RepositoryID: repo.ID, MergeRequestID: 7}
+	db.Create(&review)
+
+	suggestions := []model.FixSuggestion{
+		{ReviewResultID: review.ID, FilePath: "a.go", Severity: "high", Description: "x", Number: 1,
+			Category: "security", ModelName: "gpt-4", PromptVersion: "v1", Fingerprint: "fp-1"},
+		{ReviewResultID: review.ID, FilePath: "b.go", Severity: "low", Description: "y", Number: 2,
+			Category: "security", ModelName: "gpt-4", PromptVersion: "v1", Fingerprint: "fp-2"},
+		{ReviewResultID: review.ID, FilePath: "c.go", Severity: "low", Description: "z", Number: 3,
+			Category: "style", ModelName: "claude", PromptVersion: "v2", Fingerprint: "fp-3"},
+	}
+	db.Create(&suggestions)
+
+	// Project scoping
+	if _, err := svc.SetFeedback(suggestions[0].ID, 2, FeedbackInput{Status: model.FeedbackAccepted}, now); !errors.Is(err, ErrSuggestionNotFound) {
+		t.Errorf("Expected ErrSuggestionNotFound for another project, got %v", err)
+	}
+	if _, err := svc.SetFeedback(suggestions[0].ID, 1, FeedbackInput{Status: "maybe"}, now); !errors.Is(err, ErrInvalidFeedback) {
+		t.Errorf("Expected ErrInvalidFeedback for an unknown status, got %v", err)
+	}
+
+	updated, err := svc.SetFeedback(suggestions[0].ID, 1, FeedbackInput{
+		Status: model.FeedbackAccepted, Reason: " good catch ", By: "alice", Source: model.FeedbackSourceAPI,
+	}, now)
+	if err != nil {
+		t.Fatalf("SetFeedback failed: %v", err)
+	}
+	if updated.FeedbackStatus != model.FeedbackAccepted || updated.FeedbackReason != "good catch" || updated.FeedbackAt == nil {
+		t.Errorf("Unexpected feedback: %+v", updated)
+	}
+
+	// Replies refer to findings by their number in the comment
+	if _, err := svc.SetFeedbackByNumber(review.ID, 2, FeedbackInput{Status: model.FeedbackFalsePositive, By: "bob"}, now); err != nil {
+		t.Fatalf("SetFeedbackByNumber failed: %v", err)
+	}
+	if _, err := svc.SetFeedbackByNumber(review.ID, 3, FeedbackInput{Status: model.FeedbackWontFix, By: "bob"}, now); err != nil {
+		t.Fatalf("SetFeedbackByNumber failed: %v", err)
+	}
+	if _, err := svc.SetFeedbackByNumber(review.ID, 9, FeedbackInput{Status: model.FeedbackFixed}, now); !errors.Is(err, ErrSuggestionNotFound) {
+		t.Errorf("Expected ErrSuggestionNotFound for an unknown number, got %v", err)
+	}
+
+	dismissed, err := svc.DismissedFingerprints(review.ID)
+	if err != nil {
+		t.Fatalf("DismissedFingerprints failed: %v", err)
+	}
+	if len(dismissed) != 2 || !dismissed["fp-2"] || !dismissed["fp-3"] {
+		t.Errorf("Expected fp-2 and fp-3 dismissed, got %v", dismissed)
+	}
+
+	stats, err := svc.GetPrecision(1, nil, now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetPrecision failed: %v", err)
+	}
+	if len(stats.ByCategory) != 2 || len(stats.ByModel) != 2 || len(stats.ByPromptVersion) != 2 {
+		t.Fatalf("Unexpected dimensions: %+v", stats)
+	}
+	security := stats.ByCategory[0]
+	if security.Key != "security" || security.Total != 2 || security.Accepted != 1 ||
+		security.FalsePositive != 1 || security.Precision != 50 {
+		t.Errorf("Unexpected security precision: %+v", security)
+	}
+	// Won't fix is a real issue
+	if style := stats.ByCategory[1]; style.Precision != 100 {
+		t.Errorf("Expected 100%% precision for style, got %+v", style)
+	}
+
+	// Clearing feedback removes it from the statistics
+	if _, err := svc.SetFeedback(suggestions[0].ID, 1, FeedbackInput{}, now); err != nil {
+		t.Fatalf("Clearing feedback failed: %v", err)
+	}
+	stats, _ = svc.GetPrecision(1, nil, now.Add(-time.Hour), now.Add(time.Hour))
+	if stats.ByModel[1].Key != "gpt-4" || stats.ByModel[1].Total != 1 {
+		t.Errorf("Expected 1 gpt-4 finding with feedback after clearing, got %+v", stats.ByModel)
+	}
+}
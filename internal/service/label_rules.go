@@ -0,0 +1,93 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+)
+
+// ErrInvalidLabelRules is returned when label rules fail validation
+var ErrInvalidLabelRules = errors.New("invalid label rules")
+
+// maxLabelRules caps the number of label rules of a repository
+const maxLabelRules = 50
+
+// ValidateLabelRules checks label rules
+func ValidateLabelRules(rules []model.LabelRule) error {
+	if len(rules) > maxLabelRules {
+		return fmt.Errorf("%w: at most %d rules", ErrInvalidLabelRules, maxLabelRules)
+	}
+
+	for i, rule := range rules {
+		label := strings.TrimSpace(rule.Label)
+		switch {
+		case label == "" || len(label) > 255:
+			return fmt.Errorf("%w: rule %d: label must be 1-255 characters", ErrInvalidLabelRules, i+1)
+		case strings.Contains(label, ","):
+			return fmt.Errorf("%w: rule %d: label must not contain commas", ErrInvalidLabelRules, i+1)
+		}
+
+		switch rule.Metric {
+		case model.LabelMetricScore, model.LabelMetricChangedLines, model.LabelMetricIssues,
+			model.LabelMetricCriticalIssues, model.LabelMetricHighIssues,
+			model.LabelMetricSecurityIssues, model.LabelMetricPerformanceIssues:
+		default:
+			return fmt.Errorf("%w: rule %d: unknown metric %q", ErrInvalidLabelRules, i+1, rule.Metric)
+		}
+
+		switch {
+		case rule.Min == nil && rule.Max == nil:
+			return fmt.Errorf("%w: rule %d: set min, max or both", ErrInvalidLabelRules, i+1)
+		case (rule.Min != nil && *rule.Min < 0) || (rule.Max != nil && *rule.Max < 0):
+			return fmt.Errorf("%w: rule %d: bounds must not be negative", ErrInvalidLabelRules, i+1)
+		case rule.Min != nil && rule.Max != nil && *rule.Min > *rule.Max:
+			return fmt.Errorf("%w: rule %d: min is greater than max", ErrInvalidLabelRules, i+1)
+		}
+	}
+	return nil
+}
+
+// LabelMetrics measures a review for the label rules. Findings rejected by the
+// verifier model are not counted, as in the review's issue counts.
+func LabelMetrics(resp *llm.ReviewResponse, changedLines int) map[string]int {
+	stats := calculateStatistics(resp.Suggestions)
+	return map[string]int{
+		model.LabelMetricScore:             resp.Score,
+		model.LabelMetricChangedLines:      changedLines,
+		model.LabelMetricIssues:            stats.TotalIssues,
+		model.LabelMetricCriticalIssues:    stats.CriticalCount,
+		model.LabelMetricHighIssues:        stats.HighCount,
+		model.LabelMetricSecurityIssues:    stats.SecurityCount,
+		model.LabelMetricPerformanceIssues: stats.PerformanceCount,
+	}
+}
+
+// EvaluateLabelRules returns the labels to put on the merge request and the rule
+// labels to take off. A label named by several rules is kept if any of them matches;
+// labels no rule names are left alone.
+func EvaluateLabelRules(rules []model.LabelRule, metrics map[string]int) (add, remove []string) {
+	matched := map[string]bool{}
+	var labels []string
+	for _, rule := range rules {
+		label := strings.TrimSpace(rule.Label)
+		if _, seen := matched[label]; !seen {
+			labels = append(labels, label)
+			matched[label] = false
+		}
+		if rule.Matches(metrics[rule.Metric]) {
+			matched[label] = true
+		}
+	}
+
+	for _, label := range labels {
+		if matched[label] {
+			add = append(add, label)
+		} else {
+			remove = append(remove, label)
+		}
+	}
+	return add, remove
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+)
+
+func TestEvaluateLabelRules(t *testing.T) {
+	rules := []model.LabelRule{
+		{Label: "ai:security-risk", Metric: model.LabelMetricSecurityIssues, Min: bound(1)},
+		{Label: "ai:score-low", Metric: model.LabelMetricScore, Max: bound(59)},
+		{Label: "size:l", Metric: model.LabelMetricChangedLines, Min: bound(300), Max: bound(999)},
+		{Label: "size:xl", Metric: model.LabelMetricChangedLines, Min: bound(1000)},
+		{Label: "ai:needs-attention", Metric: model.LabelMetricCriticalIssues, Min: bound(1)},
+		{Label: "ai:needs-attention", Metric: model.LabelMetricSecurityIssues, Min: bound(1)}, // Either rule keeps it
+		{Label: "ai:clean", Metric: model.LabelMetricIssues, Max: bound(0)},
+	}
+
+	resp := &llm.ReviewResponse{Score: 72, Suggestions: []llm.FixSuggestion{
+		{Severity: "high", Category: "security"},
+		{Severity: "critical", Category: "security", Verdict: model.VerdictRejected}, // Not counted
+	}}
+	add, remove := EvaluateLabelRules(rules, LabelMetrics(resp, 450))
+
+	if want := []string{"ai:security-risk", "size:l", "ai:needs-attention"}; !reflect.DeepEqual(add, want) {
+		t.Errorf("add = %v, want %v", add, want)
+	}
+	if want := []string{"ai:score-low", "size:xl", "ai:clean"}; !reflect.DeepEqual(remove, want) {
+		t.Errorf("remove = %v, want %v", remove, want)
+	}
+}
+
+func TestValidateLabelRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    model.LabelRule
+		wantErr bool
+	}{
+		{"valid", model.LabelRule{Label: "size:xl", Metric: model.LabelMetricChangedLines, Min: bound(1000)}, false},
+		{"empty label", model.LabelRule{Label: " ", Metric: model.LabelMetricScore, Max: bound(50)}, true},
+		{"comma in label", model.LabelRule{Label: "a,b", Metric: model.LabelMetricScore, Max: bound(50)}, true},
+		{"unknown metric", model.LabelRule{Label: "x", Metric: "bugs", Min: bound(1)}, true},
+		{"no bounds", model.LabelRule{Label: "x", Metric: model.LabelMetricIssues}, true},
+		{"zero bound", model.LabelRule{Label: "ai:clean", Metric: model.LabelMetricIssues, Max: bound(0)}, false},
+		{"negative bound", model.LabelRule{Label: "x", Metric: model.LabelMetricScore, Min: bound(-1)}, true},
+		{"min above max", model.LabelRule{Label: "x", Metric: model.LabelMetricScore, Min: bound(80), Max: bound(50)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLabelRules([]model.LabelRule{tt.rule})
+			if (err != nil) != tt.wantErr || (err != nil && !errors.Is(err, ErrInvalidLabelRules)) {
+				t.Errorf("ValidateLabelRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func bound(v int) *int {
+	return &v
+}
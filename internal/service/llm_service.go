@@ -2,7 +2,9 @@ package service
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,16 +15,24 @@ import (
 	"github.com/handsoff/handsoff/internal/repository"
 	"github.com/handsoff/handsoff/pkg/config"
 	"github.com/handsoff/handsoff/pkg/crypto"
+	"github.com/handsoff/handsoff/pkg/ratelimit"
 )
 
 // LLMService handles LLM provider and model business logic
 type LLMService struct {
 	repo      *repository.LLMRepository
 	encryptor *crypto.Encryptor
+	limiter   *ratelimit.Limiter // Source of the providers' throttle state (optional)
 }
 
+// ErrInvalidRateLimit is returned when a provider rate limit is negative
+var ErrInvalidRateLimit = errors.New("rate limits must not be negative")
+
+// throttleStateTimeout bounds the rate limiter lookup when listing providers
+const throttleStateTimeout = time.Second
+
 // NewLLMService creates a new LLM service
-func NewLLMService(repo *repository.LLMRepository, cfg *config.Config) (*LLMService, error) {
+func NewLLMService(repo *repository.LLMRepository, cfg *config.Config, limiter *ratelimit.Limiter) (*LLMService, error) {
 	encryptor, err := crypto.NewEncryptor(cfg.Security.EncryptionKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create encryptor: %w", err)
@@ -31,6 +41,7 @@ func NewLLMService(repo *repository.LLMRepository, cfg *config.Config) (*LLMServ
 	return &LLMService{
 		repo:      repo,
 		encryptor: encryptor,
+		limiter:   limiter,
 	}, nil
 }
 
@@ -48,6 +59,7 @@ func (s *LLMService) ListProviders(projectID uint) ([]model.LLMProvider, error)
 		if providers[i].APIKey != "" {
 			providers[i].APIKey = "***masked***"
 		}
+		s.fillThrottleState(&providers[i])
 	}
 
 	return providers, nil
@@ -64,12 +76,36 @@ func (s *LLMService) GetProvider(id uint, projectID uint) (*model.LLMProvider, e
 	if provider.APIKey != "" {
 		provider.APIKey = "***masked***"
 	}
+	s.fillThrottleState(provider)
 
 	return provider, nil
 }
 
+// fillThrottleState sets whether the provider is currently held back by its rate limits
+// or a provider 429. Lookup failures leave the provider shown as not throttled.
+func (s *LLMService) fillThrottleState(provider *model.LLMProvider) {
+	if s.limiter == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), throttleStateTimeout)
+	defer cancel()
+
+	wait, err := s.limiter.Wait(ctx, llm.ProviderRateLimitKey(provider), llm.ProviderLimits(provider), 1)
+	if err != nil || wait <= 0 {
+		return
+	}
+	until := time.Now().Add(wait)
+	provider.Throttled = true
+	provider.ThrottledUntil = &until
+}
+
 // CreateProvider creates a new provider with encrypted API key
 func (s *LLMService) CreateProvider(provider *model.LLMProvider) error {
+	if provider.RPMLimit < 0 || provider.TPMLimit < 0 {
+		return ErrInvalidRateLimit
+	}
+
 	// Encrypt API key
 	if provider.APIKey != "" {
 		encryptedKey, err := s.encryptor.Encrypt(provider.APIKey)
@@ -101,6 +137,13 @@ func (s *LLMService) UpdateProvider(provider *model.LLMProvider) error {
 		existing.Model = provider.Model
 	}
 
+	// Rate limits: the caller passes the values to keep (0 = unlimited)
+	if provider.RPMLimit < 0 || provider.TPMLimit < 0 {
+		return ErrInvalidRateLimit
+	}
+	existing.RPMLimit = provider.RPMLimit
+	existing.TPMLimit = provider.TPMLimit
+
 	// Handle API key: only update if a new key is provided
 	if provider.APIKey != "" && provider.APIKey != "***masked***" {
 		encryptedKey, err := s.encryptor.Encrypt(provider.APIKey)
@@ -0,0 +1,425 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/pkg/config"
+	"github.com/handsoff/handsoff/pkg/crypto"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+// ErrOIDCDisabled is returned when OIDC login is not configured
+var ErrOIDCDisabled = errors.New("OIDC login is not enabled")
+
+// OIDCLoginRequest holds the authorization URL and the values that must be
+// kept by the client (cookie) until the callback
+type OIDCLoginRequest struct {
+	URL      string
+	State    string
+	Nonce    string
+	Verifier string // PKCE code verifier
+}
+
+// oidcRoleMapping maps an identity provider group to a project role
+type oidcRoleMapping struct {
+	Group     string
+	ProjectID uint
+	Role      model.ProjectRole
+}
+
+// OIDCService implements OIDC authorization-code login with PKCE.
+// Users are provisioned on first login and their project roles are synced
+// from group claims on every login.
+type OIDCService struct {
+	db           *gorm.DB
+	cfg          config.OIDCConfig
+	tokenService *AuthTokenService
+	userService  *UserService
+	mappings     []oidcRoleMapping
+
+	// Provider discovery is lazy so that an unreachable IdP does not block startup
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+// NewOIDCService creates a new OIDC service
+func NewOIDCService(db *gorm.DB, cfg *config.Config, tokenService *AuthTokenService) (*OIDCService, error) {
+	mappings, err := parseOIDCRoleMappings(splitList(cfg.OIDC.RoleMappings))
+	if err != nil {
+		return nil, err
+	}
+
+	return &OIDCService{
+		db:           db,
+		cfg:          cfg.OIDC,
+		tokenService: tokenService,
+		userService:  NewUserService(db),
+		mappings:     mappings,
+	}, nil
+}
+
+// Enabled reports whether OIDC login is configured
+func (s *OIDCService) Enabled() bool {
+	return s.cfg.Enabled
+}
+
+// PostLoginRedirect returns the frontend URL that receives issued tokens
+func (s *OIDCService) PostLoginRedirect() string {
+	return s.cfg.PostLoginRedirect
+}
+
+// getProvider returns the discovered provider, discovering it on first use
+func (s *OIDCService) getProvider(ctx context.Context) (*oidc.Provider, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.provider != nil {
+		return s.provider, nil
+	}
+
+	provider, err := oidc.NewProvider(ctx, s.cfg.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover OIDC provider: %w", err)
+	}
+	s.provider = provider
+	return provider, nil
+}
+
+// oauth2Config builds the OAuth2 client configuration for the provider
+func (s *OIDCService) oauth2Config(provider *oidc.Provider) *oauth2.Config {
+	scopes := []string{oidc.ScopeOpenID}
+	for _, scope := range splitList(s.cfg.Scopes) {
+		if scope != oidc.ScopeOpenID {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return &oauth2.Config{
+		ClientID:     s.cfg.ClientID,
+		ClientSecret: s.cfg.ClientSecret,
+		RedirectURL:  s.cfg.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       scopes,
+	}
+}
+
+// BeginLogin creates the authorization request (state, nonce and PKCE challenge)
+func (s *OIDCService) BeginLogin(ctx context.Context) (*OIDCLoginRequest, error) {
+	if !s.cfg.Enabled {
+		return nil, ErrOIDCDisabled
+	}
+
+	provider, err := s.getProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := crypto.GenerateToken(24)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := crypto.GenerateToken(24)
+	if err != nil {
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	url := s.oauth2Config(provider).AuthCodeURL(state,
+		oauth2.S256ChallengeOption(verifier),
+		oidc.Nonce(nonce),
+	)
+
+	return &OIDCLoginRequest{
+		URL:      url,
+		State:    state,
+		Nonce:    nonce,
+		Verifier: verifier,
+	}, nil
+}
+
+// CompleteLogin exchanges the authorization code, verifies the ID token,
+// provisions the user and issues a HandsOff token pair
+func (s *OIDCService) CompleteLogin(ctx context.Context, code, verifier, nonce string, meta ClientMeta) (*TokenPair, *model.User, error) {
+	if !s.cfg.Enabled {
+		return nil, nil, ErrOIDCDisabled
+	}
+
+	provider, err := s.getProvider(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	oauthToken, err := s.oauth2Config(provider).Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+
+	rawIDToken, ok := oauthToken.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, nil, fmt.Errorf("token response does not contain an id_token")
+	}
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: s.cfg.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to verify id_token: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, nil, fmt.Errorf("id_token nonce mismatch")
+	}
+
+	var claims map[string]interface{}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse id_token claims: %w", err)
+	}
+
+	user, err := s.provisionUser(idToken.Issuer, idToken.Subject, claims)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !user.IsActive {
+		return nil, nil, ErrUserInactive
+	}
+
+	if err := s.syncProjectRoles(user, claimStrings(claims[s.cfg.GroupsClaim])); err != nil {
+		return nil, nil, err
+	}
+
+	pair, err := s.tokenService.IssueTokenPair(user, meta)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pair, user, nil
+}
+
+// provisionUser finds the user linked to the external identity or creates it
+// (with a default project) on first login
+func (s *OIDCService) provisionUser(issuer, subject string, claims map[string]interface{}) (*model.User, error) {
+	externalID := issuer + "|" + subject
+
+	var user model.User
+	err := s.db.Where("external_id = ?", externalID).First(&user).Error
+	if err == nil {
+		return &user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+
+	email, _ := claims["email"].(string)
+	username, _ := claims[s.cfg.UsernameClaim].(string)
+	if username == "" && email != "" {
+		username = strings.SplitN(email, "@", 2)[0]
+	}
+	if username == "" {
+		username = subject
+	}
+
+	username, err = s.availableUsername(sanitizeUsername(username))
+	if err != nil {
+		return nil, err
+	}
+
+	if email == "" {
+		// users.email is unique, use a reserved domain for identities without email
+		email = username + "@oidc.invalid"
+	} else {
+		var count int64
+		if err := s.db.Model(&model.User{}).Where("email = ?", email).Count(&count).Error; err != nil {
+			return nil, fmt.Errorf("failed to check email: %w", err)
+		}
+		if count > 0 {
+			// Never link to an existing account by email, it could belong to a different person
+			return nil, fmt.Errorf("email %s is already used by another account", email)
+		}
+	}
+
+	// Local password login is disabled for OIDC users, the password is random and never shown
+	password, err := crypto.GenerateToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	user = model.User{
+		Username:     username,
+		Password:     password,
+		Email:        email,
+		IsActive:     true,
+		AuthProvider: model.AuthProviderOIDC,
+		ExternalID:   &externalID,
+	}
+	if err := s.userService.CreateUser(&user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// availableUsername returns the name itself or the first free "<name>-<n>"
+func (s *OIDCService) availableUsername(base string) (string, error) {
+	candidate := base
+	for i := 2; i < 100; i++ {
+		var count int64
+		if err := s.db.Model(&model.User{}).Where("username = ?", candidate).Count(&count).Error; err != nil {
+			return "", fmt.Errorf("failed to check username: %w", err)
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+		suffix := "-" + strconv.Itoa(i)
+		candidate = truncate(base, 50-len(suffix)) + suffix
+	}
+	return "", fmt.Errorf("no free username for %s", base)
+}
+
+// syncProjectRoles replaces the user's OIDC-managed memberships with the ones
+// derived from the current group claims. Manual memberships are left untouched.
+func (s *OIDCService) syncProjectRoles(user *model.User, groups []string) error {
+	desired := make(map[uint]model.ProjectRole)
+	for _, group := range groups {
+		for _, m := range s.mappings {
+			if m.Group == group && roleRank(m.Role) > roleRank(desired[m.ProjectID]) {
+				desired[m.ProjectID] = m.Role
+			}
+		}
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var existing []model.ProjectMember
+		if err := tx.Where("user_id = ?", user.ID).Find(&existing).Error; err != nil {
+			return fmt.Errorf("failed to load memberships: %w", err)
+		}
+
+		for _, member := range existing {
+			role, keep := desired[member.ProjectID]
+			if member.Source != model.MemberSourceOIDC {
+				delete(desired, member.ProjectID)
+				continue
+			}
+			if !keep {
+				if err := tx.Delete(&member).Error; err != nil {
+					return fmt.Errorf("failed to remove membership: %w", err)
+				}
+				continue
+			}
+			if member.Role != role {
+				if err := tx.Model(&member).Update("role", role).Error; err != nil {
+					return fmt.Errorf("failed to update membership: %w", err)
+				}
+			}
+			delete(desired, member.ProjectID)
+		}
+
+		for projectID, role := range desired {
+			var project model.Project
+			if err := tx.First(&project, projectID).Error; err != nil {
+				continue // Mapping points to a deleted or unknown project
+			}
+			if project.UserID == user.ID {
+				continue // Owner already has full access
+			}
+			member := model.ProjectMember{
+				ProjectID: projectID,
+				UserID:    user.ID,
+				Role:      role,
+				Source:    model.MemberSourceOIDC,
+			}
+			if err := tx.Create(&member).Error; err != nil {
+				return fmt.Errorf("failed to create membership: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// parseOIDCRoleMappings parses "<group>=<project_id>:<role>" entries
+func parseOIDCRoleMappings(entries []string) ([]oidcRoleMapping, error) {
+	var mappings []oidcRoleMapping
+	for _, entry := range entries {
+		group, target, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid OIDC role mapping %q: expected <group>=<project_id>:<role>", entry)
+		}
+		projectStr, roleStr, ok := strings.Cut(target, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid OIDC role mapping %q: expected <group>=<project_id>:<role>", entry)
+		}
+		projectID, err := strconv.ParseUint(strings.TrimSpace(projectStr), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid project ID in OIDC role mapping %q", entry)
+		}
+		role := model.ProjectRole(strings.TrimSpace(roleStr))
+		if !model.IsValidProjectRole(role) {
+			return nil, fmt.Errorf("invalid role in OIDC role mapping %q", entry)
+		}
+		mappings = append(mappings, oidcRoleMapping{
+			Group:     strings.TrimSpace(group),
+			ProjectID: uint(projectID),
+			Role:      role,
+		})
+	}
+	return mappings, nil
+}
+
+// roleRank orders roles so the most privileged mapping wins
+func roleRank(role model.ProjectRole) int {
+	switch role {
+	case model.ProjectRoleAdmin:
+		return 3
+	case model.ProjectRoleMember:
+		return 2
+	case model.ProjectRoleViewer:
+		return 1
+	}
+	return 0
+}
+
+// claimStrings converts a string or string-array claim to a slice
+func claimStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// splitList flattens comma-separated entries (env values may hold a single comma-separated string)
+func splitList(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+	}
+	return result
+}
+
+var usernameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// sanitizeUsername keeps usernames short and free of unexpected characters
+func sanitizeUsername(name string) string {
+	name = usernameInvalidChars.ReplaceAllString(name, "-")
+	name = strings.Trim(name, "-")
+	if name == "" {
+		name = "user"
+	}
+	return truncate(name, 50)
+}
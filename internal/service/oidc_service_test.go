@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/pkg/config"
+)
+
+// mockOIDCProvider is a minimal OIDC provider: discovery, JWKS and token endpoint
+type mockOIDCProvider struct {
+	server    *httptest.Server
+	key       *rsa.PrivateKey
+	challenge string // PKCE challenge from the authorization request
+	nonce     string
+	groups    []string
+}
+
+func newMockOIDCProvider(t *testing.T) *mockOIDCProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	p := &mockOIDCProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                                p.server.URL,
+			"authorization_endpoint":                p.server.URL + "/authorize",
+			"token_endpoint":                        p.server.URL + "/token",
+			"jwks_uri":                              p.server.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "test",
+				"alg": "RS256",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+		if base64.RawURLEncoding.EncodeToString(sum[:]) != p.challenge || r.Form.Get("code") != "good-code" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		idToken := jwtlib.NewWithClaims(jwtlib.SigningMethodRS256, jwtlib.MapClaims{
+			"iss":                p.server.URL,
+			"sub":                "user-123",
+			"aud":                "handsoff",
+			"exp":                time.Now().Add(time.Hour).Unix(),
+			"iat":                time.Now().Unix(),
+			"nonce":              p.nonce,
+			"email":              "bob@example.com",
+			"preferred_username": "bob",
+			"groups":             p.groups,
+		})
+		idToken.Header["kid"] = "test"
+		signed, err := idToken.SignedString(key)
+		if err != nil {
+			t.Errorf("Failed to sign id_token: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "provider-access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     signed,
+		})
+	})
+
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func TestOIDCLoginProvisionsUser(t *testing.T) {
+	provider := newMockOIDCProvider(t)
+	provider.groups = []string{"reviewers", "unrelated"}
+
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.User{}, &model.Project{}, &model.UserProjectPreference{},
+		&model.ProjectMember{}, &model.RefreshToken{}, &model.RevokedToken{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	// A project owned by someone else that the "reviewers" group maps to
+	owner := &model.User{Username: "owner", Password: "secret123", Email: "owner@example.com", IsActive: true}
+	if err := NewUserService(db).CreateUser(owner); err != nil {
+		t.Fatalf("Failed to create owner: %v", err)
+	}
+	var shared model.Project
+	db.Where("user_id = ?", owner.ID).First(&shared)
+
+	cfg := &config.Config{
+		Security: config.SecurityConfig{JWTSecret: "test", JWTExpiry: time.Minute, JWTRefreshExpiry: time.Hour},
+		OIDC: config.OIDCConfig{
+			Enabled:       true,
+			IssuerURL:     provider.server.URL,
+			ClientID:      "handsoff",
+			RedirectURL:   "http://localhost/api/auth/oidc/callback",
+			UsernameClaim: "preferred_username",
+			GroupsClaim:   "groups",
+			RoleMappings:  []string{"reviewers=" + itoa(shared.ID) + ":member"},
+		},
+	}
+	tokenSvc := NewAuthTokenService(db, cfg)
+	svc, err := NewOIDCService(db, cfg, tokenSvc)
+	if err != nil {
+		t.Fatalf("NewOIDCService failed: %v", err)
+	}
+
+	ctx := context.Background()
+	loginReq, err := svc.BeginLogin(ctx)
+	if err != nil {
+		t.Fatalf("BeginLogin failed: %v", err)
+	}
+	authURL, _ := url.Parse(loginReq.URL)
+	if authURL.Query().Get("code_challenge_method") != "S256" {
+		t.Errorf("Expected PKCE S256 challenge, got %q", authURL.Query().Get("code_challenge_method"))
+	}
+	provider.challenge = authURL.Query().Get("code_challenge")
+	provider.nonce = authURL.Query().Get("nonce")
+
+	// Wrong PKCE verifier is rejected by the provider
+	if _, _, err := svc.CompleteLogin(ctx, "good-code", "wrong-verifier", loginReq.Nonce, ClientMeta{}); err == nil {
+		t.Error("Expected error for wrong PKCE verifier")
+	}
+
+	pair, user, err := svc.CompleteLogin(ctx, "good-code", loginReq.Verifier, loginReq.Nonce, ClientMeta{})
+	if err != nil {
+		t.Fatalf("CompleteLogin failed: %v", err)
+	}
+	if user.Username != "bob" || user.AuthProvider != model.AuthProviderOIDC {
+		t.Errorf("Unexpected provisioned user: %+v", user)
+	}
+	if _, err := tokenSvc.ValidateAccessToken(pair.AccessToken); err != nil {
+		t.Errorf("Issued access token is invalid: %v", err)
+	}
+
+	var projectCount int64
+	db.Model(&model.Project{}).Where("user_id = ?", user.ID).Count(&projectCount)
+	if projectCount != 1 {
+		t.Errorf("Expected default project to be created, got %d projects", projectCount)
+	}
+
+	var member model.ProjectMember
+	if err := db.Where("user_id = ? AND project_id = ?", user.ID, shared.ID).First(&member).Error; err != nil {
+		t.Fatalf("Expected group membership to be mapped: %v", err)
+	}
+	if member.Role != model.ProjectRoleMember {
+		t.Errorf("Expected role member, got %s", member.Role)
+	}
+
+	// Second login reuses the user and drops memberships of removed groups
+	provider.groups = nil
+	_, again, err := svc.CompleteLogin(ctx, "good-code", loginReq.Verifier, loginReq.Nonce, ClientMeta{})
+	if err != nil {
+		t.Fatalf("Second CompleteLogin failed: %v", err)
+	}
+	if again.ID != user.ID {
+		t.Errorf("Expected existing user %d, got %d", user.ID, again.ID)
+	}
+	var memberCount int64
+	db.Model(&model.ProjectMember{}).Where("user_id = ?", user.ID).Count(&memberCount)
+	if memberCount != 0 {
+		t.Errorf("Expected memberships to be removed, got %d", memberCount)
+	}
+}
+
+func TestParseOIDCRoleMappings(t *testing.T) {
+	tests := []struct {
+		entry   string
+		wantErr bool
+	}{
+		{"devs=1:member", false},
+		{"admins = 2 : admin", false},
+		{"devs=1", true},
+		{"devs:1:member", true},
+		{"devs=x:member", true},
+		{"devs=1:owner", true},
+	}
+
+	for _, tt := range tests {
+		_, err := parseOIDCRoleMappings([]string{tt.entry})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseOIDCRoleMappings(%q) error = %v, wantErr %v", tt.entry, err, tt.wantErr)
+		}
+	}
+}
+
+func itoa(id uint) string {
+	return big.NewInt(int64(id)).String()
+}
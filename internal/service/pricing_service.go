@@ -0,0 +1,181 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/handsoff/handsoff/internal/model"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidPrice is returned when a model price fails validation
+var ErrInvalidPrice = errors.New("invalid model price")
+
+// ErrPriceNotFound is returned when a model price does not exist in the project
+var ErrPriceNotFound = errors.New("model price not found")
+
+// DefaultPriceCurrency is used when a price is created without a currency
+const DefaultPriceCurrency = "USD"
+
+// PriceInput holds the editable fields of a model price
+type PriceInput struct {
+	LLMProviderID   uint
+	ModelName       string
+	PromptPrice     float64
+	CompletionPrice float64
+	Currency        string
+	EffectiveFrom   time.Time
+	EffectiveTo     *time.Time
+}
+
+// PricingService manages the model pricing catalogue
+type PricingService struct {
+	db *gorm.DB
+}
+
+// NewPricingService creates a new pricing service
+func NewPricingService(db *gorm.DB) *PricingService {
+	return &PricingService{db: db}
+}
+
+// List returns the prices of a project, optionally of one provider (providerID 0 = all)
+func (s *PricingService) List(projectID, providerID uint) ([]model.ModelPrice, error) {
+	query := s.db.Where("project_id = ?", projectID)
+	if providerID != 0 {
+		query = query.Where("llm_provider_id = ?", providerID)
+	}
+
+	var prices []model.ModelPrice
+	err := query.Preload("LLMProvider").
+		Order("llm_provider_id, model_name, effective_from DESC").
+		Find(&prices).Error
+	return prices, err
+}
+
+// Create adds a price to the project
+func (s *PricingService) Create(projectID uint, input PriceInput) (*model.ModelPrice, error) {
+	price := &model.ModelPrice{ProjectID: projectID}
+	applyPriceInput(price, input)
+	if err := s.validate(price); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Create(price).Error; err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+// Update changes a price of the project.
+// Costs already logged are not recomputed.
+func (s *PricingService) Update(id, projectID uint, input PriceInput) (*model.ModelPrice, error) {
+	price, err := s.get(id, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	applyPriceInput(price, input)
+	if err := s.validate(price); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Save(price).Error; err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+// Delete removes a price of the project
+func (s *PricingService) Delete(id, projectID uint) error {
+	result := s.db.Where("id = ? AND project_id = ?", id, projectID).Delete(&model.ModelPrice{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPriceNotFound
+	}
+	return nil
+}
+
+// PriceAt returns the price of a provider's model in effect at the given time,
+// or nil if the model has no price then
+func (s *PricingService) PriceAt(providerID uint, modelName string, at time.Time) (*model.ModelPrice, error) {
+	var price model.ModelPrice
+	err := s.db.
+		Where("llm_provider_id = ? AND model_name = ? AND effective_from <= ? AND (effective_to IS NULL OR effective_to > ?)",
+			providerID, modelName, at, at).
+		Order("effective_from DESC").
+		First(&price).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
+
+func applyPriceInput(price *model.ModelPrice, input PriceInput) {
+	price.LLMProviderID = input.LLMProviderID
+	price.ModelName = strings.TrimSpace(input.ModelName)
+	price.PromptPrice = input.PromptPrice
+	price.CompletionPrice = input.CompletionPrice
+	price.Currency = strings.ToUpper(strings.TrimSpace(input.Currency))
+	price.EffectiveFrom = input.EffectiveFrom
+	price.EffectiveTo = input.EffectiveTo
+}
+
+func (s *PricingService) validate(price *model.ModelPrice) error {
+	if price.ModelName == "" {
+		return fmt.Errorf("%w: model name is required", ErrInvalidPrice)
+	}
+	if price.PromptPrice < 0 || price.CompletionPrice < 0 {
+		return fmt.Errorf("%w: prices must not be negative", ErrInvalidPrice)
+	}
+	if price.Currency == "" {
+		price.Currency = DefaultPriceCurrency
+	}
+	if price.EffectiveFrom.IsZero() {
+		return fmt.Errorf("%w: effective from date is required", ErrInvalidPrice)
+	}
+	if price.EffectiveTo != nil && !price.EffectiveTo.After(price.EffectiveFrom) {
+		return fmt.Errorf("%w: effective to must be after effective from", ErrInvalidPrice)
+	}
+
+	var count int64
+	s.db.Model(&model.LLMProvider{}).
+		Where("id = ? AND project_id = ?", price.LLMProviderID, price.ProjectID).
+		Count(&count)
+	if count == 0 {
+		return fmt.Errorf("%w: LLM provider not found", ErrInvalidPrice)
+	}
+
+	// Periods of the same model must not overlap, so a call has at most one price
+	query := s.db.Model(&model.ModelPrice{}).
+		Where("llm_provider_id = ? AND model_name = ? AND id <> ?", price.LLMProviderID, price.ModelName, price.ID).
+		Where("effective_to IS NULL OR effective_to > ?", price.EffectiveFrom)
+	if price.EffectiveTo != nil {
+		query = query.Where("effective_from < ?", *price.EffectiveTo)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: overlaps another price of %s", ErrInvalidPrice, price.ModelName)
+	}
+	return nil
+}
+
+func (s *PricingService) get(id, projectID uint) (*model.ModelPrice, error) {
+	var price model.ModelPrice
+	err := s.db.Where("id = ? AND project_id = ?", id, projectID).First(&price).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrPriceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
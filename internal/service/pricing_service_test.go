@@ -0,0 +1,153 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/handsoff/handsoff/internal/model"
+)
+
+func TestPricingServiceCostAtWriteTime(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.LLMProvider{}, &model.ModelPrice{}, &model.LLMUsageLog{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	pricing := NewPricingService(db)
+	usage := NewUsageService(db)
+
+	provider := model.LLMProvider{Name: "openai", BaseURL: "http://llm", APIKey: "key", Model: "gpt-4", ProjectID: 1}
+	db.Create(&provider)
+	repo := model.Repository{Name: "repo", ProjectID: 1}
+	db.Create(&repo)
+
+	now := time.Now()
+	priceChange := now.Add(-time.Hour)
+	if _, err := pricing.Create(1, PriceInput{
+		LLMProviderID: provider.ID, ModelName: "gpt-4", PromptPrice: 10, CompletionPrice: 30,
+		EffectiveFrom: now.AddDate(0, -1, 0), EffectiveTo: &priceChange,
+	}); err != nil {
+		t.Fatalf("Failed to create old price: %v", err)
+	}
+	if _, err := pricing.Create(1, PriceInput{
+		LLMProviderID: provider.ID, ModelName: "gpt-4", PromptPrice: 2, CompletionPrice: 8, Currency: "usd",
+		EffectiveFrom: priceChange,
+	}); err != nil {
+		t.Fatalf("Failed to create current price: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		model    string
+		cost     float64
+		currency string
+	}{
+		{"Priced model", "gpt-4", (1_000_000*2 + 500_000*8) / 1e6, "USD"},
+		{"Unpriced model", "gpt-3.5", 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, err := usage.LogUsage(UsageContext{
+				RepositoryID:  repo.ID,
+				ProjectID:     1,
+				LLMProviderID: provider.ID,
+				ModelName:     tt.model,
+				RequestType:   model.UsageTypeCodeReview,
+			}, UsageMetrics{
+				Status:           model.UsageStatusSuccess,
+				PromptTokens:     1_000_000,
+				CompletionTokens: 500_000,
+				TotalTokens:      1_500_000,
+			})
+			if err != nil {
+				t.Fatalf("LogUsage failed: %v", err)
+			}
+			if math.Abs(log.Cost-tt.cost) > 1e-9 || log.Currency != tt.currency {
+				t.Errorf("Got cost=%v %q, want %v %q", log.Cost, log.Currency, tt.cost, tt.currency)
+			}
+		})
+	}
+
+	// The old price still applies to calls made before the change
+	price, err := pricing.PriceAt(provider.ID, "gpt-4", priceChange.Add(-time.Minute))
+	if err != nil || price == nil || price.PromptPrice != 10 {
+		t.Errorf("Expected the old price before the change, got %+v (err=%v)", price, err)
+	}
+
+	costs, err := usage.GetCostBreakdown(1, nil, now.Add(-time.Minute), time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("GetCostBreakdown failed: %v", err)
+	}
+	if len(costs.ByModel) != 2 || costs.ByModel[0].ModelName != "gpt-4" || costs.ByModel[0].Cost != 6 {
+		t.Errorf("Unexpected cost by model: %+v", costs.ByModel)
+	}
+	if len(costs.ByRepository) != 2 || costs.ByRepository[0].RepositoryName != "repo" {
+		t.Errorf("Expected one row per repository and currency, got %+v", costs.ByRepository)
+	}
+	if len(costs.ByDay) == 0 {
+		t.Error("Expected daily costs")
+	}
+	if len(costs.ByCurrency) != 2 || costs.ByCurrency[0].Currency != "USD" || costs.ByCurrency[0].Cost != 6 {
+		t.Errorf("Expected one total per currency, got %+v", costs.ByCurrency)
+	}
+
+	// A failed price lookup still logs the call, without a cost
+	if err := db.Migrator().DropTable(&model.ModelPrice{}); err != nil {
+		t.Fatalf("Failed to drop prices: %v", err)
+	}
+	log, err := usage.LogUsage(UsageContext{RepositoryID: repo.ID, ProjectID: 1, LLMProviderID: provider.ID,
+		ModelName: "gpt-4", RequestType: model.UsageTypeCodeReview}, UsageMetrics{Status: model.UsageStatusSuccess, TotalTokens: 10})
+	if err == nil || log == nil || log.ID == 0 || log.Cost != 0 {
+		t.Errorf("LogUsage without prices = %+v, %v; want the call logged without cost and an error", log, err)
+	}
+}
+
+func TestPricingServiceValidation(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.LLMProvider{}, &model.ModelPrice{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	svc := NewPricingService(db)
+
+	provider := model.LLMProvider{Name: "openai", BaseURL: "http://llm", APIKey: "key", Model: "gpt-4", ProjectID: 1}
+	foreign := model.LLMProvider{Name: "other", BaseURL: "http://llm", APIKey: "key", Model: "gpt-4", ProjectID: 2}
+	db.Create(&provider)
+	db.Create(&foreign)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 3, 0)
+	if _, err := svc.Create(1, PriceInput{LLMProviderID: provider.ID, ModelName: "gpt-4", PromptPrice: 1,
+		EffectiveFrom: start, EffectiveTo: &end}); err != nil {
+		t.Fatalf("Failed to create price: %v", err)
+	}
+
+	before := start.AddDate(0, 1, 0)
+	tests := []struct {
+		name  string
+		input PriceInput
+		valid bool
+	}{
+		{"Missing model", PriceInput{LLMProviderID: provider.ID, EffectiveFrom: end}, false},
+		{"Negative price", PriceInput{LLMProviderID: provider.ID, ModelName: "gpt-4", PromptPrice: -1, EffectiveFrom: end}, false},
+		{"Ends before it starts", PriceInput{LLMProviderID: provider.ID, ModelName: "gpt-4", EffectiveFrom: end, EffectiveTo: &start}, false},
+		{"Provider of another project", PriceInput{LLMProviderID: foreign.ID, ModelName: "gpt-4", EffectiveFrom: end}, false},
+		{"Overlapping open-ended", PriceInput{LLMProviderID: provider.ID, ModelName: "gpt-4", EffectiveFrom: before}, false},
+		{"Overlapping earlier", PriceInput{LLMProviderID: provider.ID, ModelName: "gpt-4", EffectiveFrom: start.AddDate(-1, 0, 0), EffectiveTo: &before}, false},
+		{"Adjacent period", PriceInput{LLMProviderID: provider.ID, ModelName: "gpt-4", EffectiveFrom: end}, true},
+		{"Other model", PriceInput{LLMProviderID: provider.ID, ModelName: "gpt-4o", EffectiveFrom: start}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Create(1, tt.input)
+			if tt.valid && err != nil {
+				t.Errorf("Expected valid price, got %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidPrice) {
+				t.Errorf("Expected ErrInvalidPrice, got %v", err)
+			}
+		})
+	}
+}
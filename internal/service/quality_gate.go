@@ -0,0 +1,148 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidQualityGate is returned when quality gate settings fail validation
+var ErrInvalidQualityGate = errors.New("invalid quality gate")
+
+// ErrGateNotWaivable is returned when waiving a review whose gate did not fail
+var ErrGateNotWaivable = errors.New("only a failed quality gate can be waived")
+
+// maxWaiverReason caps the stored waiver reason length
+const maxWaiverReason = 2000
+
+// ValidateQualityGate checks quality gate settings
+func ValidateQualityGate(gate model.QualityGate) error {
+	if gate.MinScore < 0 || gate.MinScore > 100 {
+		return fmt.Errorf("%w: min_score must be between 0 and 100", ErrInvalidQualityGate)
+	}
+	if gate.Enabled && gate.MinScore == 0 && !gate.NoCritical && !gate.NoSecurityIssues {
+		return fmt.Errorf("%w: an enabled gate needs at least one condition", ErrInvalidQualityGate)
+	}
+	return nil
+}
+
+// EvaluateQualityGate checks a review against the gate and returns why it failed,
+// or nil when it passed. Findings rejected by the verifier model do not count.
+func EvaluateQualityGate(gate model.QualityGate, resp *llm.ReviewResponse) []string {
+	stats := calculateStatistics(resp.Suggestions)
+
+	var failures []string
+	if gate.MinScore > 0 && resp.Score < gate.MinScore {
+		failures = append(failures, fmt.Sprintf("score %d is below %d", resp.Score, gate.MinScore))
+	}
+	if gate.NoCritical && stats.CriticalCount > 0 {
+		failures = append(failures, fmt.Sprintf("%d critical issue(s)", stats.CriticalCount))
+	}
+	if gate.NoSecurityIssues && stats.SecurityCount > 0 {
+		failures = append(failures, fmt.Sprintf("%d security issue(s)", stats.SecurityCount))
+	}
+	return failures
+}
+
+// QualityGateService records quality gate outcomes and their waivers
+type QualityGateService struct {
+	db *gorm.DB
+}
+
+// NewQualityGateService creates a new quality gate service
+func NewQualityGateService(db *gorm.DB) *QualityGateService {
+	return &QualityGateService{db: db}
+}
+
+// SetResult stores the gate outcome of a review
+func (s *QualityGateService) SetResult(reviewID uint, status, reason string) error {
+	return s.db.Model(&model.ReviewResult{}).Where("id = ?", reviewID).Updates(map[string]interface{}{
+		"gate_status": status,
+		"gate_reason": reason,
+	}).Error
+}
+
+// ValidateWaiver checks that the review's gate can be waived with the reason
+func ValidateWaiver(review *model.ReviewResult, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return fmt.Errorf("%w: a reason is required", ErrInvalidQualityGate)
+	}
+	if len(reason) > maxWaiverReason {
+		return fmt.Errorf("%w: reason must be at most %d characters", ErrInvalidQualityGate, maxWaiverReason)
+	}
+	if review.GateStatus != model.GateStatusFailed {
+		return ErrGateNotWaivable
+	}
+	return nil
+}
+
+// Waive overrides the failed gate of a review for its current head commit and
+// records who waived it and why
+func (s *QualityGateService) Waive(review *model.ReviewResult, reason string, userID uint, username string, now time.Time) (*model.GateWaiver, error) {
+	if err := ValidateWaiver(review, reason); err != nil {
+		return nil, err
+	}
+
+	waiver := &model.GateWaiver{
+		CreatedAt:      now,
+		ReviewResultID: review.ID,
+		RepositoryID:   review.RepositoryID,
+		MergeRequestID: review.MergeRequestID,
+		HeadSHA:        review.HeadSHA,
+		GateReason:     review.GateReason,
+		Reason:         strings.TrimSpace(reason),
+		WaivedByID:     userID,
+		WaivedBy:       username,
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Only waive the commit that failed: a newer review may have replaced it meanwhile
+		result := tx.Model(&model.ReviewResult{}).
+			Where("id = ? AND gate_status = ? AND head_sha = ?", review.ID, model.GateStatusFailed, review.HeadSHA).
+			Update("gate_status", model.GateStatusWaived)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrGateNotWaivable
+		}
+		return tx.Create(waiver).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	review.GateStatus = model.GateStatusWaived
+	return waiver, nil
+}
+
+// RollbackWaiver undoes a waiver that could not be applied to the commit status on
+// GitLab: the waiver is removed and the gate is failed again
+func (s *QualityGateService) RollbackWaiver(review *model.ReviewResult, waiver *model.GateWaiver) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(waiver).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.ReviewResult{}).
+			Where("id = ? AND gate_status = ? AND head_sha = ?", review.ID, model.GateStatusWaived, waiver.HeadSHA).
+			Update("gate_status", model.GateStatusFailed).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	review.GateStatus = model.GateStatusFailed
+	return nil
+}
+
+// Waivers returns the waivers of a review, newest first
+func (s *QualityGateService) Waivers(reviewID uint) ([]model.GateWaiver, error) {
+	var waivers []model.GateWaiver
+	err := s.db.Where("review_result_id = ?", reviewID).Order("id DESC").Find(&waivers).Error
+	return waivers, err
+}
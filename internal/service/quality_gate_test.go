@@ -0,0 +1,97 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+)
+
+func TestEvaluateQualityGate(t *testing.T) {
+	findings := []llm.FixSuggestion{
+		{Severity: "critical", Category: "logic"},
+		{Severity: "high", Category: "security", Verdict: model.VerdictRejected}, // Rejected: not counted
+	}
+
+	tests := []struct {
+		name  string
+		gate  model.QualityGate
+		score int
+		want  int // Number of failures
+	}{
+		{"score above minimum", model.QualityGate{Enabled: true, MinScore: 70}, 80, 0},
+		{"score below minimum", model.QualityGate{Enabled: true, MinScore: 70}, 60, 1},
+		{"critical issue", model.QualityGate{Enabled: true, NoCritical: true}, 90, 1},
+		{"rejected security issue", model.QualityGate{Enabled: true, NoSecurityIssues: true}, 90, 0},
+		{"all conditions", model.QualityGate{Enabled: true, MinScore: 70, NoCritical: true, NoSecurityIssues: true}, 50, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EvaluateQualityGate(tt.gate, &llm.ReviewResponse{Score: tt.score, Suggestions: findings})
+			if len(got) != tt.want {
+				t.Errorf("EvaluateQualityGate() = %v, want %d failure(s)", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQualityGateWaive(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.GateWaiver{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	svc := NewQualityGateService(db)
+
+	review := model.ReviewResult{RepositoryID: 1, MergeRequestID: 5, HeadSHA: "abc"}
+	db.Create(&review)
+
+	if _, err := svc.Waive(&review, "hotfix", 1, "alice", time.Now()); !errors.Is(err, ErrGateNotWaivable) {
+		t.Fatalf("Waive() of a review without a failed gate error = %v, want ErrGateNotWaivable", err)
+	}
+
+	if err := svc.SetResult(review.ID, model.GateStatusFailed, "score 40 is below 70"); err != nil {
+		t.Fatalf("SetResult() error = %v", err)
+	}
+	review.GateStatus, review.GateReason = model.GateStatusFailed, "score 40 is below 70"
+	if _, err := svc.Waive(&review, "  ", 1, "alice", time.Now()); !errors.Is(err, ErrInvalidQualityGate) {
+		t.Fatalf("Waive() without a reason error = %v, want ErrInvalidQualityGate", err)
+	}
+
+	// A newer commit replaced the failed one: its gate is not waived by accident
+	stale := review
+	stale.HeadSHA = "old"
+	if _, err := svc.Waive(&stale, "hotfix", 1, "alice", time.Now()); !errors.Is(err, ErrGateNotWaivable) {
+		t.Fatalf("Waive() of a replaced commit error = %v, want ErrGateNotWaivable", err)
+	}
+
+	waiver, err := svc.Waive(&review, "hotfix for outage", 1, "alice", time.Now())
+	if err != nil {
+		t.Fatalf("Waive() error = %v", err)
+	}
+	if waiver.HeadSHA != "abc" || waiver.GateReason != "score 40 is below 70" || waiver.WaivedBy != "alice" {
+		t.Errorf("waiver = %+v", waiver)
+	}
+
+	var stored model.ReviewResult
+	db.First(&stored, review.ID)
+	if stored.GateStatus != model.GateStatusWaived {
+		t.Errorf("gate status = %q, want %q", stored.GateStatus, model.GateStatusWaived)
+	}
+	if waivers, _ := svc.Waivers(review.ID); len(waivers) != 1 {
+		t.Errorf("Waivers() returned %d waivers, want 1", len(waivers))
+	}
+
+	// The commit status could not be set: the gate is failed again and nothing is left in the audit trail
+	if err := svc.RollbackWaiver(&review, waiver); err != nil {
+		t.Fatalf("RollbackWaiver() error = %v", err)
+	}
+	db.First(&stored, review.ID)
+	if stored.GateStatus != model.GateStatusFailed || review.GateStatus != model.GateStatusFailed {
+		t.Errorf("gate status after rollback = %q, want %q", stored.GateStatus, model.GateStatusFailed)
+	}
+	if waivers, _ := svc.Waivers(review.ID); len(waivers) != 0 {
+		t.Errorf("Waivers() after rollback returned %d waivers, want 0", len(waivers))
+	}
+}
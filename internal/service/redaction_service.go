@@ -0,0 +1,154 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/redact"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidRedactionRule is returned when a redaction rule fails validation
+var ErrInvalidRedactionRule = errors.New("invalid redaction rule")
+
+// ErrRedactionRuleNotFound is returned when a redaction rule does not exist in the project
+var ErrRedactionRuleNotFound = errors.New("redaction rule not found")
+
+// RedactionRuleInput holds the editable fields of a redaction rule
+type RedactionRuleInput struct {
+	Name        string
+	Pattern     string
+	Description string
+	IsActive    bool
+}
+
+// RedactionService manages project-specific redaction rules
+type RedactionService struct {
+	db *gorm.DB
+}
+
+// NewRedactionService creates a new redaction service
+func NewRedactionService(db *gorm.DB) *RedactionService {
+	return &RedactionService{db: db}
+}
+
+// List returns the redaction rules of a project
+func (s *RedactionService) List(projectID uint) ([]model.RedactionRule, error) {
+	var rules []model.RedactionRule
+	err := s.db.Where("project_id = ?", projectID).Order("name").Find(&rules).Error
+	return rules, err
+}
+
+// Create adds a redaction rule to the project
+func (s *RedactionService) Create(projectID uint, input RedactionRuleInput) (*model.RedactionRule, error) {
+	rule := &model.RedactionRule{ProjectID: projectID}
+	applyRedactionRuleInput(rule, input)
+	if err := s.validate(rule); err != nil {
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(rule).Error; err != nil {
+			return err
+		}
+		// GORM stores a false is_active as the column default (true)
+		if !input.IsActive {
+			return tx.Model(rule).Update("is_active", false).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Update changes a redaction rule of the project
+func (s *RedactionService) Update(id, projectID uint, input RedactionRuleInput) (*model.RedactionRule, error) {
+	rule, err := s.get(id, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	applyRedactionRuleInput(rule, input)
+	if err := s.validate(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Save(rule).Error; err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Delete removes a redaction rule of the project
+func (s *RedactionService) Delete(id, projectID uint) error {
+	result := s.db.Where("id = ? AND project_id = ?", id, projectID).Delete(&model.RedactionRule{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRedactionRuleNotFound
+	}
+	return nil
+}
+
+// Redactor returns a redactor with the builtin rules and the project's active rules
+func (s *RedactionService) Redactor(projectID uint) (*redact.Redactor, error) {
+	var rules []model.RedactionRule
+	if err := s.db.Where("project_id = ? AND is_active = ?", projectID, true).Order("name").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	custom := make([]redact.Rule, 0, len(rules))
+	for _, rule := range rules {
+		compiled, err := redact.CompileRule(rule.Name, rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("redaction rule %q: %w", rule.Name, err)
+		}
+		custom = append(custom, compiled)
+	}
+	return redact.New(custom...), nil
+}
+
+func applyRedactionRuleInput(rule *model.RedactionRule, input RedactionRuleInput) {
+	rule.Name = strings.TrimSpace(input.Name)
+	rule.Pattern = input.Pattern
+	rule.Description = input.Description
+	rule.IsActive = input.IsActive
+}
+
+func (s *RedactionService) validate(rule *model.RedactionRule) error {
+	if _, err := redact.CompileRule(rule.Name, rule.Pattern); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRedactionRule, err)
+	}
+	if redact.IsBuiltin(rule.Name) {
+		return fmt.Errorf("%w: %q is a builtin rule", ErrInvalidRedactionRule, rule.Name)
+	}
+
+	var count int64
+	err := s.db.Model(&model.RedactionRule{}).
+		Where("project_id = ? AND name = ? AND id <> ?", rule.ProjectID, rule.Name, rule.ID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: a rule named %q already exists", ErrInvalidRedactionRule, rule.Name)
+	}
+	return nil
+}
+
+func (s *RedactionService) get(id, projectID uint) (*model.RedactionRule, error) {
+	var rule model.RedactionRule
+	err := s.db.Where("id = ? AND project_id = ?", id, projectID).First(&rule).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRedactionRuleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/handsoff/handsoff/internal/model"
+)
+
+func TestRedactionServiceCreateInactive(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.RedactionRule{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	svc := NewRedactionService(db)
+
+	rule, err := svc.Create(1, RedactionRuleInput{Name: "ticket_secret", Pattern: `TCK-[0-9a-f]{8}`})
+	if err != nil {
+		t.Fatalf("Failed to create redaction rule: %v", err)
+	}
+
+	var stored model.RedactionRule
+	db.First(&stored, rule.ID)
+	if stored.ID == 0 || stored.IsActive {
+		t.Errorf("Expected the rule stored inactive, got id=%d is_active=%v", stored.ID, stored.IsActive)
+	}
+}
@@ -1,13 +1,19 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
+	"github.com/handsoff/handsoff/internal/analyzer"
 	"github.com/handsoff/handsoff/internal/model"
 	"github.com/handsoff/handsoff/internal/repository"
+	"github.com/handsoff/handsoff/internal/webhook"
 	"github.com/handsoff/handsoff/pkg/config"
 	"github.com/handsoff/handsoff/pkg/crypto"
 	"github.com/xanzy/go-gitlab"
+	"gorm.io/gorm"
 )
 
 // RepositoryService handles repository business logic
@@ -38,6 +44,27 @@ func NewRepositoryService(
 	}, nil
 }
 
+// ErrInvalidTriggerRules is returned when review trigger rules fail validation
+var ErrInvalidTriggerRules = errors.New("invalid trigger rules")
+
+// ErrInvalidMRSummaryMode is returned for an unknown MR summary mode
+var ErrInvalidMRSummaryMode = errors.New("invalid MR summary mode")
+
+// ErrInvalidReviewerSettings is returned when reviewer recommendation settings fail validation
+var ErrInvalidReviewerSettings = errors.New("invalid reviewer settings")
+
+// maxRecommendedReviewers bounds how many reviewers are recommended per merge request
+const maxRecommendedReviewers = 10
+
+// ErrInvalidAnalyzers is returned for an unknown static analyzer
+var ErrInvalidAnalyzers = errors.New("invalid analyzers")
+
+// ErrInvalidConsensusSettings is returned when consensus mode settings fail validation
+var ErrInvalidConsensusSettings = errors.New("invalid consensus settings")
+
+// webhookSecretBytes is the entropy of generated webhook secret tokens
+const webhookSecretBytes = 32
+
 // GitLabRepository represents a GitLab repository
 type GitLabRepository struct {
 	ID            int64  `json:"id"`
@@ -118,6 +145,26 @@ func (s *RepositoryService) Get(id uint, projectID uint) (*model.Repository, err
 	return s.repo.Get(id, projectID)
 }
 
+// GetMergeRequest fetches a merge request of an imported repository from GitLab
+func (s *RepositoryService) GetMergeRequest(id uint, projectID uint, mrIID int) (*model.Repository, *gitlab.MergeRequest, error) {
+	repo, err := s.repo.Get(id, projectID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("repository not found: %w", err)
+	}
+
+	git, err := s.createGitLabClient(projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mr, _, err := git.MergeRequests.GetMergeRequest(int(repo.PlatformRepoID), mrIID, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get merge request: %w", err)
+	}
+
+	return repo, mr, nil
+}
+
 // BatchImportResult represents the result of batch import operation
 type BatchImportResult struct {
 	Succeeded []int64              `json:"succeeded"` // Successfully imported repository IDs
@@ -213,13 +260,13 @@ func (s *RepositoryService) importSingleRepository(
 	}
 
 	// Step 3: Ensure webhook exists (find existing or create new)
-	webhookID, webhookURL, err := s.ensureWebhook(git, platformRepoID, webhookCallbackURL)
+	webhookID, webhookURL, webhookSecret, err := s.ensureWebhook(git, platformRepoID, webhookCallbackURL)
 	if err != nil {
 		return err
 	}
 
 	// Step 4: Create repository record in database
-	repoID, err := s.createRepositoryRecord(projectID, platformID, project, webhookID, webhookURL)
+	repoID, err := s.createRepositoryRecord(projectID, platformID, project, webhookID, webhookURL, webhookSecret)
 	if err != nil {
 		return err
 	}
@@ -251,25 +298,40 @@ func (s *RepositoryService) fetchGitLabProject(git *gitlab.Client, platformRepoI
 }
 
 // ensureWebhook ensures webhook exists (finds existing or creates new)
-func (s *RepositoryService) ensureWebhook(git *gitlab.Client, platformRepoID int64, callbackURL string) (int64, string, error) {
+// Returns the webhook ID, URL and the secret token GitLab will send with each event
+func (s *RepositoryService) ensureWebhook(git *gitlab.Client, platformRepoID int64, callbackURL string) (int64, string, string, error) {
 	// Try to find existing webhook first
 	webhookID, webhookURL, err := s.findExistingWebhook(git, int(platformRepoID), callbackURL)
 	if err != nil {
-		return 0, "", fmt.Errorf("failed to check existing webhook: %w", err)
+		return 0, "", "", fmt.Errorf("failed to check existing webhook: %w", err)
 	}
 
-	// If found, return it
+	// If found, reuse its secret when another repository record (e.g. of another
+	// project) already receives events through it: a new secret would lock that one out
 	if webhookID != 0 {
-		return webhookID, webhookURL, nil
+		owner, err := s.repo.FindByWebhook(platformRepoID, webhookID, webhookURL)
+		if err == nil {
+			return webhookID, webhookURL, owner.WebhookSecret, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, "", "", fmt.Errorf("failed to look up webhook owner: %w", err)
+		}
+
+		// Otherwise set a fresh secret on it (the old one is unknown to us)
+		secret, err := s.setWebhookSecret(git, int(platformRepoID), int(webhookID))
+		if err != nil {
+			return 0, "", "", fmt.Errorf("failed to set webhook secret for project %d: %w", platformRepoID, err)
+		}
+		return webhookID, webhookURL, secret, nil
 	}
 
 	// Otherwise, create new webhook
-	webhookID, webhookURL, err = s.createWebhook(git, int(platformRepoID), callbackURL)
+	webhookID, webhookURL, secret, err := s.createWebhook(git, int(platformRepoID), callbackURL)
 	if err != nil {
-		return 0, "", fmt.Errorf("failed to create webhook for project %d: %w", platformRepoID, err)
+		return 0, "", "", fmt.Errorf("failed to create webhook for project %d: %w", platformRepoID, err)
 	}
 
-	return webhookID, webhookURL, nil
+	return webhookID, webhookURL, secret, nil
 }
 
 // createRepositoryRecord creates repository record in database
@@ -279,6 +341,7 @@ func (s *RepositoryService) createRepositoryRecord(
 	project *gitlab.Project,
 	webhookID int64,
 	webhookURL string,
+	webhookSecret string,
 ) (uint, error) {
 	repo := &model.Repository{
 		ProjectID:      projectID,
@@ -291,6 +354,7 @@ func (s *RepositoryService) createRepositoryRecord(
 		DefaultBranch:  project.DefaultBranch,
 		WebhookID:      &webhookID,
 		WebhookURL:     webhookURL,
+		WebhookSecret:  webhookSecret,
 		WebhookStatus:  model.WebhookStatusNotConfigured, // Will be tested immediately after creation
 		IsActive:       true,
 	}
@@ -302,21 +366,55 @@ func (s *RepositoryService) createRepositoryRecord(
 	return repo.ID, nil
 }
 
-// createWebhook creates a webhook for a GitLab project
-func (s *RepositoryService) createWebhook(git *gitlab.Client, projectID int, callbackURL string) (int64, string, error) {
+// createWebhook creates a webhook for a GitLab project with a generated secret token
+func (s *RepositoryService) createWebhook(git *gitlab.Client, projectID int, callbackURL string) (int64, string, string, error) {
+	secret, err := crypto.GenerateToken(webhookSecretBytes)
+	if err != nil {
+		return 0, "", "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
 	opts := &gitlab.AddProjectHookOptions{
 		URL:                   gitlab.String(callbackURL),
 		MergeRequestsEvents:   gitlab.Bool(true),
+		NoteEvents:            gitlab.Bool(true), // Feedback replies on review comments
 		PushEvents:            gitlab.Bool(false),
 		EnableSSLVerification: gitlab.Bool(false),
+		Token:                 gitlab.String(secret),
 	}
 
 	hook, _, err := git.Projects.AddProjectHook(projectID, opts)
 	if err != nil {
-		return 0, "", err
+		return 0, "", "", err
+	}
+
+	return int64(hook.ID), hook.URL, secret, nil
+}
+
+// setWebhookSecret generates a new secret token and sets it on an existing webhook
+func (s *RepositoryService) setWebhookSecret(git *gitlab.Client, projectID int, hookID int) (string, error) {
+	secret, err := crypto.GenerateToken(webhookSecretBytes)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	hook, _, err := git.Projects.GetProjectHook(projectID, hookID)
+	if err != nil {
+		return "", err
+	}
+
+	// GitLab's edit endpoint requires the URL. The hook may predate comment handling,
+	// so make sure it sends the events HandsOff needs; other settings are kept.
+	_, _, err = git.Projects.EditProjectHook(projectID, hookID, &gitlab.EditProjectHookOptions{
+		URL:                 gitlab.String(hook.URL),
+		MergeRequestsEvents: gitlab.Bool(true),
+		NoteEvents:          gitlab.Bool(true),
+		Token:               gitlab.String(secret),
+	})
+	if err != nil {
+		return "", err
 	}
 
-	return int64(hook.ID), hook.URL, nil
+	return secret, nil
 }
 
 // findExistingWebhook searches for an existing webhook with the same callback URL
@@ -344,6 +442,189 @@ func (s *RepositoryService) UpdateLLMModel(id uint, llmModelID *uint) error {
 	return s.repo.UpdateLLMModel(id, llmModelID)
 }
 
+// UpdateTriggerRules validates and stores the review trigger rules of a repository
+func (s *RepositoryService) UpdateTriggerRules(id uint, projectID uint, rules model.ReviewTriggerRules) (*model.ReviewTriggerRules, error) {
+	if _, err := s.repo.Get(id, projectID); err != nil {
+		return nil, fmt.Errorf("repository not found: %w", err)
+	}
+
+	if err := webhook.ValidateTriggerRules(rules); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTriggerRules, err)
+	}
+
+	if err := s.repo.UpdateTriggerRules(id, &rules); err != nil {
+		return nil, fmt.Errorf("failed to update trigger rules: %w", err)
+	}
+	return &rules, nil
+}
+
+// UpdateQualityGate validates and stores the quality gate of a repository
+func (s *RepositoryService) UpdateQualityGate(id uint, projectID uint, gate model.QualityGate) (*model.QualityGate, error) {
+	if _, err := s.repo.Get(id, projectID); err != nil {
+		return nil, fmt.Errorf("repository not found: %w", err)
+	}
+
+	if err := ValidateQualityGate(gate); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateQualityGate(id, &gate); err != nil {
+		return nil, fmt.Errorf("failed to update quality gate: %w", err)
+	}
+	return &gate, nil
+}
+
+// UpdateLabelRules validates and stores the MR label rules of a repository
+func (s *RepositoryService) UpdateLabelRules(id uint, projectID uint, rules []model.LabelRule) ([]model.LabelRule, error) {
+	if _, err := s.repo.Get(id, projectID); err != nil {
+		return nil, fmt.Errorf("repository not found: %w", err)
+	}
+
+	if err := ValidateLabelRules(rules); err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		rules = []model.LabelRule{}
+	}
+	for i := range rules {
+		rules[i].Label = strings.TrimSpace(rules[i].Label)
+	}
+
+	if err := s.repo.UpdateLabelRules(id, rules); err != nil {
+		return nil, fmt.Errorf("failed to update label rules: %w", err)
+	}
+	return rules, nil
+}
+
+// UpdateMRSummaryMode sets how generated MR summaries are posted ("" turns them off)
+func (s *RepositoryService) UpdateMRSummaryMode(id uint, projectID uint, mode string) error {
+	if _, err := s.repo.Get(id, projectID); err != nil {
+		return fmt.Errorf("repository not found: %w", err)
+	}
+
+	switch mode {
+	case model.MRSummaryOff, model.MRSummaryComment, model.MRSummaryDescription:
+	default:
+		return fmt.Errorf("%w: mode must be empty, %q or %q",
+			ErrInvalidMRSummaryMode, model.MRSummaryComment, model.MRSummaryDescription)
+	}
+
+	if err := s.repo.UpdateMRSummaryMode(id, mode); err != nil {
+		return fmt.Errorf("failed to update MR summary mode: %w", err)
+	}
+	return nil
+}
+
+// UpdateReviewers sets the reviewer recommendation mode ("" turns it off) and how many
+// reviewers are recommended per merge request
+func (s *RepositoryService) UpdateReviewers(id uint, projectID uint, mode string, maxReviewers int) error {
+	if _, err := s.repo.Get(id, projectID); err != nil {
+		return fmt.Errorf("repository not found: %w", err)
+	}
+
+	switch mode {
+	case model.ReviewerModeOff, model.ReviewerModeSuggest, model.ReviewerModeAssign:
+	default:
+		return fmt.Errorf("%w: mode must be empty, %q or %q",
+			ErrInvalidReviewerSettings, model.ReviewerModeSuggest, model.ReviewerModeAssign)
+	}
+	if maxReviewers < 1 || maxReviewers > maxRecommendedReviewers {
+		return fmt.Errorf("%w: max_reviewers must be between 1 and %d",
+			ErrInvalidReviewerSettings, maxRecommendedReviewers)
+	}
+
+	if err := s.repo.UpdateReviewers(id, mode, maxReviewers); err != nil {
+		return fmt.Errorf("failed to update reviewer settings: %w", err)
+	}
+	return nil
+}
+
+// UpdateAnalyzers sets the static analyzers run before the LLM review (empty turns them off)
+func (s *RepositoryService) UpdateAnalyzers(id uint, projectID uint, names []string) ([]string, error) {
+	if _, err := s.repo.Get(id, projectID); err != nil {
+		return nil, fmt.Errorf("repository not found: %w", err)
+	}
+
+	analyzers := []string{}
+	for _, name := range names {
+		if !slices.Contains(analyzer.Names, name) {
+			return nil, fmt.Errorf("%w: unknown analyzer %q (supported: %s)",
+				ErrInvalidAnalyzers, name, strings.Join(analyzer.Names, ", "))
+		}
+		if !slices.Contains(analyzers, name) {
+			analyzers = append(analyzers, name)
+		}
+	}
+
+	if err := s.repo.UpdateAnalyzers(id, analyzers); err != nil {
+		return nil, fmt.Errorf("failed to update analyzers: %w", err)
+	}
+	return analyzers, nil
+}
+
+// SetCommitStatus sets the HandsOff status on a commit of a repository
+func (s *RepositoryService) SetCommitStatus(projectID uint, platformRepoID int64, sha string, state gitlab.BuildStateValue, description, targetURL string) error {
+	git, err := s.createGitLabClient(projectID)
+	if err != nil {
+		return err
+	}
+
+	opts := &gitlab.SetCommitStatusOptions{
+		State:       state,
+		Name:        gitlab.Ptr(model.QualityGateStatusName),
+		Description: gitlab.Ptr(description),
+	}
+	if targetURL != "" {
+		opts.TargetURL = gitlab.Ptr(targetURL)
+	}
+	if _, _, err := git.Commits.SetCommitStatus(int(platformRepoID), sha, opts); err != nil {
+		return fmt.Errorf("failed to set commit status: %w", err)
+	}
+	return nil
+}
+
+// UpdateResponseCache sets whether reviews of a repository bypass the LLM response cache
+func (s *RepositoryService) UpdateResponseCache(id uint, projectID uint, disabled bool) error {
+	if _, err := s.repo.Get(id, projectID); err != nil {
+		return fmt.Errorf("repository not found: %w", err)
+	}
+
+	if err := s.repo.UpdateResponseCacheDisabled(id, disabled); err != nil {
+		return fmt.Errorf("failed to update response cache setting: %w", err)
+	}
+	return nil
+}
+
+// UpdateConsensus sets the verifier provider that cross-checks high and critical findings
+// (nil turns consensus mode off) and what happens to the findings it rejects
+func (s *RepositoryService) UpdateConsensus(id uint, projectID uint, verifierID *uint, rejectedAction string) error {
+	if _, err := s.repo.Get(id, projectID); err != nil {
+		return fmt.Errorf("repository not found: %w", err)
+	}
+
+	if rejectedAction == "" {
+		rejectedAction = model.RejectedFindingDrop
+	}
+	if rejectedAction != model.RejectedFindingDrop && rejectedAction != model.RejectedFindingDowngrade {
+		return fmt.Errorf("%w: rejected action must be %q or %q",
+			ErrInvalidConsensusSettings, model.RejectedFindingDrop, model.RejectedFindingDowngrade)
+	}
+	if verifierID != nil {
+		found, err := s.repo.HasLLMProvider(projectID, *verifierID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("%w: verifier LLM provider not found", ErrInvalidConsensusSettings)
+		}
+	}
+
+	if err := s.repo.UpdateConsensus(id, verifierID, rejectedAction); err != nil {
+		return fmt.Errorf("failed to update consensus settings: %w", err)
+	}
+	return nil
+}
+
 // Delete deletes a repository and removes webhook from GitLab
 func (s *RepositoryService) Delete(id uint, projectID uint) error {
 	// Get repository
@@ -472,13 +753,13 @@ func (s *RepositoryService) RecreateWebhook(id uint, projectID uint) error {
 	}
 
 	// Create new webhook
-	webhookID, webhookURL, err := s.createWebhook(git, int(repo.PlatformRepoID), webhookConfig.WebhookCallbackURL)
+	webhookID, webhookURL, webhookSecret, err := s.createWebhook(git, int(repo.PlatformRepoID), webhookConfig.WebhookCallbackURL)
 	if err != nil {
 		return fmt.Errorf("failed to create webhook: %w", err)
 	}
 
 	// Update repository
-	return s.repo.UpdateWebhook(id, webhookID, webhookURL)
+	return s.repo.UpdateWebhook(id, webhookID, webhookURL, webhookSecret)
 }
 
 // isNotFoundError checks if error is a 404 Not Found error
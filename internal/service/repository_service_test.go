@@ -0,0 +1,63 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/repository"
+	"github.com/xanzy/go-gitlab"
+)
+
+// TestEnsureWebhookSharedHook checks an existing webhook gets a fresh secret, unless
+// another repository record already receives events through it
+func TestEnsureWebhookSharedHook(t *testing.T) {
+	const callbackURL = "https://handsoff.example.com/api/webhook"
+	var edits []map[string]interface{}
+	gitlabServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/10/hooks":
+			json.NewEncoder(w).Encode([]map[string]interface{}{{"id": 7, "url": callbackURL}})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/10/hooks/7":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 7, "url": callbackURL})
+		case r.Method == http.MethodPut && r.URL.Path == "/api/v4/projects/10/hooks/7":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			edits = append(edits, body)
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 7, "url": callbackURL})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer gitlabServer.Close()
+
+	git, err := gitlab.NewClient("token", gitlab.WithBaseURL(gitlabServer.URL))
+	if err != nil {
+		t.Fatalf("Failed to create GitLab client: %v", err)
+	}
+	db := setupTestDB(t)
+	s := &RepositoryService{repo: repository.NewRepositoryRepo(db)}
+
+	// Not known yet: a fresh secret is set, with the events HandsOff needs
+	_, _, secret, err := s.ensureWebhook(git, 10, callbackURL)
+	if err != nil {
+		t.Fatalf("ensureWebhook() error = %v", err)
+	}
+	if len(edits) != 1 || edits[0]["token"] != secret || edits[0]["note_events"] != true || edits[0]["merge_requests_events"] != true {
+		t.Fatalf("hook edits = %v, want one setting secret %q and note and merge request events", edits, secret)
+	}
+
+	// Imported by another project: its secret is reused and the hook left alone
+	hookID := int64(7)
+	db.Create(&model.Repository{ProjectID: 1, PlatformID: 1, PlatformRepoID: 10, Name: "repo",
+		WebhookID: &hookID, WebhookURL: callbackURL, WebhookSecret: "shared-secret"})
+	_, _, secret, err = s.ensureWebhook(git, 10, callbackURL)
+	if err != nil {
+		t.Fatalf("ensureWebhook() error = %v", err)
+	}
+	if secret != "shared-secret" || len(edits) != 1 {
+		t.Errorf("ensureWebhook() secret = %q with %d hook edits, want the shared secret and no edit", secret, len(edits))
+	}
+}
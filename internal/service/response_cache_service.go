@@ -0,0 +1,88 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ResponseCacheService stores LLM review responses keyed by llm.ResponseCacheKey
+type ResponseCacheService struct {
+	db *gorm.DB
+}
+
+// NewResponseCacheService creates a new response cache service
+func NewResponseCacheService(db *gorm.DB) *ResponseCacheService {
+	return &ResponseCacheService{db: db}
+}
+
+// ResponseCacheEntry describes a response to cache
+type ResponseCacheEntry struct {
+	Key           string
+	ProjectID     uint
+	LLMProviderID uint
+	ModelName     string
+}
+
+// Get returns the cached response for key, or nil if there is none or it expired
+func (s *ResponseCacheService) Get(key string, now time.Time) (*llm.ReviewResponse, error) {
+	var entry model.LLMResponseCache
+	err := s.db.Where("cache_key = ? AND expires_at > ?", key, now).First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var resp llm.ReviewResponse
+	if err := json.Unmarshal([]byte(entry.Response), &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode cached response: %w", err)
+	}
+
+	s.db.Model(&entry).Updates(map[string]interface{}{
+		"hit_count":   gorm.Expr("hit_count + 1"),
+		"last_hit_at": now,
+	})
+	return &resp, nil
+}
+
+// Put caches a response for ttl, replacing any previous entry for the key.
+// Expired entries are purged along the way.
+func (s *ResponseCacheService) Put(entry ResponseCacheEntry, resp *llm.ReviewResponse, ttl time.Duration, now time.Time) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("failed to encode response: %w", err)
+	}
+
+	record := &model.LLMResponseCache{
+		CreatedAt:     now,
+		CacheKey:      entry.Key,
+		ProjectID:     entry.ProjectID,
+		LLMProviderID: entry.LLMProviderID,
+		ModelName:     entry.ModelName,
+		Response:      string(data),
+		ExpiresAt:     now.Add(ttl),
+	}
+	err = s.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "cache_key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"created_at":  record.CreatedAt,
+			"response":    record.Response,
+			"expires_at":  record.ExpiresAt,
+			"hit_count":   0,
+			"last_hit_at": nil,
+		}),
+	}).Create(record).Error
+	if err != nil {
+		return err
+	}
+
+	return s.db.Where("expires_at <= ?", now).Delete(&model.LLMResponseCache{}).Error
+}
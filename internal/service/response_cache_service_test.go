@@ -0,0 +1,61 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+)
+
+func TestResponseCacheService(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.LLMResponseCache{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	svc := NewResponseCacheService(db)
+
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	entry := ResponseCacheEntry{Key: "key", ProjectID: 1, LLMProviderID: 1, ModelName: "gpt-4"}
+	resp := &llm.ReviewResponse{
+		Summary:     "Looks good",
+		Score:       90,
+		Suggestions: []llm.FixSuggestion{{FilePath: "main.go", Severity: "low"}},
+		TokenUsage:  llm.TokenUsage{TotalTokens: 1200},
+	}
+
+	if got, err := svc.Get("key", now); err != nil || got != nil {
+		t.Fatalf("Expected a miss before Put, got %+v (err=%v)", got, err)
+	}
+
+	if err := svc.Put(entry, resp, time.Hour, now); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	got, err := svc.Get("key", now.Add(30*time.Minute))
+	if err != nil || got == nil {
+		t.Fatalf("Expected a hit, got %+v (err=%v)", got, err)
+	}
+	if got.Summary != resp.Summary || got.Score != resp.Score || len(got.Suggestions) != 1 {
+		t.Errorf("Cached response differs: %+v", got)
+	}
+
+	if got, _ := svc.Get("key", now.Add(2*time.Hour)); got != nil {
+		t.Error("Expected a miss after the TTL")
+	}
+
+	// Replacing an entry restarts its TTL and purges expired ones
+	resp.Score = 70
+	later := now.Add(2 * time.Hour)
+	if err := svc.Put(entry, resp, time.Hour, later); err != nil {
+		t.Fatalf("Second Put failed: %v", err)
+	}
+	if got, _ := svc.Get("key", later.Add(time.Minute)); got == nil || got.Score != 70 {
+		t.Errorf("Expected the replaced response, got %+v", got)
+	}
+
+	var count int64
+	db.Model(&model.LLMResponseCache{}).Count(&count)
+	if count != 1 {
+		t.Errorf("Expected 1 cache entry, got %d", count)
+	}
+}
@@ -29,8 +29,15 @@ func (s *ReviewStorageService) SaveReviewResult(
 		// Calculate statistics
 		stats := calculateStatistics(response.Suggestions)
 
-		// Update review result with all fields
+		// Store suggestions, linking findings reported again to their earlier rows
 		now := time.Now()
+		progress, err := trackSuggestions(tx, reviewResult, response.Suggestions, now)
+		if err != nil {
+			return err
+		}
+		response.Progress = &progress
+
+		// Update review result with all fields
 		updates := map[string]interface{}{
 			"status":                  "completed",
 			"summary":                 response.Summary,
@@ -45,35 +52,15 @@ func (s *ReviewStorageService) SaveReviewResult(
 			"security_issues_count":   stats.SecurityCount,
 			"performance_issues_count": stats.PerformanceCount,
 			"quality_issues_count":    stats.QualityCount,
+			"new_issues_count":        progress.New,
+			"persisting_issues_count": progress.Persisting,
+			"resolved_issues_count":   progress.Resolved,
 		}
 
 		if err := tx.Model(reviewResult).Updates(updates).Error; err != nil {
 			return fmt.Errorf("failed to update review result: %w", err)
 		}
 
-		// Batch insert fix suggestions
-		if len(response.Suggestions) > 0 {
-		fixSuggestions := make([]model.FixSuggestion, 0, len(response.Suggestions))
-		for _, sug := range response.Suggestions {
-			fixSuggestions = append(fixSuggestions, model.FixSuggestion{
-				ReviewResultID: reviewResult.ID,
-				FilePath:       sug.FilePath,
-				LineStart:      sug.LineStart,
-				LineEnd:        sug.LineEnd,
-				Severity:       sug.Severity,
-				Category:       sug.Category,
-				Description:    sug.Description,
-				Suggestion:     sug.Suggestion,
-				CodeSnippet:    sug.CodeSnippet,
-			})
-		}
-
-			// Batch insert with CreateInBatches for better performance
-			if err := tx.CreateInBatches(fixSuggestions, 100).Error; err != nil {
-				return fmt.Errorf("failed to batch insert fix suggestions: %w", err)
-			}
-		}
-
 		return nil
 	})
 }
@@ -108,12 +95,16 @@ type ReviewStatistics struct {
 }
 
 // calculateStatistics calculates statistics from suggestions
+// Findings rejected by the verifier model are not counted
 func calculateStatistics(suggestions []llm.FixSuggestion) ReviewStatistics {
-	stats := ReviewStatistics{
-		TotalIssues: len(suggestions),
-	}
+	stats := ReviewStatistics{}
 
 	for _, sug := range suggestions {
+		if sug.Rejected() {
+			continue
+		}
+		stats.TotalIssues++
+
 		// Count by severity
 		switch sug.Severity {
 		case "critical":
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+	"gorm.io/gorm"
+)
+
+// MaxActiveReviewerProfiles bounds the parallel LLM calls made for one review
+const MaxActiveReviewerProfiles = 5
+
+// ErrInvalidReviewerProfile is returned when a reviewer profile fails validation
+var ErrInvalidReviewerProfile = errors.New("invalid reviewer profile")
+
+// ErrReviewerProfileNotFound is returned when a reviewer profile does not exist in the project
+var ErrReviewerProfileNotFound = errors.New("reviewer profile not found")
+
+var reviewerCategories = []string{
+	model.ReviewCategorySecurity,
+	model.ReviewCategoryPerformance,
+	model.ReviewCategoryCorrectness,
+	model.ReviewCategoryQuality,
+	model.ReviewCategoryStyle,
+}
+
+// ReviewerProfileInput holds the editable fields of a reviewer profile
+type ReviewerProfileInput struct {
+	Name           string
+	Category       string
+	PromptTemplate string
+	LLMProviderID  *uint
+	IsActive       bool
+}
+
+// ReviewerProfileService manages reviewer profiles
+type ReviewerProfileService struct {
+	db *gorm.DB
+}
+
+// NewReviewerProfileService creates a new reviewer profile service
+func NewReviewerProfileService(db *gorm.DB) *ReviewerProfileService {
+	return &ReviewerProfileService{db: db}
+}
+
+// List returns the reviewer profiles of a project
+func (s *ReviewerProfileService) List(projectID uint) ([]model.ReviewerProfile, error) {
+	var profiles []model.ReviewerProfile
+	err := s.db.Where("project_id = ?", projectID).
+		Preload("LLMProvider").
+		Order("id").
+		Find(&profiles).Error
+	return profiles, err
+}
+
+// ActiveProfiles returns the profiles that review the project's merge requests
+func (s *ReviewerProfileService) ActiveProfiles(projectID uint) ([]model.ReviewerProfile, error) {
+	var profiles []model.ReviewerProfile
+	err := s.db.Where("project_id = ? AND is_active = ?", projectID, true).
+		Preload("LLMProvider").
+		Order("id").
+		Limit(MaxActiveReviewerProfiles).
+		Find(&profiles).Error
+	return profiles, err
+}
+
+// Create adds a reviewer profile to the project
+func (s *ReviewerProfileService) Create(projectID uint, input ReviewerProfileInput) (*model.ReviewerProfile, error) {
+	profile := &model.ReviewerProfile{ProjectID: projectID}
+	applyReviewerProfileInput(profile, input)
+	if err := s.validate(profile); err != nil {
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(profile).Error; err != nil {
+			return err
+		}
+		// GORM stores a false is_active as the column default (true)
+		if !input.IsActive {
+			return tx.Model(profile).Update("is_active", false).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// Update changes a reviewer profile of the project
+func (s *ReviewerProfileService) Update(id, projectID uint, input ReviewerProfileInput) (*model.ReviewerProfile, error) {
+	profile, err := s.get(id, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	applyReviewerProfileInput(profile, input)
+	if err := s.validate(profile); err != nil {
+		return nil, err
+	}
+
+	profile.LLMProvider = nil
+	if err := s.db.Save(profile).Error; err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// Delete removes a reviewer profile of the project
+func (s *ReviewerProfileService) Delete(id, projectID uint) error {
+	result := s.db.Where("id = ? AND project_id = ?", id, projectID).Delete(&model.ReviewerProfile{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrReviewerProfileNotFound
+	}
+	return nil
+}
+
+func applyReviewerProfileInput(profile *model.ReviewerProfile, input ReviewerProfileInput) {
+	profile.Name = strings.TrimSpace(input.Name)
+	profile.Category = strings.ToLower(strings.TrimSpace(input.Category))
+	profile.PromptTemplate = strings.TrimSpace(input.PromptTemplate)
+	profile.LLMProviderID = input.LLMProviderID
+	profile.IsActive = input.IsActive
+}
+
+func (s *ReviewerProfileService) validate(profile *model.ReviewerProfile) error {
+	if profile.Name == "" || len(profile.Name) > 100 {
+		return fmt.Errorf("%w: name is required (at most 100 characters)", ErrInvalidReviewerProfile)
+	}
+
+	validCategory := false
+	for _, category := range reviewerCategories {
+		validCategory = validCategory || profile.Category == category
+	}
+	if !validCategory {
+		return fmt.Errorf("%w: category must be one of %s", ErrInvalidReviewerProfile, strings.Join(reviewerCategories, ", "))
+	}
+
+	if profile.PromptTemplate != "" {
+		if err := llm.ValidatePromptTemplate(profile.PromptTemplate); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidReviewerProfile, err)
+		}
+	}
+
+	var count int64
+	if profile.LLMProviderID != nil {
+		s.db.Model(&model.LLMProvider{}).
+			Where("id = ? AND project_id = ?", *profile.LLMProviderID, profile.ProjectID).
+			Count(&count)
+		if count == 0 {
+			return fmt.Errorf("%w: LLM provider not found", ErrInvalidReviewerProfile)
+		}
+	}
+
+	err := s.db.Model(&model.ReviewerProfile{}).
+		Where("project_id = ? AND name = ? AND id <> ?", profile.ProjectID, profile.Name, profile.ID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: a profile named %q already exists", ErrInvalidReviewerProfile, profile.Name)
+	}
+
+	if profile.IsActive {
+		err := s.db.Model(&model.ReviewerProfile{}).
+			Where("project_id = ? AND is_active = ? AND id <> ?", profile.ProjectID, true, profile.ID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count >= MaxActiveReviewerProfiles {
+			return fmt.Errorf("%w: at most %d profiles can be active", ErrInvalidReviewerProfile, MaxActiveReviewerProfiles)
+		}
+	}
+	return nil
+}
+
+func (s *ReviewerProfileService) get(id, projectID uint) (*model.ReviewerProfile, error) {
+	var profile model.ReviewerProfile
+	err := s.db.Where("id = ? AND project_id = ?", id, projectID).First(&profile).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrReviewerProfileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/handsoff/handsoff/internal/model"
+)
+
+func TestReviewerProfileServiceCreateInactive(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.ReviewerProfile{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	svc := NewReviewerProfileService(db)
+
+	profile, err := svc.Create(1, ReviewerProfileInput{Name: "Security", Category: model.ReviewCategorySecurity})
+	if err != nil {
+		t.Fatalf("Failed to create reviewer profile: %v", err)
+	}
+
+	// An inactive profile must not become an extra review pass
+	active, err := svc.ActiveProfiles(1)
+	if err != nil {
+		t.Fatalf("ActiveProfiles failed: %v", err)
+	}
+	if len(active) != 0 {
+		t.Errorf("Expected no active profiles, got %d (created profile %d)", len(active), profile.ID)
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/handsoff/handsoff/internal/codeowners"
+	"github.com/handsoff/handsoff/internal/model"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxRecordedFiles caps the changed files stored per review
+	maxRecordedFiles = 500
+	// reviewerHistory is how far back MR authorship counts towards recommendations
+	reviewerHistory = 180 * 24 * time.Hour
+	// maxHistoryReviews caps the past reviews scanned per recommendation
+	maxHistoryReviews = 500
+)
+
+// Recommendation weights per changed file
+const (
+	ownerWeight  = 3 // Code owner of the file
+	authorWeight = 2 // Authored a recent MR changing the file
+	nearbyWeight = 1 // Authored a recent MR changing its directory
+)
+
+// ReviewerCandidate is a human reviewer recommended for a merge request
+type ReviewerCandidate struct {
+	Username      string
+	Score         int
+	OwnedFiles    int // Changed files the user is a code owner of
+	AuthoredFiles int // Changed files the user changed in recent merge requests
+	NearbyFiles   int // Changed files in directories the user recently changed
+}
+
+// Reason explains the recommendation, e.g. "code owner of 2 changed files"
+func (c ReviewerCandidate) Reason() string {
+	var reasons []string
+	if c.OwnedFiles > 0 {
+		reasons = append(reasons, fmt.Sprintf("code owner of %s", files(c.OwnedFiles)))
+	}
+	if c.AuthoredFiles > 0 {
+		reasons = append(reasons, fmt.Sprintf("recently changed %s", files(c.AuthoredFiles)))
+	}
+	if c.NearbyFiles > 0 {
+		reasons = append(reasons, fmt.Sprintf("recently worked next to %s", files(c.NearbyFiles)))
+	}
+	return strings.Join(reasons, ", ")
+}
+
+func files(n int) string {
+	if n == 1 {
+		return "1 changed file"
+	}
+	return fmt.Sprintf("%d changed files", n)
+}
+
+// ReviewerService recommends human reviewers from CODEOWNERS and path history
+type ReviewerService struct {
+	db *gorm.DB
+}
+
+// NewReviewerService creates a new reviewer service
+func NewReviewerService(db *gorm.DB) *ReviewerService {
+	return &ReviewerService{db: db}
+}
+
+// RecordChangedFiles stores the files a review covered, as path history for later recommendations
+func (s *ReviewerService) RecordChangedFiles(reviewID uint, files []string) error {
+	if len(files) > maxRecordedFiles {
+		files = files[:maxRecordedFiles]
+	}
+	return s.db.Model(&model.ReviewResult{ID: reviewID}).Select("changed_files").
+		Updates(&model.ReviewResult{ChangedFiles: files}).Error
+}
+
+// SaveSuggested stores the reviewers recommended for a review
+func (s *ReviewerService) SaveSuggested(reviewID uint, usernames []string) error {
+	return s.db.Model(&model.ReviewResult{ID: reviewID}).Select("suggested_reviewers").
+		Updates(&model.ReviewResult{SuggestedReviewers: usernames}).Error
+}
+
+// Recommend ranks reviewers for the files a merge request changes: the code owners
+// of each file (owners may be nil) and the authors of recent merge requests of the
+// repository that changed the same files or directories. The MR author is left out.
+func (s *ReviewerService) Recommend(review *model.ReviewResult, changed []string, owners *codeowners.File, now time.Time) ([]ReviewerCandidate, error) {
+	author := strings.ToLower(review.MRAuthor)
+	candidates := map[string]*ReviewerCandidate{}
+	candidate := func(username string) *ReviewerCandidate {
+		key := strings.ToLower(username)
+		if key == "" || key == author {
+			return nil
+		}
+		if candidates[key] == nil {
+			candidates[key] = &ReviewerCandidate{Username: username}
+		}
+		return candidates[key]
+	}
+
+	if owners != nil {
+		for _, file := range changed {
+			for _, owner := range owners.Owners(file) {
+				if strings.Contains(owner, "/") {
+					continue // Subgroups cannot be asked for a review
+				}
+				if c := candidate(owner); c != nil {
+					c.OwnedFiles++
+					c.Score += ownerWeight
+				}
+			}
+		}
+	}
+
+	history, err := s.pathHistory(review, changed, now.Add(-reviewerHistory))
+	if err != nil {
+		return nil, err
+	}
+	for username, touched := range history {
+		c := candidate(username)
+		if c == nil {
+			continue
+		}
+		for _, file := range changed {
+			switch {
+			case touched[file]:
+				c.AuthoredFiles++
+				c.Score += authorWeight
+			case touched[path.Dir(file)+"/"]:
+				c.NearbyFiles++
+				c.Score += nearbyWeight
+			}
+		}
+	}
+
+	ranked := make([]ReviewerCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		if c.Score > 0 {
+			ranked = append(ranked, *c)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Username < ranked[j].Username
+	})
+	return ranked, nil
+}
+
+// pathHistory returns, per author of a recent merge request of the repository, the
+// files they changed and their directories (with a trailing "/"). The files of past
+// findings count too, so reviews recorded before changed files were stored still help.
+func (s *ReviewerService) pathHistory(review *model.ReviewResult, changed []string, since time.Time) (map[string]map[string]bool, error) {
+	history := map[string]map[string]bool{}
+	add := func(author, file string) {
+		if history[author] == nil {
+			history[author] = map[string]bool{}
+		}
+		history[author][file] = true
+		history[author][path.Dir(file)+"/"] = true
+	}
+
+	var reviews []model.ReviewResult
+	err := s.db.Select("id", "mr_author", "changed_files").
+		Where("repository_id = ? AND id <> ? AND mr_author <> '' AND updated_at >= ?", review.RepositoryID, review.ID, since).
+		Order("id DESC").
+		Limit(maxHistoryReviews).
+		Find(&reviews).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load review history: %w", err)
+	}
+	for _, r := range reviews {
+		for _, file := range r.ChangedFiles {
+			add(r.MRAuthor, file)
+		}
+	}
+
+	if len(changed) == 0 {
+		return history, nil
+	}
+	var findings []struct {
+		MRAuthor string
+		FilePath string
+	}
+	err = s.db.Model(&model.FixSuggestion{}).
+		Select("DISTINCT review_results.mr_author, fix_suggestions.file_path").
+		Joins("JOIN review_results ON review_results.id = fix_suggestions.review_result_id").
+		Where("review_results.repository_id = ? AND review_results.id <> ? AND review_results.mr_author <> ''", review.RepositoryID, review.ID).
+		Where("review_results.updated_at >= ?", since).
+		Where("fix_suggestions.file_path IN ?", changed).
+		Scan(&findings).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load finding history: %w", err)
+	}
+	for _, f := range findings {
+		add(f.MRAuthor, f.FilePath)
+	}
+	return history, nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/handsoff/handsoff/internal/codeowners"
+	"github.com/handsoff/handsoff/internal/model"
+)
+
+func TestReviewerRecommend(t *testing.T) {
+	db := setupTestDB(t)
+	svc := NewReviewerService(db)
+	now := time.Now()
+
+	past := []struct {
+		author  string
+		files   []string
+		updated time.Time
+	}{
+		{"bob", []string{"api/user.go"}, now.Add(-24 * time.Hour)},
+		{"carol", []string{"api/order.go"}, now.Add(-48 * time.Hour)},
+		{"dave", []string{"api/user.go"}, now.Add(-200 * 24 * time.Hour)}, // Too old
+		{"alice", []string{"api/user.go"}, now},                           // The MR author
+	}
+	for i, p := range past {
+		review := model.ReviewResult{RepositoryID: 1, MergeRequestID: int64(i + 1), MRAuthor: p.author}
+		db.Create(&review)
+		if err := svc.RecordChangedFiles(review.ID, p.files); err != nil {
+			t.Fatalf("RecordChangedFiles() error = %v", err)
+		}
+		db.Model(&review).UpdateColumn("updated_at", p.updated)
+	}
+
+	// Reviewed before changed files were recorded: its findings count instead
+	legacy := model.ReviewResult{RepositoryID: 1, MergeRequestID: 10, MRAuthor: "erin"}
+	db.Create(&legacy)
+	db.Create(&model.FixSuggestion{ReviewResultID: legacy.ID, FilePath: "web/app.ts", Severity: "low", Category: "style", Description: "x"})
+
+	// Another repository's history is ignored
+	other := model.ReviewResult{RepositoryID: 2, MergeRequestID: 1, MRAuthor: "frank"}
+	db.Create(&other)
+	svc.RecordChangedFiles(other.ID, []string{"api/user.go"})
+
+	current := model.ReviewResult{RepositoryID: 1, MergeRequestID: 20, MRAuthor: "alice"}
+	db.Create(&current)
+
+	owners := codeowners.Parse("api/ @carol @alice @backend/leads\n")
+	changed := []string{"api/user.go", "web/app.ts"}
+	got, err := svc.Recommend(&current, changed, owners, now)
+	if err != nil {
+		t.Fatalf("Recommend() error = %v", err)
+	}
+
+	want := []ReviewerCandidate{
+		{Username: "carol", Score: 4, OwnedFiles: 1, NearbyFiles: 1}, // Owner of api/, worked next to api/user.go
+		{Username: "bob", Score: 2, AuthoredFiles: 1},
+		{Username: "erin", Score: 2, AuthoredFiles: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Recommend() = %+v, want %+v", got, want)
+	}
+	if reason := got[0].Reason(); reason != "code owner of 1 changed file, recently worked next to 1 changed file" {
+		t.Errorf("Reason() = %q", reason)
+	}
+}
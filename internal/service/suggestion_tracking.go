@@ -0,0 +1,168 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+	"gorm.io/gorm"
+)
+
+// trackedColumns are rewritten when a finding is reported again; its feedback,
+// first sighting and creation time are kept
+var trackedColumns = []string{
+	"updated_at", "line_start", "line_end", "severity", "description", "suggestion", "code_snippet",
+	"verdict", "verdict_confidence", "verdict_reason", "number", "fingerprint", "sim_hash",
+	"model_name", "prompt_version", "source", "last_seen_at", "last_seen_sha", "occurrences", "resolved_at", "resolved_sha",
+}
+
+// trackSuggestions stores a review run's findings against the ones earlier runs
+// stored for the merge request: a finding reported again updates its row, a new
+// one gets a row, and open findings no longer reported are marked resolved.
+// Findings developers dismissed are not resolved, since the review leaves them out.
+func trackSuggestions(tx *gorm.DB, review *model.ReviewResult, suggestions []llm.FixSuggestion, now time.Time) (llm.IssueProgress, error) {
+	var progress llm.IssueProgress
+
+	var existing []model.FixSuggestion
+	if err := tx.Where("review_result_id = ?", review.ID).Order("id").Find(&existing).Error; err != nil {
+		return progress, fmt.Errorf("failed to load previous suggestions: %w", err)
+	}
+
+	// Only the latest run's findings keep their #N
+	if err := tx.Model(&model.FixSuggestion{}).
+		Where("review_result_id = ? AND number <> 0", review.ID).
+		Update("number", 0).Error; err != nil {
+		return progress, fmt.Errorf("failed to reset suggestion numbers: %w", err)
+	}
+
+	matches := matchSuggestions(existing, suggestions)
+	seen := make(map[string]bool, len(suggestions))
+	var created []model.FixSuggestion
+	for i, sug := range suggestions {
+		row := suggestionRow(review, sug, now)
+		seen[row.Fingerprint] = true
+
+		if matches[i] < 0 {
+			created = append(created, row)
+			if !sug.Rejected() {
+				progress.New++
+			}
+			continue
+		}
+
+		previous := existing[matches[i]]
+		row.Occurrences = previous.Occurrences + 1
+		if err := tx.Model(&model.FixSuggestion{ID: previous.ID}).Select(trackedColumns).Updates(&row).Error; err != nil {
+			return progress, fmt.Errorf("failed to update suggestion %d: %w", previous.ID, err)
+		}
+		switch {
+		case sug.Rejected():
+		case previous.ResolvedAt != nil:
+			progress.New++ // Came back after being resolved
+		default:
+			progress.Persisting++
+		}
+	}
+
+	if len(created) > 0 {
+		if err := tx.CreateInBatches(created, 100).Error; err != nil {
+			return progress, fmt.Errorf("failed to batch insert fix suggestions: %w", err)
+		}
+	}
+
+	matched := make(map[int]bool, len(matches))
+	for _, j := range matches {
+		matched[j] = true
+	}
+	for j, previous := range existing {
+		if matched[j] || previous.ResolvedAt != nil || dismissedFeedback(previous.FeedbackStatus) {
+			continue
+		}
+		if err := tx.Model(&previous).Updates(map[string]interface{}{
+			"resolved_at":  now,
+			"resolved_sha": review.HeadSHA,
+		}).Error; err != nil {
+			return progress, fmt.Errorf("failed to resolve suggestion %d: %w", previous.ID, err)
+		}
+		// Rejected findings were never shown; same-fingerprint rows are duplicates
+		// stored before findings were tracked
+		if previous.Verdict != model.VerdictRejected && !seen[previous.Fingerprint] {
+			progress.Resolved++
+		}
+	}
+
+	return progress, nil
+}
+
+// matchSuggestions returns, for each finding, the index of the earlier row it is
+// another occurrence of, or -1. Rows are matched by fingerprint first, then by a
+// similar description in the same file and category; each row matches once.
+func matchSuggestions(existing []model.FixSuggestion, suggestions []llm.FixSuggestion) []int {
+	matches := make([]int, len(suggestions))
+	used := make([]bool, len(existing))
+
+	for i, sug := range suggestions {
+		matches[i] = -1
+		fingerprint := llm.Fingerprint(sug)
+		for j := len(existing) - 1; j >= 0; j-- {
+			if !used[j] && existing[j].Fingerprint == fingerprint {
+				matches[i], used[j] = j, true
+				break
+			}
+		}
+	}
+
+	for i, sug := range suggestions {
+		if matches[i] >= 0 {
+			continue
+		}
+		hash := llm.SimHash(sug.Description)
+		for j := len(existing) - 1; j >= 0; j-- {
+			e := existing[j]
+			if !used[j] && e.FilePath == sug.FilePath && strings.EqualFold(e.Category, sug.Category) &&
+				llm.SimilarHashes(e.SimHash, hash) {
+				matches[i], used[j] = j, true
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// suggestionRow converts a finding of the review's current commit to its row
+func suggestionRow(review *model.ReviewResult, sug llm.FixSuggestion, now time.Time) model.FixSuggestion {
+	source := sug.Source
+	if source == "" {
+		source = model.SuggestionSourceLLM
+	}
+	return model.FixSuggestion{
+		ReviewResultID:    review.ID,
+		FilePath:          sug.FilePath,
+		LineStart:         sug.LineStart,
+		LineEnd:           sug.LineEnd,
+		Severity:          sug.Severity,
+		Category:          sug.Category,
+		Description:       sug.Description,
+		Suggestion:        sug.Suggestion,
+		CodeSnippet:       sug.CodeSnippet,
+		Verdict:           sug.Verdict,
+		VerdictConfidence: sug.VerdictConfidence,
+		VerdictReason:     sug.VerdictReason,
+		Number:            sug.Number,
+		Fingerprint:       llm.Fingerprint(sug),
+		SimHash:           llm.SimHash(sug.Description),
+		ModelName:         sug.ModelName,
+		PromptVersion:     sug.PromptVersion,
+		Source:            source,
+		FirstSeenSHA:      review.HeadSHA,
+		LastSeenAt:        &now,
+		LastSeenSHA:       review.HeadSHA,
+		Occurrences:       1,
+	}
+}
+
+func dismissedFeedback(status string) bool {
+	return status == model.FeedbackWontFix || status == model.FeedbackFalsePositive
+}
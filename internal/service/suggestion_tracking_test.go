@@ -0,0 +1,142 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+)
+
+func TestSaveReviewResultTracksFindingsAcrossRuns(t *testing.T) {
+	db := setupTestDB(t)
+	storage := NewReviewStorageService(db)
+
+	review := model.ReviewResult{RepositoryID: 1, MergeRequestID: 7, Status: "processing", HeadSHA: "aaa"}
+	if err := db.Create(&review).Error; err != nil {
+		t.Fatalf("Failed to create review result: %v", err)
+	}
+
+	injection := llm.FixSuggestion{
+		FilePath: "db.go", LineStart: 10, Severity: "critical", Category: "security",
+		Description: "SQL query built by concatenating user input, allowing SQL injection",
+		CodeSnippet: `db.Query("SELECT * FROM users WHERE id = " + id)`,
+	}
+	closeErr := llm.FixSuggestion{
+		FilePath: "file.go", LineStart: 5, Severity: "medium", Category: "bug",
+		Description: "Error returned by Close is ignored", CodeSnippet: "f.Close()",
+	}
+	unusedVar := llm.FixSuggestion{
+		FilePath: "util.go", LineStart: 3, Severity: "low", Category: "style",
+		Description: "Unused variable", CodeSnippet: "var unused = 1",
+	}
+	dismissed := llm.FixSuggestion{
+		FilePath: "util.go", LineStart: 9, Severity: "low", Category: "style",
+		Description: "Magic number", CodeSnippet: "timeout := 30",
+	}
+
+	save := func(sha string, suggestions ...llm.FixSuggestion) *llm.IssueProgress {
+		t.Helper()
+		review.HeadSHA = sha
+		resp := &llm.ReviewResponse{Score: 80, Suggestions: suggestions}
+		if err := storage.SaveReviewResult(&review, resp); err != nil {
+			t.Fatalf("SaveReviewResult failed: %v", err)
+		}
+		return resp.Progress
+	}
+
+	first := save("aaa", injection, closeErr, unusedVar, dismissed)
+	if *first != (llm.IssueProgress{New: 4}) {
+		t.Errorf("first review progress = %+v, want 4 new", *first)
+	}
+	if err := db.Model(&model.FixSuggestion{}).Where("description = ?", dismissed.Description).
+		Update("feedback_status", model.FeedbackFalsePositive).Error; err != nil {
+		t.Fatalf("Failed to dismiss suggestion: %v", err)
+	}
+
+	// Second commit: the injection moved, the close error was reworded with other code,
+	// the unused variable is gone and a new finding appears; the dismissed one is
+	// suppressed by the worker
+	moved := injection
+	moved.LineStart = 42
+	reworded := closeErr
+	reworded.Description = "The error returned by Close is not checked"
+	reworded.CodeSnippet = "defer f.Close()"
+	newIssue := llm.FixSuggestion{
+		FilePath: "api.go", LineStart: 1, Severity: "high", Category: "security",
+		Description: "Missing authorization check", CodeSnippet: "func Delete(c *gin.Context) {",
+	}
+
+	second := save("bbb", moved, reworded, newIssue)
+	if want := (llm.IssueProgress{New: 1, Persisting: 2, Resolved: 1}); *second != want {
+		t.Errorf("second review progress = %+v, want %+v", *second, want)
+	}
+
+	var rows []model.FixSuggestion
+	db.Order("id").Find(&rows)
+	if len(rows) != 5 {
+		t.Fatalf("stored %d suggestion rows, want 5", len(rows))
+	}
+	byDescription := map[string]model.FixSuggestion{}
+	for _, row := range rows {
+		byDescription[row.Description] = row
+	}
+
+	if row := byDescription[injection.Description]; row.Occurrences != 2 || row.LineStart != 42 ||
+		row.FirstSeenSHA != "aaa" || row.LastSeenSHA != "bbb" || row.ResolvedAt != nil {
+		t.Errorf("moved finding = %+v, want 2 occurrences at line 42 seen aaa..bbb", row)
+	}
+	if row := byDescription[reworded.Description]; row.Occurrences != 2 || row.FirstSeenSHA != "aaa" {
+		t.Errorf("reworded finding = %+v, want it linked to the first review's row", row)
+	}
+	if row := byDescription[unusedVar.Description]; row.ResolvedAt == nil || row.ResolvedSHA != "bbb" || row.Number != 0 {
+		t.Errorf("gone finding = %+v, want resolved at bbb", row)
+	}
+	if row := byDescription[dismissed.Description]; row.ResolvedAt != nil || row.FeedbackStatus != model.FeedbackFalsePositive {
+		t.Errorf("dismissed finding = %+v, want it left open with its feedback", row)
+	}
+
+	var stored model.ReviewResult
+	db.First(&stored, review.ID)
+	if stored.NewIssuesCount != 1 || stored.PersistingIssuesCount != 2 || stored.ResolvedIssuesCount != 1 {
+		t.Errorf("review counts = %d new, %d persisting, %d resolved; want 1, 2, 1",
+			stored.NewIssuesCount, stored.PersistingIssuesCount, stored.ResolvedIssuesCount)
+	}
+
+	// Third commit: the unused variable comes back
+	third := save("ccc", moved, reworded, newIssue, unusedVar)
+	if want := (llm.IssueProgress{New: 1, Persisting: 3}); *third != want {
+		t.Errorf("third review progress = %+v, want %+v", *third, want)
+	}
+	var reopened model.FixSuggestion
+	db.Where("description = ?", unusedVar.Description).First(&reopened)
+	if reopened.ResolvedAt != nil || reopened.Occurrences != 2 {
+		t.Errorf("reopened finding = %+v, want open with 2 occurrences", reopened)
+	}
+}
+
+func TestMatchSuggestions(t *testing.T) {
+	existing := []model.FixSuggestion{
+		{FilePath: "a.go", Category: "bug", Fingerprint: llm.Fingerprint(llm.FixSuggestion{FilePath: "a.go", Category: "bug", CodeSnippet: "x := 1"})},
+		{FilePath: "a.go", Category: "bug", SimHash: llm.SimHash("Error returned by Close is ignored")},
+	}
+
+	tests := []struct {
+		name string
+		sug  llm.FixSuggestion
+		want int
+	}{
+		{"same code", llm.FixSuggestion{FilePath: "a.go", Category: "bug", CodeSnippet: "x := 1", Description: "anything"}, 0},
+		{"reworded", llm.FixSuggestion{FilePath: "a.go", Category: "bug", CodeSnippet: "other", Description: "The error returned by Close is not checked"}, 1},
+		{"other file", llm.FixSuggestion{FilePath: "b.go", Category: "bug", CodeSnippet: "other", Description: "Error returned by Close is ignored"}, -1},
+		{"other category", llm.FixSuggestion{FilePath: "a.go", Category: "style", CodeSnippet: "other", Description: "Error returned by Close is ignored"}, -1},
+		{"unrelated", llm.FixSuggestion{FilePath: "a.go", Category: "bug", CodeSnippet: "other", Description: "Loop allocates a new buffer on every iteration"}, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchSuggestions(existing, []llm.FixSuggestion{tt.sug})[0]; got != tt.want {
+				t.Errorf("matchSuggestions() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -9,6 +9,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// ErrInvalidPromptTemplate is returned when a prompt template fails validation
+var ErrInvalidPromptTemplate = errors.New("invalid prompt template")
+
 // SystemConfigService handles system configuration operations
 type SystemConfigService struct {
 	db *gorm.DB
@@ -44,8 +47,20 @@ func (s *SystemConfigService) GetWebhookConfig(projectID uint) (*WebhookConfig,
 }
 
 // UpdateWebhookConfig updates webhook configuration for a project
+// A non-empty webhookSecret becomes the platform-wide fallback secret for repositories
+// that have no secret of their own.
 func (s *SystemConfigService) UpdateWebhookConfig(projectID uint, webhookURL, webhookSecret string) error {
-	return s.upsertConfig(s.db, projectID, model.ConfigKeyWebhookURL, webhookURL)
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.upsertConfig(tx, projectID, model.ConfigKeyWebhookURL, webhookURL); err != nil {
+			return err
+		}
+		if webhookSecret == "" {
+			return nil
+		}
+		return tx.Model(&model.GitPlatformConfig{}).
+			Where("project_id = ?", projectID).
+			Update("webhook_secret", webhookSecret).Error
+	})
 }
 
 // upsertConfig creates or updates a config value
@@ -85,6 +100,27 @@ func (s *SystemConfigService) GetReviewPrompt(projectID uint) string {
 	return config.Value
 }
 
+// GetMRSummaryPrompt returns the MR summary prompt template for a project
+// Falls back to the default template if not configured
+func (s *SystemConfigService) GetMRSummaryPrompt(projectID uint) string {
+	var config model.SystemConfig
+	err := s.db.Where("project_id = ? AND config_key = ?", projectID, model.ConfigKeyMRSummaryPrompt).
+		First(&config).Error
+
+	if err != nil || config.Value == "" {
+		return llm.DefaultMRSummaryPromptTemplate
+	}
+	return config.Value
+}
+
+// UpdateMRSummaryPrompt updates the MR summary prompt template
+func (s *SystemConfigService) UpdateMRSummaryPrompt(projectID uint, prompt string) error {
+	if err := llm.ValidatePromptTemplate(prompt); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPromptTemplate, err)
+	}
+	return s.upsertConfig(s.db, projectID, model.ConfigKeyMRSummaryPrompt, prompt)
+}
+
 // UpdateReviewPrompt updates the Review Prompt configuration
 func (s *SystemConfigService) UpdateReviewPrompt(projectID uint, prompt, version string) error {
 	// Validate prompt template
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/handsoff/handsoff/internal/model"
@@ -40,7 +41,9 @@ type UsageMetrics struct {
 	ResponseSizeB    int
 }
 
-// LogUsage logs an LLM API call to the usage log table
+// LogUsage logs an LLM API call to the usage log table. If the model's price cannot
+// be looked up, the call is still logged, without a cost, and the error is returned
+// with the log.
 func (s *UsageService) LogUsage(ctx UsageContext, metrics UsageMetrics) (*model.LLMUsageLog, error) {
 	log := &model.LLMUsageLog{
 		CreatedAt:        time.Now(),
@@ -61,9 +64,19 @@ func (s *UsageService) LogUsage(ctx UsageContext, metrics UsageMetrics) (*model.
 		ResponseSizeB:    metrics.ResponseSizeB,
 	}
 
+	// Cost is fixed at write time so later price changes don't rewrite history
+	price, priceErr := NewPricingService(s.db).PriceAt(ctx.LLMProviderID, ctx.ModelName, log.CreatedAt)
+	if price != nil {
+		log.Cost = price.Cost(metrics.PromptTokens, metrics.CompletionTokens)
+		log.Currency = price.Currency
+	}
+
 	if err := s.db.Create(log).Error; err != nil {
 		return nil, err
 	}
+	if priceErr != nil {
+		return log, fmt.Errorf("failed to look up model price: %w", priceErr)
+	}
 
 	return log, nil
 }
@@ -84,6 +97,7 @@ type TokenStats struct {
 	TotalCalls       int64   `json:"total_calls"`
 	SuccessfulCalls  int64   `json:"successful_calls"`
 	FailedCalls      int64   `json:"failed_calls"`
+	CachedCalls      int64   `json:"cached_calls"` // Served from the response cache
 	TotalTokens      int64   `json:"total_tokens"`
 	PromptTokens     int64   `json:"prompt_tokens"`
 	CompletionTokens int64   `json:"completion_tokens"`
@@ -100,7 +114,8 @@ func (s *UsageService) GetProjectTokenStats(projectID uint, startDate, endDate t
 		Select(`
 			COUNT(*) as total_calls,
 			SUM(CASE WHEN status = 'success' THEN 1 ELSE 0 END) as successful_calls,
-			SUM(CASE WHEN status != 'success' THEN 1 ELSE 0 END) as failed_calls,
+			SUM(CASE WHEN status NOT IN ('success', 'cached') THEN 1 ELSE 0 END) as failed_calls,
+			SUM(CASE WHEN status = 'cached' THEN 1 ELSE 0 END) as cached_calls,
 			COALESCE(SUM(total_tokens), 0) as total_tokens,
 			COALESCE(SUM(prompt_tokens), 0) as prompt_tokens,
 			COALESCE(SUM(completion_tokens), 0) as completion_tokens,
@@ -112,8 +127,9 @@ func (s *UsageService) GetProjectTokenStats(projectID uint, startDate, endDate t
 		return nil, err
 	}
 
-	if stats.TotalCalls > 0 {
-		stats.SuccessRate = float64(stats.SuccessfulCalls) / float64(stats.TotalCalls) * 100
+	// Cache hits made no API call, so they don't count towards the success rate
+	if apiCalls := stats.TotalCalls - stats.CachedCalls; apiCalls > 0 {
+		stats.SuccessRate = float64(stats.SuccessfulCalls) / float64(apiCalls) * 100
 	}
 
 	return &stats, nil
@@ -157,11 +173,11 @@ func (s *UsageService) GetTopRepositoriesByTokens(projectID uint, limit int, sta
 
 // DailyTokenStats represents daily token statistics
 type DailyTokenStats struct {
-	Date         string `json:"date"`
-	TotalTokens  int64  `json:"total_tokens"`
-	ReviewCount  int64  `json:"review_count"`
-	AvgDuration  int64  `json:"avg_duration_ms"`
-	SuccessRate  float64 `json:"success_rate"`
+	Date        string  `json:"date"`
+	TotalTokens int64   `json:"total_tokens"`
+	ReviewCount int64   `json:"review_count"`
+	AvgDuration int64   `json:"avg_duration_ms"`
+	SuccessRate float64 `json:"success_rate"`
 }
 
 // GetDailyTokenStats returns daily token statistics for trend analysis
@@ -176,9 +192,9 @@ func (s *UsageService) GetDailyTokenStats(projectID uint, days int) ([]DailyToke
 			COALESCE(SUM(total_tokens), 0) as total_tokens,
 			COUNT(DISTINCT review_result_id) as review_count,
 			COALESCE(AVG(duration_ms), 0) as avg_duration,
-			CASE WHEN COUNT(*) > 0 
-				THEN SUM(CASE WHEN status = 'success' THEN 1 ELSE 0 END) * 100.0 / COUNT(*) 
-				ELSE 0 
+			CASE WHEN SUM(CASE WHEN status <> 'cached' THEN 1 ELSE 0 END) > 0
+				THEN SUM(CASE WHEN status = 'success' THEN 1 ELSE 0 END) * 100.0 / SUM(CASE WHEN status <> 'cached' THEN 1 ELSE 0 END)
+				ELSE 0
 			END as success_rate
 		`).
 		Where("project_id = ? AND created_at >= ?", projectID, startDate).
@@ -193,6 +209,133 @@ func (s *UsageService) GetDailyTokenStats(projectID uint, days int) ([]DailyToke
 	return results, nil
 }
 
+// CurrencyCost is the total cost in one currency
+type CurrencyCost struct {
+	Currency    string  `json:"currency"` // Empty for calls of unpriced models
+	TotalTokens int64   `json:"total_tokens"`
+	Cost        float64 `json:"cost"`
+}
+
+// ModelCost is the cost of one model in one currency
+type ModelCost struct {
+	LLMProviderID    uint    `json:"llm_provider_id"`
+	ModelName        string  `json:"model_name"`
+	Currency         string  `json:"currency"` // Empty for calls of unpriced models
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	TotalTokens      int64   `json:"total_tokens"`
+	Cost             float64 `json:"cost"`
+}
+
+// RepositoryCost is the cost of one repository in one currency
+type RepositoryCost struct {
+	RepositoryID   uint    `json:"repository_id"`
+	RepositoryName string  `json:"repository_name"`
+	Currency       string  `json:"currency"`
+	TotalTokens    int64   `json:"total_tokens"`
+	Cost           float64 `json:"cost"`
+}
+
+// DailyCost is the cost of one day in one currency
+type DailyCost struct {
+	Date        string  `json:"date"`
+	Currency    string  `json:"currency"`
+	TotalTokens int64   `json:"total_tokens"`
+	Cost        float64 `json:"cost"`
+}
+
+// CostBreakdown splits usage cost by currency, model, repository and day.
+// Rows are per currency since prices may be in different currencies.
+type CostBreakdown struct {
+	ByCurrency   []CurrencyCost   `json:"by_currency"`
+	ByModel      []ModelCost      `json:"by_model"`
+	ByRepository []RepositoryCost `json:"by_repository"`
+	ByDay        []DailyCost      `json:"by_day"`
+}
+
+// GetCostBreakdown returns the cost of a project's usage in the period,
+// optionally limited to one repository
+func (s *UsageService) GetCostBreakdown(projectID uint, repositoryID *uint, startDate, endDate time.Time) (*CostBreakdown, error) {
+	scope := func() *gorm.DB {
+		query := s.db.Model(&model.LLMUsageLog{}).
+			Where("llm_usage_logs.project_id = ? AND llm_usage_logs.created_at >= ? AND llm_usage_logs.created_at <= ?",
+				projectID, startDate, endDate)
+		if repositoryID != nil {
+			query = query.Where("llm_usage_logs.repository_id = ?", *repositoryID)
+		}
+		return query
+	}
+
+	breakdown := &CostBreakdown{
+		ByCurrency:   []CurrencyCost{},
+		ByModel:      []ModelCost{},
+		ByRepository: []RepositoryCost{},
+		ByDay:        []DailyCost{},
+	}
+
+	err := scope().
+		Select(`
+			currency,
+			COALESCE(SUM(total_tokens), 0) as total_tokens,
+			COALESCE(SUM(cost), 0) as cost
+		`).
+		Group("currency").
+		Order("cost DESC, total_tokens DESC").
+		Scan(&breakdown.ByCurrency).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = scope().
+		Select(`
+			llm_provider_id,
+			model_name,
+			currency,
+			COALESCE(SUM(prompt_tokens), 0) as prompt_tokens,
+			COALESCE(SUM(completion_tokens), 0) as completion_tokens,
+			COALESCE(SUM(total_tokens), 0) as total_tokens,
+			COALESCE(SUM(cost), 0) as cost
+		`).
+		Group("llm_provider_id, model_name, currency").
+		Order("cost DESC, total_tokens DESC").
+		Scan(&breakdown.ByModel).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = scope().
+		Select(`
+			llm_usage_logs.repository_id,
+			repositories.name as repository_name,
+			llm_usage_logs.currency,
+			COALESCE(SUM(llm_usage_logs.total_tokens), 0) as total_tokens,
+			COALESCE(SUM(llm_usage_logs.cost), 0) as cost
+		`).
+		Joins("LEFT JOIN repositories ON llm_usage_logs.repository_id = repositories.id").
+		Group("llm_usage_logs.repository_id, repositories.name, llm_usage_logs.currency").
+		Order("cost DESC, total_tokens DESC").
+		Scan(&breakdown.ByRepository).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = scope().
+		Select(`
+			DATE(created_at) as date,
+			currency,
+			COALESCE(SUM(total_tokens), 0) as total_tokens,
+			COALESCE(SUM(cost), 0) as cost
+		`).
+		Group("DATE(created_at), currency").
+		Order("date ASC, currency ASC").
+		Scan(&breakdown.ByDay).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return breakdown, nil
+}
+
 // GetUsageLogsByReview returns all usage logs for a specific review
 func (s *UsageService) GetUsageLogsByReview(reviewResultID uint) ([]model.LLMUsageLog, error) {
 	var logs []model.LLMUsageLog
@@ -1,12 +1,16 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/handsoff/handsoff/internal/model"
 	"gorm.io/gorm"
 )
 
+// ErrInvalidPassword is returned when the current password does not match
+var ErrInvalidPassword = errors.New("current password is incorrect")
+
 // UserService handles user-related business logic
 type UserService struct {
 	db *gorm.DB
@@ -183,9 +187,38 @@ func (s *UserService) ActivateUser(id uint) error {
 	})
 }
 
-// DeactivateUser deactivates a user
+// DeactivateUser deactivates a user and revokes all of its sessions
 func (s *UserService) DeactivateUser(id uint) error {
-	return s.db.Model(&model.User{}).Where("id = ?", id).Update("is_active", false).Error
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.User{}).Where("id = ?", id).Update("is_active", false).Error; err != nil {
+			return fmt.Errorf("failed to deactivate user: %w", err)
+		}
+		return revokeUserSessions(tx, id)
+	})
+}
+
+// ChangePassword verifies the current password, stores the new one and
+// revokes all existing sessions of the user
+func (s *UserService) ChangePassword(id uint, currentPassword, newPassword string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var user model.User
+		if err := tx.First(&user, id).Error; err != nil {
+			return fmt.Errorf("failed to find user: %w", err)
+		}
+
+		if !user.CheckPassword(currentPassword) {
+			return ErrInvalidPassword
+		}
+
+		if err := user.SetPassword(newPassword); err != nil {
+			return fmt.Errorf("failed to hash password: %w", err)
+		}
+		if err := tx.Model(&user).Update("password", user.Password).Error; err != nil {
+			return fmt.Errorf("failed to update password: %w", err)
+		}
+
+		return revokeUserSessions(tx, id)
+	})
 }
 
 // ListUsers returns all users with pagination
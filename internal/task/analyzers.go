@@ -0,0 +1,111 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/handsoff/handsoff/internal/analyzer"
+	"github.com/handsoff/handsoff/internal/gitlab"
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/service"
+	"github.com/handsoff/handsoff/pkg/crypto"
+)
+
+// maxAnalyzerFindings caps the analyzer findings reported per review
+const maxAnalyzerFindings = 50
+
+// runAnalyzers runs the repository's static analyzers on a checkout of the MR head
+// and keeps their findings on the lines the diff adds. Messages can quote code, so
+// they are redacted like the diff before they reach the prompt or the database.
+// Best-effort: failures are logged and the review goes on with the findings of the
+// analyzers that ran.
+func (h *ReviewHandler) runAnalyzers(ctx context.Context, review *model.ReviewResult, diff string) []analyzer.Finding {
+	repo := review.Repository
+	if len(repo.Analyzers) == 0 || h.analyzers == nil {
+		return nil
+	}
+	if repo.HTTPURL == "" {
+		h.log.Error("Static analysis skipped, repository has no HTTP clone URL", "review_id", review.ID)
+		return nil
+	}
+
+	redactor, err := service.NewRedactionService(h.db).Redactor(repo.ProjectID)
+	if err != nil {
+		h.log.Error("Static analysis skipped, failed to load redaction rules", "error", err, "review_id", review.ID)
+		return nil
+	}
+
+	token, err := crypto.DecryptString(repo.Platform.AccessToken, h.encryptionKey)
+	if err != nil {
+		h.log.Error("Failed to decrypt platform access token", "error", err, "review_id", review.ID)
+		return nil
+	}
+
+	// The MR's head ref also covers merge requests from forks
+	ref := fmt.Sprintf("refs/merge-requests/%d/head", review.MergeRequestID)
+	// Findings of another commit would not match the diff being reviewed
+	findings, err := h.analyzers.Analyze(ctx, repo.HTTPURL, token, ref, review.HeadSHA, repo.Analyzers)
+	if errors.Is(err, analyzer.ErrHeadMoved) {
+		h.log.Info("Static analysis skipped, merge request has a newer commit", "review_id", review.ID, "reason", err)
+		return nil
+	}
+	if err != nil {
+		h.log.Error("Static analysis failed", "error", err, "review_id", review.ID)
+	}
+
+	reported := len(findings)
+	findings = analyzer.OnLines(findings, gitlab.AddedLines(diff))
+	if len(findings) > maxAnalyzerFindings {
+		findings = findings[:maxAnalyzerFindings]
+	}
+	for i := range findings {
+		findings[i].Message = redactor.RedactText(findings[i].Message, findings[i].FilePath)
+	}
+	h.log.Info("Static analysis completed", "review_id", review.ID,
+		"analyzers", repo.Analyzers, "reported", reported, "on_changed_lines", len(findings))
+	return findings
+}
+
+// analyzerPromptFindings lists analyzer findings for the review prompt, one per line
+func analyzerPromptFindings(findings []analyzer.Finding) string {
+	var sb strings.Builder
+	for _, f := range findings {
+		sb.WriteString(fmt.Sprintf("- %s:%d [%s] %s\n", f.FilePath, f.Line, analyzerLabel(f), f.Message))
+	}
+	return sb.String()
+}
+
+// appendAnalyzerFindings reports analyzer findings as suggestions next to the LLM's
+func appendAnalyzerFindings(resp *llm.ReviewResponse, findings []analyzer.Finding) {
+	for _, f := range findings {
+		resp.Suggestions = append(resp.Suggestions, llm.FixSuggestion{
+			FilePath:    f.FilePath,
+			LineStart:   f.Line,
+			LineEnd:     f.Line,
+			Severity:    f.Severity,
+			Category:    f.Category,
+			Description: fmt.Sprintf("[%s] %s", analyzerLabel(f), f.Message),
+			Source:      model.SuggestionSourceAnalyzer,
+		})
+	}
+}
+
+// analyzerLabel names the tool and rule of a finding, e.g. "golangci-lint/errcheck"
+func analyzerLabel(f analyzer.Finding) string {
+	var tool string
+	switch f.Analyzer {
+	case analyzer.GoVet:
+		tool = "go vet"
+	case analyzer.GolangciLint:
+		tool = "golangci-lint"
+	default:
+		tool = f.Analyzer
+	}
+	if f.Rule == "" {
+		return tool
+	}
+	return tool + "/" + f.Rule
+}
@@ -0,0 +1,40 @@
+package task
+
+import (
+	"time"
+
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/service"
+)
+
+// checkBudgets evaluates the budgets that apply to the review's repository.
+// Crossed soft limits are reported once per month; the first budget whose hard limit
+// is reached is returned. Budget lookup failures do not block the review.
+func (h *ReviewHandler) checkBudgets(review *model.ReviewResult) *service.BudgetStatus {
+	budgetSvc := service.NewBudgetService(h.db)
+
+	statuses, err := budgetSvc.RepositoryStatus(review.Repository.ProjectID, review.RepositoryID, time.Now())
+	if err != nil {
+		h.log.Error("Failed to check budgets", "error", err, "review_id", review.ID)
+		return nil
+	}
+
+	var exceeded *service.BudgetStatus
+	for i := range statuses {
+		status := &statuses[i]
+		switch status.State {
+		case model.BudgetStateExceeded:
+			if exceeded == nil {
+				exceeded = status
+			}
+		case model.BudgetStateWarning:
+			if first, err := budgetSvc.MarkWarned(status); err == nil && first {
+				h.log.Info("Budget soft limit crossed",
+					"budget_id", status.ID,
+					"project_id", status.ProjectID,
+					"budget", status.Describe())
+			}
+		}
+	}
+	return exceeded
+}
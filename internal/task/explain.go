@@ -0,0 +1,87 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/service"
+	"github.com/hibiken/asynq"
+)
+
+// HandleExplainSuggestion answers "/handsoff explain <n>": it asks the review's LLM
+// provider to explain finding #n in depth and replies in the comment's thread
+func (h *ReviewHandler) HandleExplainSuggestion(ctx context.Context, t *asynq.Task) error {
+	var payload ExplainSuggestionPayload
+	if err := payload.FromJSON(t.Payload()); err != nil {
+		h.log.Error("Failed to unmarshal task payload", "error", err)
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	review, client, err := h.loadThreadContext(payload.ReviewResultID)
+	if err != nil {
+		return err
+	}
+	projectID, mrIID := int(review.Repository.PlatformRepoID), int(review.MergeRequestID)
+	reply := func(body string) error {
+		return client.ReplyToDiscussionContext(ctx, projectID, mrIID, payload.DiscussionID, body)
+	}
+
+	suggestion, err := service.NewFeedbackService(h.db).SuggestionByNumber(review.ID, payload.Number)
+	if errors.Is(err, service.ErrSuggestionNotFound) {
+		return reply(fmt.Sprintf("@%s the latest review has no finding #%d.", payload.Username, payload.Number))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load finding: %w", err)
+	}
+
+	provider := review.LLMProvider
+	if provider == nil || !provider.IsActive {
+		return reply(fmt.Sprintf("@%s no active LLM provider is configured for this repository.", payload.Username))
+	}
+
+	diff, err := client.GetMRDiffContext(ctx, projectID, mrIID)
+	if err != nil {
+		h.log.Error("Failed to get MR diff", "error", err, "review_id", review.ID)
+		return fmt.Errorf("failed to get MR diff: %w", err)
+	}
+	if diff, _, err = h.redactDiff(review, diff); err != nil {
+		return err
+	}
+
+	prompt := llm.BuildExplanationPrompt(diff, suggestionFinding(suggestion))
+	explanation, err := h.askLLM(ctx, review, provider, diff, prompt, model.UsageTypeExplanation)
+	if errors.Is(err, errBudgetExceeded) {
+		h.log.Info("Skipping explanation: budget exceeded", "review_id", review.ID, "error", err)
+		return reply(fmt.Sprintf("@%s the LLM budget of this repository is exceeded, finding #%d cannot be explained now.",
+			payload.Username, payload.Number))
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := reply(fmt.Sprintf("@%s here is more on finding #%d (`%s`):\n\n%s",
+		payload.Username, payload.Number, suggestion.FilePath, explanation)); err != nil {
+		h.log.Error("Failed to reply with explanation", "error", err, "review_id", review.ID)
+		return fmt.Errorf("failed to reply: %w", err)
+	}
+
+	h.log.Info("Finding explained", "review_id", review.ID, "number", payload.Number, "user", payload.Username)
+	return nil
+}
+
+// suggestionFinding converts a stored suggestion back to the LLM's finding type
+func suggestionFinding(s *model.FixSuggestion) llm.FixSuggestion {
+	return llm.FixSuggestion{
+		FilePath:    s.FilePath,
+		LineStart:   s.LineStart,
+		LineEnd:     s.LineEnd,
+		Severity:    s.Severity,
+		Category:    s.Category,
+		Description: s.Description,
+		Suggestion:  s.Suggestion,
+		CodeSnippet: s.CodeSnippet,
+	}
+}
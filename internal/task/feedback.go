@@ -0,0 +1,41 @@
+package task
+
+import (
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/service"
+)
+
+// stampSuggestions records which model and prompt produced a pass's findings,
+// for precision statistics
+func stampSuggestions(resp *llm.ReviewResponse, modelName, template string) {
+	version := llm.PromptVersion(template)
+	for i := range resp.Suggestions {
+		resp.Suggestions[i].ModelName = modelName
+		resp.Suggestions[i].PromptVersion = version
+	}
+}
+
+// suppressDismissed drops findings developers marked as won't fix or false positive
+// on earlier reviews of the same merge request (best-effort)
+func (h *ReviewHandler) suppressDismissed(review *model.ReviewResult, resp *llm.ReviewResponse) {
+	dismissed, err := service.NewFeedbackService(h.db).DismissedFingerprints(review.ID)
+	if err != nil {
+		h.log.Error("Failed to load dismissed findings", "error", err, "review_id", review.ID)
+		return
+	}
+	if len(dismissed) == 0 {
+		return
+	}
+
+	kept := resp.Suggestions[:0]
+	for _, sug := range resp.Suggestions {
+		if !dismissed[llm.Fingerprint(sug)] {
+			kept = append(kept, sug)
+		}
+	}
+	if suppressed := len(resp.Suggestions) - len(kept); suppressed > 0 {
+		h.log.Info("Suppressed dismissed findings", "review_id", review.ID, "suppressed", suppressed)
+	}
+	resp.Suggestions = kept
+}
@@ -0,0 +1,201 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/handsoff/handsoff/internal/gitlab"
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/service"
+	"github.com/handsoff/handsoff/pkg/crypto"
+	"github.com/hibiken/asynq"
+)
+
+// HandleFollowUpReply answers a developer who replied in a thread HandsOff started,
+// with the conversation so far, the finding it is about and its diff hunk.
+// Threads started by someone else, and replies already followed by newer notes,
+// are left alone.
+func (h *ReviewHandler) HandleFollowUpReply(ctx context.Context, t *asynq.Task) error {
+	var payload FollowUpReplyPayload
+	if err := payload.FromJSON(t.Payload()); err != nil {
+		h.log.Error("Failed to unmarshal task payload", "error", err)
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	review, client, err := h.loadThreadContext(payload.ReviewResultID)
+	if err != nil {
+		return err
+	}
+	projectID, mrIID := int(review.Repository.PlatformRepoID), int(review.MergeRequestID)
+
+	bot, err := client.GetCurrentUserContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get bot user: %w", err)
+	}
+	discussion, err := client.GetMRDiscussionContext(ctx, projectID, mrIID, payload.DiscussionID)
+	if err != nil {
+		return fmt.Errorf("failed to get discussion: %w", err)
+	}
+
+	turns := conversationTurns(discussion, bot.ID)
+	switch {
+	case len(turns) == 0 || !turns[0].Reviewer:
+		h.log.Info("Skipping follow-up: thread not started by HandsOff", "review_id", review.ID)
+		return nil
+	case turns[len(turns)-1].Reviewer:
+		return nil // Already answered, or the bot's own note
+	case lastUserNoteID(discussion) != payload.NoteID:
+		h.log.Info("Skipping follow-up: newer replies in the thread", "review_id", review.ID)
+		return nil
+	}
+
+	provider := review.LLMProvider
+	if provider == nil || !provider.IsActive {
+		h.log.Info("Skipping follow-up: no active LLM provider", "review_id", review.ID)
+		return nil
+	}
+
+	var finding *llm.FixSuggestion
+	var diff string
+	if suggestion := h.discussedSuggestion(review, turns); suggestion != nil {
+		f := suggestionFinding(suggestion)
+		finding = &f
+		diff, err = client.GetMRDiffContext(ctx, projectID, mrIID)
+		if err != nil {
+			return fmt.Errorf("failed to get MR diff: %w", err)
+		}
+		if diff, _, err = h.redactDiff(review, diff); err != nil {
+			return err
+		}
+	}
+
+	answer, err := h.askLLM(ctx, review, provider, diff, llm.BuildFollowUpPrompt(diff, turns, finding), model.UsageTypeFollowUp)
+	if errors.Is(err, errBudgetExceeded) {
+		h.log.Info("Skipping follow-up: budget exceeded", "review_id", review.ID, "error", err)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := client.ReplyToDiscussionContext(ctx, projectID, mrIID, payload.DiscussionID, answer); err != nil {
+		h.log.Error("Failed to post follow-up reply", "error", err, "review_id", review.ID)
+		return fmt.Errorf("failed to reply: %w", err)
+	}
+
+	h.log.Info("Follow-up reply posted", "review_id", review.ID, "discussion_id", payload.DiscussionID)
+	return nil
+}
+
+// conversationTurns returns the thread's user-written notes, oldest first
+func conversationTurns(discussion *gitlab.Discussion, botID int64) []llm.ConversationTurn {
+	var turns []llm.ConversationTurn
+	for _, note := range discussion.Notes {
+		if note.System {
+			continue
+		}
+		turns = append(turns, llm.ConversationTurn{
+			Author:   note.Author.Username,
+			Reviewer: note.Author.ID == botID,
+			Body:     note.Body,
+		})
+	}
+	return turns
+}
+
+func lastUserNoteID(discussion *gitlab.Discussion) int64 {
+	for i := len(discussion.Notes) - 1; i >= 0; i-- {
+		if !discussion.Notes[i].System {
+			return discussion.Notes[i].ID
+		}
+	}
+	return 0
+}
+
+// discussedSuggestion returns the finding the developer refers to (#N, latest
+// mention wins), or the review's only finding; nil when it cannot be told
+func (h *ReviewHandler) discussedSuggestion(review *model.ReviewResult, turns []llm.ConversationTurn) *model.FixSuggestion {
+	feedbackSvc := service.NewFeedbackService(h.db)
+	for i := len(turns) - 1; i >= 0; i-- {
+		if turns[i].Reviewer {
+			continue
+		}
+		if n := llm.FindingReference(turns[i].Body); n > 0 {
+			if suggestion, err := feedbackSvc.SuggestionByNumber(review.ID, n); err == nil {
+				return suggestion
+			}
+		}
+	}
+
+	var numbered []model.FixSuggestion
+	h.db.Where("review_result_id = ? AND number > 0", review.ID).Limit(2).Find(&numbered)
+	if len(numbered) == 1 {
+		return &numbered[0]
+	}
+	return nil
+}
+
+// loadThreadContext loads a review with what answering in its MR threads needs:
+// the repository's platform and the review's LLM provider
+func (h *ReviewHandler) loadThreadContext(reviewID uint) (*model.ReviewResult, *gitlab.Client, error) {
+	var review model.ReviewResult
+	err := h.db.
+		Preload("Repository.Platform").
+		Preload("LLMProvider").
+		First(&review, reviewID).Error
+	if err != nil {
+		h.log.Error("Failed to load review result", "error", err, "review_id", reviewID)
+		return nil, nil, fmt.Errorf("review result not found: %w", err)
+	}
+
+	accessToken, err := crypto.DecryptString(review.Repository.Platform.AccessToken, h.encryptionKey)
+	if err != nil {
+		h.log.Error("Failed to decrypt platform access token", "error", err, "review_id", review.ID)
+		return nil, nil, fmt.Errorf("failed to decrypt access token: %w", err)
+	}
+	return &review, gitlab.NewClient(review.Repository.Platform.BaseURL, accessToken), nil
+}
+
+// errBudgetExceeded is returned by askLLM when a budget of the review's repository is used up
+var errBudgetExceeded = errors.New("budget exceeded")
+
+// askLLM sends a free-form prompt to the provider and returns its Markdown answer,
+// logging the call's usage under requestType. Nothing is sent while a budget is exceeded.
+func (h *ReviewHandler) askLLM(ctx context.Context, review *model.ReviewResult, provider *model.LLMProvider, diff, prompt string, requestType model.UsageRequestType) (string, error) {
+	if exceeded := h.checkBudgets(review); exceeded != nil {
+		return "", fmt.Errorf("%w: %s", errBudgetExceeded, exceeded.Describe())
+	}
+
+	llmClient, err := llm.GetOrCreateClient(provider, h.encryptionKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to get LLM client: %w", err)
+	}
+
+	req := llm.ReviewRequest{
+		Diff:        diff,
+		Prompt:      prompt,
+		MaxTokens:   2048,
+		Temperature: 0.3,
+		ModelName:   provider.Model,
+	}
+	estimatedTokens := llm.EstimateTokens(req)
+	if err := h.waitForRateLimit(ctx, provider, estimatedTokens); err != nil {
+		return "", err
+	}
+
+	resp, err := llmClient.ReviewContext(ctx, req)
+	if err != nil {
+		var rateErr *llm.RateLimitError
+		if errors.As(err, &rateErr) {
+			h.pauseProvider(provider, rateErr)
+		}
+		h.logUsage(review, provider, requestType, nil, err)
+		h.log.Error("LLM call failed", "error", err, "review_id", review.ID, "request_type", requestType)
+		return "", fmt.Errorf("LLM call failed: %w", err)
+	}
+	h.chargeRateLimit(provider, resp.TokenUsage.TotalTokens-estimatedTokens)
+	h.logUsage(review, provider, requestType, resp, nil)
+
+	return resp.RawResponse, nil
+}
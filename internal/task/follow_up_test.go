@@ -0,0 +1,57 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/handsoff/handsoff/internal/gitlab"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/pkg/logger"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestConversationTurns(t *testing.T) {
+	bot := gitlab.User{ID: 1, Username: "handsoff"}
+	alice := gitlab.User{ID: 2, Username: "alice"}
+	discussion := &gitlab.Discussion{Notes: []gitlab.Note{
+		{ID: 10, Author: bot, Body: "## AI Code Review"},
+		{ID: 11, Author: alice, Body: "Why is #1 a problem?"},
+		{ID: 12, Author: alice, Body: "changed this line in version 2", System: true},
+	}}
+
+	turns := conversationTurns(discussion, bot.ID)
+	if len(turns) != 2 {
+		t.Fatalf("conversationTurns() returned %d turns, want 2 (system notes left out)", len(turns))
+	}
+	if !turns[0].Reviewer || turns[1].Reviewer || turns[1].Author != "alice" {
+		t.Errorf("conversationTurns() = %+v, want the bot's note then alice's", turns)
+	}
+	if got := lastUserNoteID(discussion); got != 11 {
+		t.Errorf("lastUserNoteID() = %d, want 11", got)
+	}
+}
+
+// TestAskLLMBudgetExceeded checks follow-ups, explanations and MR summaries spend
+// nothing once a budget of the repository is used up
+func TestAskLLMBudgetExceeded(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&model.Budget{}, &model.LLMUsageLog{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	db.Create(&model.Budget{ProjectID: 1, Metric: model.BudgetMetricTokens, HardLimit: 100, IsActive: true})
+	db.Create(&model.LLMUsageLog{ProjectID: 1, RepositoryID: 1, TotalTokens: 150})
+
+	h := &ReviewHandler{db: db, log: logger.New("error", "console")}
+	review := &model.ReviewResult{RepositoryID: 1, Repository: &model.Repository{ProjectID: 1}}
+	provider := &model.LLMProvider{Model: "test"}
+
+	_, err = h.askLLM(context.Background(), review, provider, "", "Why?", model.UsageTypeFollowUp)
+	if !errors.Is(err, errBudgetExceeded) {
+		t.Errorf("askLLM() error = %v, want errBudgetExceeded", err)
+	}
+}
@@ -0,0 +1,29 @@
+package task
+
+import (
+	"context"
+
+	"github.com/handsoff/handsoff/internal/gitlab"
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/service"
+)
+
+// applyLabelRules puts the labels of the repository's matching rules on the MR and
+// takes off those whose conditions cleared. Best-effort: failures are logged.
+func (h *ReviewHandler) applyLabelRules(ctx context.Context, review *model.ReviewResult, client *gitlab.Client, resp *llm.ReviewResponse, diff string) {
+	rules := review.Repository.LabelRules
+	if len(rules) == 0 {
+		return
+	}
+
+	metrics := service.LabelMetrics(resp, gitlab.CountChangedLines(diff))
+	add, remove := service.EvaluateLabelRules(rules, metrics)
+
+	err := client.UpdateMRLabelsContext(ctx, int(review.Repository.PlatformRepoID), int(review.MergeRequestID), add, remove)
+	if err != nil {
+		h.log.Error("Failed to update MR labels", "error", err, "review_id", review.ID)
+		return
+	}
+	h.log.Info("MR labels updated", "review_id", review.ID, "added", add, "removed", remove)
+}
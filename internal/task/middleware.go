@@ -39,6 +39,22 @@ func LoggingMiddleware(log Logger) asynq.MiddlewareFunc {
 	}
 }
 
+// TimeoutMiddleware cancels the task context after the given duration.
+// Handlers pass the context to outgoing HTTP requests, so a stuck call is aborted.
+func TimeoutMiddleware(timeout time.Duration) asynq.MiddlewareFunc {
+	return func(next asynq.Handler) asynq.Handler {
+		return asynq.HandlerFunc(func(ctx context.Context, t *asynq.Task) error {
+			if timeout <= 0 {
+				return next.ProcessTask(ctx, t)
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return next.ProcessTask(ctx, t)
+		})
+	}
+}
+
 // RecoveryMiddleware recovers from panics
 func RecoveryMiddleware(log Logger) asynq.MiddlewareFunc {
 	return func(next asynq.Handler) asynq.Handler {
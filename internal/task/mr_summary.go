@@ -0,0 +1,122 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/handsoff/handsoff/internal/gitlab"
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/hibiken/asynq"
+)
+
+// HandleMRSummary generates a summary of a merge request from its diff and commits,
+// once per merge request. Depending on the repository's mode it is written into an
+// empty MR description or posted as a comment.
+func (h *ReviewHandler) HandleMRSummary(ctx context.Context, t *asynq.Task) error {
+	var payload MRSummaryPayload
+	if err := payload.FromJSON(t.Payload()); err != nil {
+		h.log.Error("Failed to unmarshal task payload", "error", err)
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	review, client, err := h.loadThreadContext(payload.ReviewResultID)
+	if err != nil {
+		return err
+	}
+	mode := review.Repository.MRSummaryMode
+	provider := review.LLMProvider
+	switch {
+	case review.MRSummaryAt != nil:
+		return nil // Already summarized
+	case mode == model.MRSummaryOff:
+		h.log.Info("Skipping MR summary: disabled for repository", "review_id", review.ID)
+		return nil
+	case provider == nil || !provider.IsActive:
+		h.log.Info("Skipping MR summary: no active LLM provider", "review_id", review.ID)
+		return nil
+	}
+
+	projectID, mrIID := int(review.Repository.PlatformRepoID), int(review.MergeRequestID)
+	mr, err := client.GetMergeRequestContext(ctx, projectID, mrIID)
+	if err != nil {
+		return fmt.Errorf("failed to get merge request: %w", err)
+	}
+	if mr.State != "opened" {
+		h.log.Info("Skipping MR summary: merge request not open", "review_id", review.ID, "state", mr.State)
+		return nil
+	}
+	commits, err := client.GetMRCommitsContext(ctx, projectID, mrIID)
+	if err != nil {
+		return fmt.Errorf("failed to get MR commits: %w", err)
+	}
+	diff, err := client.GetMRDiffContext(ctx, projectID, mrIID)
+	if err != nil {
+		return fmt.Errorf("failed to get MR diff: %w", err)
+	}
+	rules := review.Repository.EffectiveTriggerRules()
+	if changed := gitlab.CountChangedLines(diff); rules.MaxChangedLines > 0 && changed > rules.MaxChangedLines {
+		h.log.Info("Skipping MR summary: too many changed lines", "review_id", review.ID,
+			"changed", changed, "limit", rules.MaxChangedLines)
+		return nil
+	}
+	if diff, _, err = h.redactDiff(review, diff); err != nil {
+		return err
+	}
+
+	prompt := llm.RenderPrompt(h.systemConfigSvc.GetMRSummaryPrompt(review.Repository.ProjectID), llm.PromptData{
+		Diff:          diff,
+		MRTitle:       mr.Title,
+		MRAuthor:      review.MRAuthor,
+		SourceBranch:  review.SourceBranch,
+		TargetBranch:  review.TargetBranch,
+		CommitMessage: llm.FormatCommitLog(summaryCommits(commits)),
+	})
+	summary, err := h.askLLM(ctx, review, provider, diff, prompt, model.UsageTypeMRSummary)
+	if errors.Is(err, errBudgetExceeded) {
+		h.log.Info("Skipping MR summary: budget exceeded", "review_id", review.ID, "error", err)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	body := gitlab.FormatMRSummary(summary, provider.Model)
+	target := model.MRSummaryComment
+	if mode == model.MRSummaryDescription && strings.TrimSpace(mr.Description) == "" {
+		// Read it again: the author may have written a description while the LLM was answering
+		latest, err := client.GetMergeRequestContext(ctx, projectID, mrIID)
+		if err != nil {
+			return fmt.Errorf("failed to get merge request: %w", err)
+		}
+		if strings.TrimSpace(latest.Description) == "" {
+			target = model.MRSummaryDescription
+		}
+	}
+	if target == model.MRSummaryDescription {
+		err = client.UpdateMRDescriptionContext(ctx, projectID, mrIID, body)
+	} else {
+		err = client.PostMRCommentContext(ctx, projectID, mrIID, body)
+	}
+	if err != nil {
+		h.log.Error("Failed to post MR summary", "error", err, "review_id", review.ID, "target", target)
+		return fmt.Errorf("failed to post MR summary: %w", err)
+	}
+
+	if err := h.db.Model(&model.ReviewResult{}).Where("id = ?", review.ID).Update("mr_summary_at", time.Now()).Error; err != nil {
+		h.log.Error("Failed to record MR summary", "error", err, "review_id", review.ID)
+	}
+	h.log.Info("MR summary posted", "review_id", review.ID, "target", target)
+	return nil
+}
+
+func summaryCommits(commits []gitlab.Commit) []llm.SummaryCommit {
+	summary := make([]llm.SummaryCommit, len(commits))
+	for i, c := range commits {
+		summary[i] = llm.SummaryCommit{ShortID: c.ShortID, Title: c.Title}
+	}
+	return summary
+}
@@ -0,0 +1,77 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/handsoff/handsoff/internal/gitlab"
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/service"
+	"github.com/hibiken/asynq"
+)
+
+// gateEnabled reports whether the review's outcome is reported as a commit status
+func gateEnabled(review *model.ReviewResult) bool {
+	return review.Repository.EffectiveQualityGate().Enabled && review.HeadSHA != ""
+}
+
+// startQualityGate marks the head commit pending while the review runs and clears
+// the outcome of the previous commit
+func (h *ReviewHandler) startQualityGate(ctx context.Context, review *model.ReviewResult, client *gitlab.Client) {
+	if err := service.NewQualityGateService(h.db).SetResult(review.ID, "", ""); err != nil {
+		h.log.Error("Failed to clear quality gate result", "error", err, "review_id", review.ID)
+	}
+	h.setCommitStatus(ctx, review, client, gitlab.CommitStatusPending, "AI review in progress")
+}
+
+// abortQualityGate closes the pending status of a review that stopped with err.
+// A review the queue retries (throttled, or failed with retries left) stays pending,
+// so a transient error does not block merges until the next attempt.
+func (h *ReviewHandler) abortQualityGate(ctx context.Context, review *model.ReviewResult, client *gitlab.Client, err error) {
+	var rateErr *llm.RateLimitError
+	switch {
+	case err == nil, errors.As(err, &rateErr):
+	case errors.Is(err, errReviewSuperseded):
+		h.setCommitStatus(ctx, review, client, gitlab.CommitStatusCanceled, "Superseded by a newer commit")
+	case finalAttempt(ctx) || errors.Is(err, asynq.SkipRetry):
+		h.setCommitStatus(ctx, review, client, gitlab.CommitStatusFailed, "AI review failed: "+err.Error())
+	}
+}
+
+// applyQualityGate checks the review against the repository's quality gate and
+// reports the outcome as the head commit's status
+func (h *ReviewHandler) applyQualityGate(ctx context.Context, review *model.ReviewResult, client *gitlab.Client, resp *llm.ReviewResponse) {
+	failures := service.EvaluateQualityGate(review.Repository.EffectiveQualityGate(), resp)
+
+	status, state := model.GateStatusPassed, gitlab.CommitStatusSuccess
+	description := fmt.Sprintf("Quality gate passed (score %d)", resp.Score)
+	reason := strings.Join(failures, ", ")
+	if len(failures) > 0 {
+		status, state = model.GateStatusFailed, gitlab.CommitStatusFailed
+		description = "Quality gate failed: " + reason
+	}
+
+	if err := service.NewQualityGateService(h.db).SetResult(review.ID, status, reason); err != nil {
+		h.log.Error("Failed to save quality gate result", "error", err, "review_id", review.ID)
+	}
+	h.setCommitStatus(ctx, review, client, state, description)
+
+	h.log.Info("Quality gate evaluated", "review_id", review.ID, "status", status, "reason", reason)
+}
+
+// setCommitStatus sets the HandsOff status on the reviewed commit. Best-effort:
+// a status that cannot be set is logged and does not fail the review.
+func (h *ReviewHandler) setCommitStatus(ctx context.Context, review *model.ReviewResult, client *gitlab.Client, state, description string) {
+	err := client.SetCommitStatusContext(ctx, int(review.Repository.PlatformRepoID), review.HeadSHA, gitlab.CommitStatus{
+		State:       state,
+		Name:        model.QualityGateStatusName,
+		Description: description,
+		TargetURL:   review.CommentURL,
+	})
+	if err != nil {
+		h.log.Error("Failed to set commit status", "error", err, "review_id", review.ID, "state", state)
+	}
+}
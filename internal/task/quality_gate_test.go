@@ -0,0 +1,53 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/handsoff/handsoff/internal/gitlab"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/pkg/logger"
+	"github.com/hibiken/asynq"
+)
+
+// TestAbortQualityGate checks a failed review only fails the commit status when the
+// queue will not retry it
+func TestAbortQualityGate(t *testing.T) {
+	var states []string
+	gitlabServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct{ State string }
+		json.NewDecoder(r.Body).Decode(&body)
+		states = append(states, body.State)
+		w.Write([]byte("{}"))
+	}))
+	defer gitlabServer.Close()
+
+	h := &ReviewHandler{log: logger.New("error", "console")}
+	client := gitlab.NewClient(gitlabServer.URL, "token")
+	review := &model.ReviewResult{Repository: &model.Repository{PlatformRepoID: 10}, HeadSHA: "0123456789abcdef"}
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStates []string
+	}{
+		{"retried", errors.New("failed to post comment"), nil},
+		{"not retried", fmt.Errorf("invalid response: %w", asynq.SkipRetry), []string{gitlab.CommitStatusFailed}},
+		{"superseded", errReviewSuperseded, []string{gitlab.CommitStatusCanceled}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			states = nil
+			h.abortQualityGate(context.Background(), review, client, tt.err)
+			if !reflect.DeepEqual(states, tt.wantStates) {
+				t.Errorf("commit statuses = %v, want %v", states, tt.wantStates)
+			}
+		})
+	}
+}
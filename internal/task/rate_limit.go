@@ -0,0 +1,129 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/hibiken/asynq"
+)
+
+const (
+	// maxInlineRateLimitWait is the longest a worker sleeps for rate limit capacity;
+	// longer waits hand the task back to the queue so the worker slot is freed
+	maxInlineRateLimitWait = 15 * time.Second
+
+	// defaultRateLimitBackoff applies when a provider returns 429 without Retry-After
+	defaultRateLimitBackoff = 30 * time.Second
+
+	// rateLimitStateTimeout bounds limiter bookkeeping that must not delay the review
+	rateLimitStateTimeout = 2 * time.Second
+)
+
+// waitForRateLimit reserves one request and the estimated tokens from the provider's budget.
+// Short waits are slept through; longer ones return a *llm.RateLimitError.
+// Limiter failures are logged and do not block the review.
+func (h *ReviewHandler) waitForRateLimit(ctx context.Context, provider *model.LLMProvider, tokens int) error {
+	if h.limiter == nil {
+		return nil
+	}
+
+	key := llm.ProviderRateLimitKey(provider)
+	limits := llm.ProviderLimits(provider)
+	for {
+		wait, err := h.limiter.Reserve(ctx, key, limits, tokens)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			h.log.Error("Rate limiter unavailable, continuing without it", "error", err, "provider_id", provider.ID)
+			return nil
+		}
+		if wait == 0 {
+			return nil
+		}
+
+		if wait > maxInlineRateLimitWait {
+			h.log.Info("Provider rate limit reached, requeueing task",
+				"provider_id", provider.ID,
+				"retry_after", wait)
+			return &llm.RateLimitError{
+				Provider:   provider.Name,
+				RetryAfter: wait,
+				Message:    "provider rate limit budget exhausted",
+				Local:      true,
+			}
+		}
+
+		h.log.Info("Waiting for provider rate limit", "provider_id", provider.ID, "wait", wait)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// chargeRateLimit corrects the provider's token bucket by the difference between
+// actual and estimated usage (negative returns unused tokens)
+func (h *ReviewHandler) chargeRateLimit(provider *model.LLMProvider, tokens int) {
+	if h.limiter == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rateLimitStateTimeout)
+	defer cancel()
+	if err := h.limiter.Charge(ctx, llm.ProviderRateLimitKey(provider), llm.ProviderLimits(provider), tokens); err != nil {
+		h.log.Error("Failed to update provider token usage", "error", err, "provider_id", provider.ID)
+	}
+}
+
+// pauseProvider holds back all workers after the provider answered 429
+func (h *ReviewHandler) pauseProvider(provider *model.LLMProvider, rateErr *llm.RateLimitError) {
+	if rateErr.RetryAfter <= 0 {
+		rateErr.RetryAfter = defaultRateLimitBackoff
+	}
+	if h.limiter == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rateLimitStateTimeout)
+	defer cancel()
+	if err := h.limiter.Pause(ctx, llm.ProviderRateLimitKey(provider), rateErr.RetryAfter); err != nil {
+		h.log.Error("Failed to pause rate limited provider", "error", err, "provider_id", provider.ID)
+	}
+}
+
+// deferReview puts a throttled review back to pending until the queue retries it
+func (h *ReviewHandler) deferReview(review *model.ReviewResult, cause error) {
+	err := h.db.Model(&model.ReviewResult{}).Where("id = ?", review.ID).Updates(map[string]interface{}{
+		"status":        model.ReviewStatusPending,
+		"error_message": cause.Error(),
+	}).Error
+	if err != nil {
+		h.log.Error("Failed to mark review as pending", "error", err, "review_id", review.ID)
+	}
+}
+
+// isRateLimited reports whether the task failed because the provider was throttled
+func isRateLimited(err error) bool {
+	var rateErr *llm.RateLimitError
+	return errors.As(err, &rateErr)
+}
+
+// retryDelay retries throttled tasks once the provider has capacity again (with jitter,
+// so queued reviews do not all fire at once); other failures use asynq's backoff
+func retryDelay(n int, err error, t *asynq.Task) time.Duration {
+	var rateErr *llm.RateLimitError
+	if errors.As(err, &rateErr) {
+		d := rateErr.RetryAfter
+		if d <= 0 {
+			d = defaultRateLimitBackoff
+		}
+		return d + time.Duration(rand.Int63n(int64(d)/10+1))
+	}
+	return asynq.DefaultRetryDelayFunc(n, err, t)
+}
@@ -0,0 +1,45 @@
+package task
+
+import (
+	"fmt"
+
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/redact"
+	"github.com/handsoff/handsoff/internal/service"
+)
+
+// redactDiff replaces secrets in the diff so they never reach the LLM provider.
+// If the project's rules cannot be loaded nothing is sent: the builtin rules
+// alone would let secrets of the custom rules through.
+func (h *ReviewHandler) redactDiff(review *model.ReviewResult, diff string) (string, []redact.Finding, error) {
+	redactor, err := service.NewRedactionService(h.db).Redactor(review.Repository.ProjectID)
+	if err != nil {
+		h.log.Error("Failed to load redaction rules", "error", err, "review_id", review.ID)
+		return "", nil, fmt.Errorf("failed to load redaction rules: %w", err)
+	}
+
+	redacted, findings := redactor.Redact(diff)
+	if len(findings) > 0 {
+		h.log.Info("Redacted secrets from diff", "review_id", review.ID, "findings", len(findings))
+	}
+	return redacted, findings, nil
+}
+
+// appendSecretFindings reports redacted secrets as high-severity security suggestions.
+// Snippets show the placeholder, never the secret.
+func appendSecretFindings(resp *llm.ReviewResponse, findings []redact.Finding) {
+	for _, f := range findings {
+		resp.Suggestions = append(resp.Suggestions, llm.FixSuggestion{
+			FilePath:    f.FilePath,
+			LineStart:   f.Line,
+			LineEnd:     f.Line,
+			Severity:    "high",
+			Category:    "security",
+			Description: fmt.Sprintf("Possible %s committed in this change (redacted before review as %s)", f.Label, f.Placeholder),
+			Suggestion:  "Remove the secret from the change, rotate it, and load it from the environment or a secret manager instead.",
+			CodeSnippet: f.Snippet,
+			Source:      model.SuggestionSourceBuiltin,
+		})
+	}
+}
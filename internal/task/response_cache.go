@@ -0,0 +1,49 @@
+package task
+
+import (
+	"time"
+
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/service"
+)
+
+// responseCacheEnabled reports whether the review may reuse a cached LLM response
+func (h *ReviewHandler) responseCacheEnabled(review *model.ReviewResult) bool {
+	return h.cacheTTL > 0 && !review.Repository.ResponseCacheDisabled
+}
+
+// cachedResponse returns the cached response for key, or nil on a miss.
+// No tokens are spent for it, so its token usage is cleared.
+// Cache failures are logged and fall back to calling the LLM.
+func (h *ReviewHandler) cachedResponse(review *model.ReviewResult, key string) *llm.ReviewResponse {
+	resp, err := service.NewResponseCacheService(h.db).Get(key, time.Now())
+	if err != nil {
+		h.log.Error("Failed to read response cache", "error", err, "review_id", review.ID)
+		return nil
+	}
+	if resp == nil {
+		return nil
+	}
+
+	resp.Cached = true
+	resp.TokenUsage = llm.TokenUsage{}
+	resp.TokensUsed = 0
+	resp.Duration = 0
+
+	h.log.Info("Reusing cached LLM response", "review_id", review.ID, "cache_key", key)
+	return resp
+}
+
+// storeResponse caches a fresh LLM response (best-effort)
+func (h *ReviewHandler) storeResponse(review *model.ReviewResult, provider *model.LLMProvider, key string, resp *llm.ReviewResponse) {
+	entry := service.ResponseCacheEntry{
+		Key:           key,
+		ProjectID:     review.Repository.ProjectID,
+		LLMProviderID: provider.ID,
+		ModelName:     provider.Model,
+	}
+	if err := service.NewResponseCacheService(h.db).Put(entry, resp, h.cacheTTL, time.Now()); err != nil {
+		h.log.Error("Failed to store LLM response in cache", "error", err, "review_id", review.ID)
+	}
+}
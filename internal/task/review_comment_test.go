@@ -0,0 +1,150 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/handsoff/handsoff/internal/gitlab"
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/pkg/logger"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestPostCommentToGitLab checks the first review starts a thread, re-reviews edit its
+// note in place (or start a new thread if it was deleted) and the thread is resolved
+// once no findings are left
+func TestPostCommentToGitLab(t *testing.T) {
+	var created, edited int
+	noteExists := true
+	var resolved []bool
+	gitlabServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v4/projects/10/merge_requests/5/discussions":
+			created++
+			noteExists = true
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id":    "d1",
+				"notes": []map[string]interface{}{{"id": 100 + created}},
+			})
+		case r.Method == http.MethodPut && r.URL.Path == "/api/v4/projects/10/merge_requests/5/notes/101" && noteExists:
+			edited++
+			json.NewEncoder(w).Encode(map[string]int{"id": 101})
+		case r.Method == http.MethodPut && r.URL.Path == "/api/v4/projects/10/merge_requests/5/discussions/d1":
+			var body struct{ Resolved bool }
+			json.NewDecoder(r.Body).Decode(&body)
+			resolved = append(resolved, body.Resolved)
+			w.Write([]byte("{}"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer gitlabServer.Close()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ReviewResult{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	review := model.ReviewResult{
+		RepositoryID:   1,
+		Repository:     &model.Repository{PlatformRepoID: 10},
+		MergeRequestID: 5,
+		MRWebURL:       "https://gitlab.example.com/g/r/-/merge_requests/5",
+		HeadSHA:        "0123456789abcdef",
+	}
+	if err := db.Omit("Repository").Create(&review).Error; err != nil {
+		t.Fatalf("Failed to create review: %v", err)
+	}
+
+	h := &ReviewHandler{db: db, log: logger.New("error", "console")}
+	client := gitlab.NewClient(gitlabServer.URL, "token")
+	withFinding := &llm.ReviewResponse{Score: 70, Suggestions: []llm.FixSuggestion{{FilePath: "a.go", Severity: "high", Description: "x"}}}
+	clean := &llm.ReviewResponse{Score: 95}
+
+	steps := []struct {
+		name        string
+		resp        *llm.ReviewResponse
+		deleteNote  bool
+		wantCreated int
+		wantEdited  int
+		wantNoteID  int64
+		wantResolve bool
+	}{
+		{"first review starts a thread", withFinding, false, 1, 0, 101, false},
+		{"re-review edits the note", clean, false, 1, 1, 101, true},
+		{"deleted note gets a new thread", withFinding, true, 2, 1, 102, false},
+	}
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			if step.deleteNote {
+				noteExists = false
+			}
+			if err := h.postCommentToGitLab(context.Background(), &review, client, step.resp); err != nil {
+				t.Fatalf("postCommentToGitLab() error = %v", err)
+			}
+			if created != step.wantCreated || edited != step.wantEdited {
+				t.Errorf("created %d, edited %d notes, want %d, %d", created, edited, step.wantCreated, step.wantEdited)
+			}
+			if got := resolved[len(resolved)-1]; got != step.wantResolve {
+				t.Errorf("thread resolved = %v, want %v", got, step.wantResolve)
+			}
+
+			var stored model.ReviewResult
+			db.First(&stored, review.ID)
+			if stored.CommentNoteID != step.wantNoteID || stored.CommentDiscussionID != "d1" || !stored.CommentPosted {
+				t.Errorf("stored comment = note %d, discussion %q, posted %v", stored.CommentNoteID, stored.CommentDiscussionID, stored.CommentPosted)
+			}
+			if stored.CommentURL == "" {
+				t.Error("comment URL not set")
+			}
+		})
+	}
+}
+
+// TestCollapseOutdatedReviews checks starting a review thread marks the bot's older
+// review comments outdated and leaves every other note alone
+func TestCollapseOutdatedReviews(t *testing.T) {
+	review := "## 🤖 AI Code Review\n\nLooks good"
+	var updated []int64
+	gitlabServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/user":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 9, "username": "handsoff-bot"})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/10/merge_requests/5/notes":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"id": 1, "body": review, "author": map[string]interface{}{"id": 9}},                                     // Legacy comment
+				{"id": 2, "body": review, "author": map[string]interface{}{"id": 3}},                                     // Quoted by a developer
+				{"id": 3, "body": "@alice finding #1 ...", "author": map[string]interface{}{"id": 9}},                    // Bot reply
+				{"id": 4, "body": gitlab.FormatOutdatedReviewComment(review), "author": map[string]interface{}{"id": 9}}, // Already outdated
+				{"id": 101, "body": review, "author": map[string]interface{}{"id": 9}},                                   // The new thread
+			})
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/api/v4/projects/10/merge_requests/5/notes/"):
+			id, _ := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/api/v4/projects/10/merge_requests/5/notes/"), 10, 64)
+			updated = append(updated, id)
+			w.Write([]byte("{}"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer gitlabServer.Close()
+
+	h := &ReviewHandler{log: logger.New("error", "console")}
+	current := &gitlab.Discussion{ID: "d1", Notes: []gitlab.Note{{ID: 101}}}
+	h.collapseOutdatedReviews(context.Background(), &model.ReviewResult{Repository: &model.Repository{PlatformRepoID: 10}, MergeRequestID: 5},
+		gitlab.NewClient(gitlabServer.URL, "token"), current)
+
+	if !reflect.DeepEqual(updated, []int64{1}) {
+		t.Errorf("updated notes = %v, want [1]", updated)
+	}
+}
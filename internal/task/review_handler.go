@@ -3,13 +3,17 @@ package task
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/handsoff/handsoff/internal/analyzer"
 	"github.com/handsoff/handsoff/internal/gitlab"
 	"github.com/handsoff/handsoff/internal/llm"
 	"github.com/handsoff/handsoff/internal/model"
 	"github.com/handsoff/handsoff/internal/service"
+	"github.com/handsoff/handsoff/pkg/crypto"
+	"github.com/handsoff/handsoff/pkg/ratelimit"
 	"github.com/hibiken/asynq"
 	"gorm.io/gorm"
 )
@@ -20,6 +24,9 @@ type ReviewHandler struct {
 	log             Logger
 	encryptionKey   string
 	systemConfigSvc *service.SystemConfigService
+	limiter         *ratelimit.Limiter // Per-provider rate limits (nil disables them)
+	cacheTTL        time.Duration      // Response cache lifetime (0 disables the cache)
+	analyzers       *analyzer.Runner   // Static analyzers (nil disables them)
 }
 
 // Logger interface for handler logging
@@ -29,59 +36,160 @@ type Logger interface {
 }
 
 // NewReviewHandler creates a new review handler
-func NewReviewHandler(db *gorm.DB, log Logger, encryptionKey string) *ReviewHandler {
+func NewReviewHandler(db *gorm.DB, log Logger, encryptionKey string, limiter *ratelimit.Limiter, cacheTTL time.Duration, analyzers *analyzer.Runner) *ReviewHandler {
 	return &ReviewHandler{
 		db:              db,
 		log:             log,
 		encryptionKey:   encryptionKey,
 		systemConfigSvc: service.NewSystemConfigService(db),
+		limiter:         limiter,
+		cacheTTL:        cacheTTL,
+		analyzers:       analyzers,
 	}
 }
 
+// errReviewSuperseded is returned when a newer commit replaced the review's head while it was in flight
+var errReviewSuperseded = errors.New("review superseded by a newer commit")
+
 // HandleCodeReview processes code review tasks
 // REFACTORED: Split into 6 small functions with single responsibility
 // FIXED: GitLab comment failure now triggers retry (was swallowed before)
-func (h *ReviewHandler) HandleCodeReview(ctx context.Context, t *asynq.Task) error {
+func (h *ReviewHandler) HandleCodeReview(ctx context.Context, t *asynq.Task) (err error) {
 	// Step 1: Parse payload and load review result from DB
 	reviewResult, err := h.loadReviewContext(t)
+	if errors.Is(err, errReviewSuperseded) {
+		return nil // A task for the newer commit owns the review now
+	}
 	if err != nil {
 		return err // Already logged internally
 	}
 
+	// A superseded review is not a failure: stop quietly without retrying.
+	// Registered first so it runs after the webhook event defer below.
+	defer func() {
+		if errors.Is(err, errReviewSuperseded) {
+			h.markReviewSuperseded(reviewResult)
+			err = nil
+		}
+	}()
+
+	// Track the triggering webhook delivery (if any) through processing
+	if reviewResult.WebhookEventID != nil {
+		h.updateWebhookEventStatus(reviewResult, model.EventStatusProcessing, "")
+		defer func() {
+			var rateErr *llm.RateLimitError
+			switch {
+			case errors.Is(err, errReviewSuperseded):
+				h.ignoreWebhookEvent(reviewResult, "superseded by a newer commit")
+			case errors.As(err, &rateErr):
+				h.updateWebhookEventStatus(reviewResult, model.EventStatusPending, err.Error())
+			case err != nil:
+				h.updateWebhookEventStatus(reviewResult, model.EventStatusFailed, err.Error())
+				if finalAttempt(ctx) {
+					h.releaseWebhookCommit(reviewResult)
+				}
+			}
+		}()
+	}
+
+	// Step 1.5: Enforce monthly budgets before spending anything on the LLM
+	if exceeded := h.checkBudgets(reviewResult); exceeded != nil {
+		h.skipReviewWithStatus(reviewResult, model.ReviewStatusBudgetExceeded,
+			"Review skipped, budget exceeded: "+exceeded.Describe())
+		return nil
+	}
+
 	// Step 2: Fetch MR diff from GitLab
-	diff, gitlabClient, err := h.fetchMRDiff(reviewResult)
+	diff, gitlabClient, err := h.fetchMRDiff(ctx, reviewResult)
 	if err != nil {
-		h.markReviewFailed(reviewResult.ID, fmt.Sprintf("Failed to get MR diff: %v", err))
+		if h.isSuperseded(reviewResult) {
+			return errReviewSuperseded // Cancelled in favour of a newer commit
+		}
+		h.markReviewFailed(reviewResult, fmt.Sprintf("Failed to get MR diff: %v", err))
 		return err
 	}
 
-	// Step 3: Perform LLM code review
-	reviewResp, err := h.callLLMReview(reviewResult, diff)
+	// Step 2.5: Enforce the repository's changed-lines cap (webhook-triggered reviews only;
+	// a review requested explicitly always runs)
+	if reviewResult.WebhookEventID != nil {
+		rules := reviewResult.Repository.EffectiveTriggerRules()
+		if changed := gitlab.CountChangedLines(diff); rules.MaxChangedLines > 0 && changed > rules.MaxChangedLines {
+			h.skipReview(reviewResult, fmt.Sprintf("merge request changes %d lines, more than the limit of %d",
+				changed, rules.MaxChangedLines))
+			return nil
+		}
+	}
+
+	if err := h.checkCurrent(ctx, reviewResult); err != nil {
+		return err
+	}
+
+	// Step 2.6: Hold the quality gate's commit status pending until the review is done
+	if gateEnabled(reviewResult) {
+		h.startQualityGate(ctx, reviewResult, gitlabClient)
+		defer func() {
+			h.abortQualityGate(ctx, reviewResult, gitlabClient, err)
+		}()
+	}
+
+	// Step 2.7: Run the repository's static analyzers; their findings on changed
+	// lines go into the prompt and the results
+	analyzerFindings := h.runAnalyzers(ctx, reviewResult, diff)
+
+	// Step 3: Perform LLM code review (usage of each LLM call is logged as it completes)
+	reviewResp, err := h.callLLMReview(ctx, reviewResult, diff, analyzerFindings)
 	if err != nil {
-		// Log failed usage even on error (tokens may have been consumed)
-		h.logUsage(reviewResult, nil, err)
-		h.markReviewFailed(reviewResult.ID, fmt.Sprintf("LLM review failed: %v", err))
+		var rateErr *llm.RateLimitError
+		if errors.As(err, &rateErr) {
+			// Throttled: back to pending, the queue retries once the provider has capacity
+			h.deferReview(reviewResult, err)
+			return err
+		}
+
+		if h.isSuperseded(reviewResult) {
+			return errReviewSuperseded // Cancelled in favour of a newer commit
+		}
+		h.markReviewFailed(reviewResult, fmt.Sprintf("LLM review failed: %v", err))
 		return err
 	}
 
-	// Step 3.5: Log successful LLM usage for operations analytics
-	h.logUsage(reviewResult, reviewResp, nil)
+	// Step 3.5: Record the review's token totals
+	h.updateReviewTokens(reviewResult, reviewResp)
+
+	// Step 3.6: Drop the result if a newer commit arrived during the LLM call
+	if err := h.checkCurrent(ctx, reviewResult); err != nil {
+		return err
+	}
+	if err := h.checkMRHead(ctx, reviewResult, gitlabClient); err != nil {
+		return err
+	}
 
 	// Step 4: Save review results to database
 	if err := h.saveReviewResults(reviewResult, reviewResp); err != nil {
 		return err
 	}
 
+	// Step 4.5: Recommend human reviewers (listed in the comment posted next)
+	h.recommendReviewers(ctx, reviewResult, gitlabClient, diff, reviewResp)
+
 	// Step 5: Post comment to GitLab MR
 	// FIXED: Now returns error to trigger Asynq retry if comment fails
-	if err := h.postCommentToGitLab(reviewResult, gitlabClient, reviewResp); err != nil {
+	if err := h.postCommentToGitLab(ctx, reviewResult, gitlabClient, reviewResp); err != nil {
 		h.log.Error("Failed to post comment, will retry", "error", err, "review_id", reviewResult.ID)
 		return fmt.Errorf("failed to post comment: %w", err)
 	}
 
+	// Step 5.5: Report the quality gate outcome on the head commit
+	if gateEnabled(reviewResult) {
+		h.applyQualityGate(ctx, reviewResult, gitlabClient, reviewResp)
+	}
+
+	// Step 5.6: Keep the MR's rule labels in sync with the findings
+	h.applyLabelRules(ctx, reviewResult, gitlabClient, reviewResp, diff)
+
 	// Step 6: Update webhook event status to completed (if associated with webhook)
 	if reviewResult.WebhookEventID != nil {
-		h.updateWebhookEventStatus(reviewResult, model.EventStatusCompleted)
+		h.updateWebhookEventStatus(reviewResult, model.EventStatusCompleted, "")
 	}
 
 	h.log.Info("Code review completed successfully",
@@ -109,6 +217,7 @@ func (h *ReviewHandler) loadReviewContext(t *asynq.Task) (*model.ReviewResult, e
 	err := h.db.
 		Preload("Repository.Platform").
 		Preload("Repository.LLMProvider").
+		Preload("Repository.VerifierLLMProvider").
 		Preload("LLMProvider").
 		First(&reviewResult, payload.ReviewResultID).Error
 
@@ -117,6 +226,15 @@ func (h *ReviewHandler) loadReviewContext(t *asynq.Task) (*model.ReviewResult, e
 		return nil, fmt.Errorf("review result not found: %w", err)
 	}
 
+	// The review moved on to a newer commit after this task was enqueued
+	if payload.CommitSHA != "" && reviewResult.HeadSHA != payload.CommitSHA {
+		h.log.Info("Skipping superseded review task",
+			"review_id", reviewResult.ID,
+			"task_commit", payload.CommitSHA,
+			"head_commit", reviewResult.HeadSHA)
+		return nil, errReviewSuperseded
+	}
+
 	// Verify LLM provider is configured
 	if reviewResult.LLMProvider == nil {
 		h.log.Error("No LLM provider configured", "review_id", reviewResult.ID)
@@ -124,27 +242,81 @@ func (h *ReviewHandler) loadReviewContext(t *asynq.Task) (*model.ReviewResult, e
 	}
 
 	// Update status to processing
-	if err := h.db.Model(&reviewResult).Update("status", "processing").Error; err != nil {
+	if err := h.db.Model(&reviewResult).Update("status", model.ReviewStatusProcessing).Error; err != nil {
 		h.log.Error("Failed to update review status", "error", err)
 	}
 
 	return &reviewResult, nil
 }
 
+// checkCurrent returns an error when the task was cancelled or a newer commit
+// took over the review while it was running
+func (h *ReviewHandler) checkCurrent(ctx context.Context, review *model.ReviewResult) error {
+	if h.isSuperseded(review) {
+		return errReviewSuperseded
+	}
+	if err := ctx.Err(); err != nil {
+		h.log.Info("Code review task cancelled", "review_id", review.ID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// isSuperseded reports whether the review record now tracks a different head commit
+func (h *ReviewHandler) isSuperseded(review *model.ReviewResult) bool {
+	if review.HeadSHA == "" {
+		return false
+	}
+
+	var headSHA string
+	if err := h.db.Model(&model.ReviewResult{}).Where("id = ?", review.ID).
+		Select("head_sha").Scan(&headSHA).Error; err != nil {
+		h.log.Error("Failed to check review head commit", "error", err, "review_id", review.ID)
+		return false
+	}
+	return headSHA != review.HeadSHA
+}
+
+// checkMRHead compares the reviewed commit with the MR's current head on GitLab.
+// Lookup errors are logged and ignored; the head check must not fail a finished review.
+func (h *ReviewHandler) checkMRHead(ctx context.Context, review *model.ReviewResult, client *gitlab.Client) error {
+	if review.HeadSHA == "" {
+		return nil
+	}
+
+	mr, err := client.GetMergeRequestContext(ctx, int(review.Repository.PlatformRepoID), int(review.MergeRequestID))
+	if err != nil {
+		h.log.Error("Failed to check MR head commit", "error", err, "review_id", review.ID)
+		return nil
+	}
+	if mr.SHA != "" && mr.SHA != review.HeadSHA {
+		h.log.Info("MR head moved during review",
+			"review_id", review.ID,
+			"reviewed_commit", review.HeadSHA,
+			"head_commit", mr.SHA)
+		return errReviewSuperseded
+	}
+	return nil
+}
+
 // fetchMRDiff fetches MR diff from GitLab
-func (h *ReviewHandler) fetchMRDiff(review *model.ReviewResult) (string, *gitlab.Client, error) {
+func (h *ReviewHandler) fetchMRDiff(ctx context.Context, review *model.ReviewResult) (string, *gitlab.Client, error) {
 	h.log.Info("Fetching MR diff from GitLab",
 		"review_id", review.ID,
 		"mr_id", review.MergeRequestID,
 		"platform", review.Repository.Platform.BaseURL)
 
-	client := gitlab.NewClient(
-		review.Repository.Platform.BaseURL,
-		review.Repository.Platform.AccessToken,
-	)
+	accessToken, err := crypto.DecryptString(review.Repository.Platform.AccessToken, h.encryptionKey)
+	if err != nil {
+		h.log.Error("Failed to decrypt platform access token", "error", err, "review_id", review.ID)
+		return "", nil, fmt.Errorf("failed to decrypt access token: %w", err)
+	}
+
+	client := gitlab.NewClient(review.Repository.Platform.BaseURL, accessToken)
 
 	// Note: We need platform_project_id from Repository, not from payload
-	diff, err := client.GetMRDiff(
+	diff, err := client.GetMRDiffContext(
+		ctx,
 		int(review.Repository.PlatformRepoID),
 		int(review.MergeRequestID),
 	)
@@ -158,18 +330,58 @@ func (h *ReviewHandler) fetchMRDiff(review *model.ReviewResult) (string, *gitlab
 	return diff, client, nil
 }
 
-// callLLMReview calls LLM to perform code review
-func (h *ReviewHandler) callLLMReview(review *model.ReviewResult, diff string) (*llm.ReviewResponse, error) {
+// callLLMReview calls LLM to perform code review: one pass per active reviewer
+// profile, or a single general pass when the project has none. Analyzer findings
+// are given to every pass as context and reported with the LLM's.
+func (h *ReviewHandler) callLLMReview(ctx context.Context, review *model.ReviewResult, diff string, analyzerFindings []analyzer.Finding) (*llm.ReviewResponse, error) {
 	h.log.Info("Starting LLM code review",
 		"review_id", review.ID,
 		"repository", review.Repository.Name,
 		"llm_provider", review.LLMProvider.Name)
 
+	// Redact secrets first; they are reported as findings instead of being sent
+	diff, secretFindings, err := h.redactDiff(review, diff)
+	if err != nil {
+		return nil, err
+	}
+
+	passes := h.reviewPasses(review)
+	if len(analyzerFindings) > 0 {
+		findings := analyzerPromptFindings(analyzerFindings)
+		for i := range passes {
+			passes[i].AnalyzerFindings = findings
+		}
+	}
+
+	reviewResp, err := h.runReviewPasses(ctx, review, passes, diff)
+	if err != nil {
+		return nil, err
+	}
+
+	// Consensus mode: a second model cross-checks the severe findings
+	h.verifyFindings(ctx, review, reviewResp, diff)
+	appendSecretFindings(reviewResp, secretFindings)
+	appendAnalyzerFindings(reviewResp, analyzerFindings)
+
+	// Drop what developers dismissed on earlier commits, then number the findings
+	// so replies on the MR comment can refer to them
+	h.suppressDismissed(review, reviewResp)
+	llm.NumberSuggestions(reviewResp.Suggestions)
+
+	h.log.Info("LLM review completed",
+		"tokens_used", reviewResp.TokensUsed,
+		"duration", reviewResp.Duration,
+		"suggestions", len(reviewResp.Suggestions))
+
+	return reviewResp, nil
+}
+
+// runReviewPass makes the LLM call of one review pass and logs its usage
+func (h *ReviewHandler) runReviewPass(ctx context.Context, review *model.ReviewResult, pass reviewPass, diff string) (*llm.ReviewResponse, error) {
+	provider := pass.Provider
+
 	// Get or create LLM client (uses pool for performance)
-	llmClient, err := llm.GetOrCreateClient(
-		review.LLMProvider,
-		h.encryptionKey,
-	)
+	llmClient, err := llm.GetOrCreateClient(provider, h.encryptionKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get LLM client: %w", err)
 	}
@@ -182,9 +394,20 @@ func (h *ReviewHandler) callLLMReview(review *model.ReviewResult, diff string) (
 		review.SourceBranch,
 		review.TargetBranch,
 	)
-
-	promptTemplate := h.getPromptTemplate(review)
-	prompt := llm.RenderPrompt(promptTemplate, promptData)
+	promptData.AnalyzerFindings = pass.AnalyzerFindings
+	prompt := llm.RenderPrompt(pass.Template, promptData)
+
+	// Reuse the response of an identical earlier review
+	cacheKey := ""
+	if h.responseCacheEnabled(review) {
+		cacheKey = llm.ResponseCacheKey(provider.ID, provider.Model, pass.Template, promptData)
+		if cached := h.cachedResponse(review, cacheKey); cached != nil {
+			h.logUsage(review, provider, model.UsageTypeCodeReview, cached, nil)
+			categorizeSuggestions(cached, pass.Category)
+			stampSuggestions(cached, provider.Model, pass.Template)
+			return cached, nil
+		}
+	}
 
 	// Prepare review request
 	reviewReq := llm.ReviewRequest{
@@ -192,24 +415,41 @@ func (h *ReviewHandler) callLLMReview(review *model.ReviewResult, diff string) (
 		Prompt:      prompt,
 		MaxTokens:   4096,
 		Temperature: 0.7,
-		ModelName:   review.LLMProvider.Model,
+		ModelName:   provider.Model,
+	}
+
+	// Wait for the provider's rate limit budget (shared by all workers)
+	estimatedTokens := llm.EstimateTokens(reviewReq)
+	if err := h.waitForRateLimit(ctx, provider, estimatedTokens); err != nil {
+		return nil, err
 	}
 
 	// Call LLM API
 	h.log.Info("Calling LLM API",
-		"provider", review.LLMProvider.Name,
-		"model", review.LLMProvider.Model)
+		"provider", provider.Name,
+		"model", provider.Model,
+		"pass", pass.Name)
 
-	reviewResp, err := llmClient.Review(reviewReq)
+	reviewResp, err := llmClient.ReviewContext(ctx, reviewReq)
 	if err != nil {
+		var rateErr *llm.RateLimitError
+		if errors.As(err, &rateErr) {
+			h.pauseProvider(provider, rateErr)
+		}
+		// Log failed usage even on error (tokens may have been consumed)
+		h.logUsage(review, provider, model.UsageTypeCodeReview, nil, err)
 		return nil, fmt.Errorf("LLM API call failed: %w", err)
 	}
 
-	h.log.Info("LLM review completed",
-		"tokens_used", reviewResp.TokensUsed,
-		"duration", reviewResp.Duration,
-		"suggestions", len(reviewResp.Suggestions))
+	// Settle the estimate against the real usage
+	h.chargeRateLimit(provider, reviewResp.TokenUsage.TotalTokens-estimatedTokens)
+	h.logUsage(review, provider, model.UsageTypeCodeReview, reviewResp, nil)
 
+	if cacheKey != "" {
+		h.storeResponse(review, provider, cacheKey, reviewResp)
+	}
+	categorizeSuggestions(reviewResp, pass.Category)
+	stampSuggestions(reviewResp, provider.Model, pass.Template)
 	return reviewResp, nil
 }
 
@@ -303,51 +543,208 @@ func (h *ReviewHandler) isCustomPromptUsed(review *model.ReviewResult) bool {
 	return h.getPromptSource(review) != "default"
 }
 
-// postCommentToGitLab posts review comment to GitLab MR
+// postCommentToGitLab posts review comment to GitLab MR.
+// The first review starts a thread; re-reviews edit its note in place so the MR keeps
+// a single, current bot comment. The thread is resolved once no open findings remain.
 // FIXED: Now returns error to trigger retry (was swallowing error before)
-func (h *ReviewHandler) postCommentToGitLab(review *model.ReviewResult, client *gitlab.Client, resp *llm.ReviewResponse) error {
+func (h *ReviewHandler) postCommentToGitLab(ctx context.Context, review *model.ReviewResult, client *gitlab.Client, resp *llm.ReviewResponse) error {
 	h.log.Info("Posting review comment to GitLab MR",
 		"review_id", review.ID,
 		"mr_id", review.MergeRequestID)
 
+	projectID, mrIID := int(review.Repository.PlatformRepoID), int(review.MergeRequestID)
 	comment := gitlab.FormatReviewComment(resp)
-	err := client.PostMRComment(
-		int(review.Repository.PlatformRepoID),
-		int(review.MergeRequestID),
-		comment,
-	)
+	if review.CommentNoteID != 0 {
+		comment += fmt.Sprintf("\n\n_Updated for commit %s_", shortSHA(review.HeadSHA))
+	}
 
+	updates := map[string]interface{}{"comment_posted": true}
+	err := gitlab.ErrNotFound
+	if review.CommentNoteID != 0 {
+		err = client.UpdateMRNoteContext(ctx, projectID, mrIID, review.CommentNoteID, comment)
+	}
+	if errors.Is(err, gitlab.ErrNotFound) {
+		// First review, or the previous comment was deleted
+		var discussion *gitlab.Discussion
+		discussion, err = client.CreateMRDiscussionContext(ctx, projectID, mrIID, comment)
+		if err == nil {
+			h.collapseOutdatedReviews(ctx, review, client, discussion)
+		}
+		if err == nil && len(discussion.Notes) > 0 {
+			review.CommentNoteID = discussion.Notes[0].ID
+			review.CommentDiscussionID = discussion.ID
+			updates["comment_note_id"] = review.CommentNoteID
+			updates["comment_discussion_id"] = review.CommentDiscussionID
+			if review.MRWebURL != "" {
+				review.CommentURL = fmt.Sprintf("%s#note_%d", review.MRWebURL, review.CommentNoteID)
+				updates["comment_url"] = review.CommentURL
+			}
+		}
+	}
 	if err != nil {
 		h.log.Error("Failed to post comment to GitLab", "error", err, "review_id", review.ID)
 		return fmt.Errorf("failed to post comment: %w", err)
 	}
 
 	// Update comment_posted flag
-	if err := h.db.Model(review).Update("comment_posted", true).Error; err != nil {
+	if err := h.db.Model(review).Updates(updates).Error; err != nil {
 		h.log.Error("Failed to update comment_posted flag", "error", err)
 		// Don't fail the task for this minor error
 	}
 
+	h.resolveReviewThread(ctx, review, client, resp)
+
 	h.log.Info("Review comment posted successfully", "review_id", review.ID)
 	return nil
 }
 
-// markReviewFailed marks review as failed in database
-func (h *ReviewHandler) markReviewFailed(reviewID uint, errorMsg string) {
+// collapseOutdatedReviews marks the bot's older review comments on the merge request
+// outdated once a new review thread is started, e.g. the notes of earlier versions that
+// posted a new comment on every push, or a thread whose first note was deleted.
+// Best-effort: failures are logged.
+func (h *ReviewHandler) collapseOutdatedReviews(ctx context.Context, review *model.ReviewResult, client *gitlab.Client, current *gitlab.Discussion) {
+	projectID, mrIID := int(review.Repository.PlatformRepoID), int(review.MergeRequestID)
+	bot, err := client.GetCurrentUserContext(ctx)
+	if err != nil {
+		h.log.Error("Failed to look up bot user", "error", err, "review_id", review.ID)
+		return
+	}
+	notes, err := client.GetMRNotesContext(ctx, projectID, mrIID)
+	if err != nil {
+		h.log.Error("Failed to list merge request notes", "error", err, "review_id", review.ID)
+		return
+	}
+
+	for _, note := range notes {
+		if note.System || note.Author.ID != bot.ID || !gitlab.IsReviewComment(note.Body) ||
+			(len(current.Notes) > 0 && note.ID == current.Notes[0].ID) {
+			continue
+		}
+		if err := client.UpdateMRNoteContext(ctx, projectID, mrIID, note.ID, gitlab.FormatOutdatedReviewComment(note.Body)); err != nil {
+			h.log.Error("Failed to mark review comment outdated", "error", err, "review_id", review.ID, "note_id", note.ID)
+		}
+	}
+}
+
+// resolveReviewThread resolves the review thread when all findings were fixed or
+// dismissed, and reopens it when new ones appear. Best-effort: failures are logged.
+func (h *ReviewHandler) resolveReviewThread(ctx context.Context, review *model.ReviewResult, client *gitlab.Client, resp *llm.ReviewResponse) {
+	if review.CommentDiscussionID == "" {
+		return
+	}
+
+	open := 0
+	for _, sug := range resp.Suggestions {
+		if !sug.Rejected() {
+			open++
+		}
+	}
+
+	err := client.ResolveMRDiscussionContext(ctx, int(review.Repository.PlatformRepoID), int(review.MergeRequestID),
+		review.CommentDiscussionID, open == 0)
+	if err != nil {
+		h.log.Error("Failed to update review thread resolution", "error", err, "review_id", review.ID)
+	}
+}
+
+// shortSHA abbreviates a commit SHA the way GitLab shows it
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
+
+// markReviewFailed marks review as failed in database.
+// A superseded review is left alone: the record belongs to the newer commit's task.
+func (h *ReviewHandler) markReviewFailed(review *model.ReviewResult, errorMsg string) {
+	if h.isSuperseded(review) {
+		return
+	}
+
 	storage := service.NewReviewStorageService(h.db)
-	if err := storage.MarkReviewFailed(&model.ReviewResult{ID: reviewID}, errorMsg); err != nil {
-		h.log.Error("Failed to mark review as failed", "error", err, "review_id", reviewID)
+	if err := storage.MarkReviewFailed(&model.ReviewResult{ID: review.ID}, errorMsg); err != nil {
+		h.log.Error("Failed to mark review as failed", "error", err, "review_id", review.ID)
+	}
+}
+
+// markReviewSuperseded records that the MR moved past the reviewed commit.
+// Only applies while the record still tracks that commit; otherwise a newer task owns it.
+func (h *ReviewHandler) markReviewSuperseded(review *model.ReviewResult) {
+	err := h.db.Model(&model.ReviewResult{}).
+		Where("id = ? AND head_sha = ?", review.ID, review.HeadSHA).
+		Updates(map[string]interface{}{
+			"status":        model.ReviewStatusSuperseded,
+			"error_message": errReviewSuperseded.Error(),
+		}).Error
+	if err != nil {
+		h.log.Error("Failed to mark review as superseded", "error", err, "review_id", review.ID)
+	}
+}
+
+// ignoreWebhookEvent marks the triggering webhook event as ignored with a reason
+func (h *ReviewHandler) ignoreWebhookEvent(review *model.ReviewResult, reason string) {
+	err := h.db.Model(&model.WebhookEvent{}).Where("id = ?", *review.WebhookEventID).Updates(map[string]interface{}{
+		"status":        model.EventStatusIgnored,
+		"ignore_reason": reason,
+		"processed_at":  time.Now(),
+	}).Error
+	if err != nil {
+		h.log.Error("Failed to update webhook event status", "error", err, "webhook_event_id", *review.WebhookEventID)
+	}
+}
+
+// releaseWebhookCommit clears the commit of the review's webhook event once the
+// task has failed for good, so a redelivery of the commit can retry it
+func (h *ReviewHandler) releaseWebhookCommit(review *model.ReviewResult) {
+	err := h.db.Model(&model.WebhookEvent{}).Where("id = ?", *review.WebhookEventID).Update("commit_sha", nil).Error
+	if err != nil {
+		h.log.Error("Failed to release webhook event commit", "error", err, "webhook_event_id", *review.WebhookEventID)
+	}
+}
+
+// finalAttempt reports whether a failure of the running task leaves no retries
+func finalAttempt(ctx context.Context) bool {
+	retried, ok := asynq.GetRetryCount(ctx)
+	if !ok {
+		return false
+	}
+	maxRetry, ok := asynq.GetMaxRetry(ctx)
+	return ok && retried >= maxRetry
+}
+
+// skipReview marks a review as skipped (not an error, so the task is not retried)
+func (h *ReviewHandler) skipReview(review *model.ReviewResult, reason string) {
+	h.skipReviewWithStatus(review, model.ReviewStatusSkipped, reason)
+}
+
+// skipReviewWithStatus ends a review without calling the LLM, recording why
+func (h *ReviewHandler) skipReviewWithStatus(review *model.ReviewResult, status, reason string) {
+	h.log.Info("Skipping code review", "review_id", review.ID, "status", status, "reason", reason)
+
+	err := h.db.Model(&model.ReviewResult{}).Where("id = ?", review.ID).Updates(map[string]interface{}{
+		"status":        status,
+		"error_message": reason,
+	}).Error
+	if err != nil {
+		h.log.Error("Failed to mark review as skipped", "error", err, "review_id", review.ID)
+	}
+
+	if review.WebhookEventID != nil {
+		h.ignoreWebhookEvent(review, reason)
 	}
 }
 
 // updateWebhookEventStatus updates webhook event status
 // Note: This function assumes WebhookEventID is NOT nil - caller must check before calling
 // Do not add defensive nil checks here - let it fail fast if misused
-func (h *ReviewHandler) updateWebhookEventStatus(review *model.ReviewResult, status model.EventStatus) {
-	now := time.Now()
+func (h *ReviewHandler) updateWebhookEventStatus(review *model.ReviewResult, status model.EventStatus, errorMsg string) {
 	updates := map[string]interface{}{
-		"status":       status,
-		"processed_at": now,
+		"status":        status,
+		"error_message": errorMsg,
+	}
+	if status != model.EventStatusProcessing && status != model.EventStatusPending {
+		updates["processed_at"] = time.Now()
 	}
 
 	if err := h.db.Model(&model.WebhookEvent{}).Where("id = ?", *review.WebhookEventID).Updates(updates).Error; err != nil {
@@ -381,9 +778,9 @@ func (h *ReviewHandler) getPromptTemplate(review *model.ReviewResult) string {
 	return llm.GetDefaultPrompt()
 }
 
-// logUsage logs LLM API usage to the database for operations analytics
+// logUsage logs one LLM API call to the database for operations analytics
 // This function is best-effort - it won't fail the review if logging fails
-func (h *ReviewHandler) logUsage(review *model.ReviewResult, resp *llm.ReviewResponse, apiErr error) {
+func (h *ReviewHandler) logUsage(review *model.ReviewResult, provider *model.LLMProvider, requestType model.UsageRequestType, resp *llm.ReviewResponse, apiErr error) {
 	usageSvc := service.NewUsageService(h.db)
 
 	// Build usage context from review
@@ -391,9 +788,9 @@ func (h *ReviewHandler) logUsage(review *model.ReviewResult, resp *llm.ReviewRes
 		ReviewResultID: &review.ID,
 		RepositoryID:   review.RepositoryID,
 		ProjectID:      review.Repository.ProjectID,
-		LLMProviderID:  review.LLMProviderID,
-		ModelName:      review.LLMProvider.Model,
-		RequestType:    model.UsageTypeCodeReview,
+		LLMProviderID:  provider.ID,
+		ModelName:      provider.Model,
+		RequestType:    requestType,
 	}
 
 	// Build metrics based on success or failure
@@ -405,6 +802,9 @@ func (h *ReviewHandler) logUsage(review *model.ReviewResult, resp *llm.ReviewRes
 			Status:   model.UsageStatusFailed,
 			ErrorMsg: apiErr.Error(),
 		}
+	} else if resp != nil && resp.Cached {
+		// Served from the response cache, no API call was made
+		metrics = service.UsageMetrics{Status: model.UsageStatusCached}
 	} else if resp != nil {
 		// Successful request
 		metrics = service.UsageMetrics{
@@ -417,22 +817,25 @@ func (h *ReviewHandler) logUsage(review *model.ReviewResult, resp *llm.ReviewRes
 	}
 
 	// Log usage (best-effort, don't fail the review)
-	if _, err := usageSvc.LogUsage(ctx, metrics); err != nil {
+	if usageLog, err := usageSvc.LogUsage(ctx, metrics); usageLog != nil && err != nil {
+		h.log.Error("LLM usage logged without cost", "error", err, "review_id", review.ID)
+	} else if err != nil {
 		h.log.Error("Failed to log LLM usage", "error", err, "review_id", review.ID)
 		// Don't return error - usage logging is non-critical
 	}
+}
 
-	// Update denormalized token fields in ReviewResult (if successful)
-	if resp != nil {
-		if err := usageSvc.UpdateReviewTokens(
-			review.ID,
-			resp.TokenUsage.PromptTokens,
-			resp.TokenUsage.CompletionTokens,
-			resp.TokenUsage.TotalTokens,
-			resp.Duration.Milliseconds(),
-		); err != nil {
-			h.log.Error("Failed to update review tokens", "error", err, "review_id", review.ID)
-			// Don't return error - this is also non-critical
-		}
+// updateReviewTokens updates the denormalized token fields in ReviewResult
+// with the totals of all LLM calls of the review (best-effort)
+func (h *ReviewHandler) updateReviewTokens(review *model.ReviewResult, resp *llm.ReviewResponse) {
+	if err := service.NewUsageService(h.db).UpdateReviewTokens(
+		review.ID,
+		resp.TokenUsage.PromptTokens,
+		resp.TokenUsage.CompletionTokens,
+		resp.TokenUsage.TotalTokens,
+		resp.Duration.Milliseconds(),
+	); err != nil {
+		h.log.Error("Failed to update review tokens", "error", err, "review_id", review.ID)
+		// Don't return error - this is non-critical
 	}
 }
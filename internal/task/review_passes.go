@@ -0,0 +1,241 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/service"
+)
+
+// reviewPass is one LLM review of the diff
+type reviewPass struct {
+	Name     string // Reviewer profile name ("" for the general review)
+	Category string // Set on every suggestion of the pass ("" keeps the LLM's categories)
+	Provider *model.LLMProvider
+	Template string
+
+	AnalyzerFindings string // Static analyzer findings given as context (see llm.PromptData)
+}
+
+// reviewPasses returns one pass per active reviewer profile of the project,
+// or the general review pass when there are none
+func (h *ReviewHandler) reviewPasses(review *model.ReviewResult) []reviewPass {
+	template := h.getPromptTemplate(review)
+
+	profiles, err := service.NewReviewerProfileService(h.db).ActiveProfiles(review.Repository.ProjectID)
+	if err != nil {
+		h.log.Error("Failed to load reviewer profiles, using a general review",
+			"error", err, "review_id", review.ID)
+	}
+	if len(profiles) == 0 {
+		return []reviewPass{{Provider: review.LLMProvider, Template: template}}
+	}
+
+	passes := make([]reviewPass, 0, len(profiles))
+	for _, profile := range profiles {
+		pass := reviewPass{
+			Name:     profile.Name,
+			Category: profile.Category,
+			Provider: review.LLMProvider,
+			Template: profile.PromptTemplate,
+		}
+		if profile.LLMProvider != nil && profile.LLMProvider.IsActive {
+			pass.Provider = profile.LLMProvider
+		}
+		if pass.Template == "" {
+			pass.Template = llm.FocusPrompt(template, profile.Category)
+		}
+		passes = append(passes, pass)
+	}
+	return passes
+}
+
+// runReviewPasses runs the passes in parallel and merges their responses.
+// Every pass runs to completion so the successful ones are cached for the retry
+// when another fails; a rate limit is only returned if no pass failed otherwise.
+func (h *ReviewHandler) runReviewPasses(ctx context.Context, review *model.ReviewResult, passes []reviewPass, diff string) (*llm.ReviewResponse, error) {
+	if len(passes) == 1 {
+		return h.runReviewPass(ctx, review, passes[0], diff)
+	}
+
+	responses := make([]*llm.ReviewResponse, len(passes))
+	errs := make([]error, len(passes))
+	var wg sync.WaitGroup
+	for i, pass := range passes {
+		wg.Add(1)
+		go func(i int, pass reviewPass) {
+			defer wg.Done()
+			responses[i], errs[i] = h.runReviewPass(ctx, review, pass, diff)
+		}(i, pass)
+	}
+	wg.Wait()
+
+	var rateLimited error
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		var rateErr *llm.RateLimitError
+		if !errors.As(err, &rateErr) {
+			return nil, fmt.Errorf("%s review: %w", passes[i].Name, err)
+		}
+		if rateLimited == nil {
+			rateLimited = err
+		}
+	}
+	if rateLimited != nil {
+		return nil, rateLimited
+	}
+
+	return mergePassResponses(passes, responses), nil
+}
+
+// mergePassResponses combines the responses of parallel passes into one review.
+// The score is the lowest of the passes: a change is only as good as its weakest aspect.
+func mergePassResponses(passes []reviewPass, responses []*llm.ReviewResponse) *llm.ReviewResponse {
+	merged := &llm.ReviewResponse{Cached: true}
+	var summaries, models []string
+	var suggestions []llm.FixSuggestion
+
+	for i, resp := range responses {
+		pass := passes[i]
+		if resp.Summary != "" {
+			summaries = append(summaries, fmt.Sprintf("**%s**: %s", pass.Name, resp.Summary))
+		}
+		if i == 0 || resp.Score < merged.Score {
+			merged.Score = resp.Score
+		}
+		if !containsString(models, resp.ModelUsed) {
+			models = append(models, resp.ModelUsed)
+		}
+
+		suggestions = append(suggestions, resp.Suggestions...)
+
+		merged.TokensUsed += resp.TokensUsed
+		merged.TokenUsage.PromptTokens += resp.TokenUsage.PromptTokens
+		merged.TokenUsage.CompletionTokens += resp.TokenUsage.CompletionTokens
+		merged.TokenUsage.TotalTokens += resp.TokenUsage.TotalTokens
+		if resp.Duration > merged.Duration {
+			merged.Duration = resp.Duration // Passes run in parallel
+		}
+		merged.Cached = merged.Cached && resp.Cached
+	}
+
+	merged.Summary = strings.Join(summaries, "\n\n")
+	merged.ModelUsed = strings.Join(models, ", ")
+	merged.Suggestions = dedupeSuggestions(suggestions)
+	return merged
+}
+
+// categorizeSuggestions sets the category of a pass on all of its suggestions
+func categorizeSuggestions(resp *llm.ReviewResponse, category string) {
+	if category == "" {
+		return // The LLM's categories are kept
+	}
+	for i := range resp.Suggestions {
+		resp.Suggestions[i].Category = category
+	}
+}
+
+// dedupeSuggestions drops suggestions reported twice, keeping the more severe one.
+// Two suggestions are the same issue when they are on overlapping lines of the same
+// file and either share a category or describe it in largely the same words.
+func dedupeSuggestions(suggestions []llm.FixSuggestion) []llm.FixSuggestion {
+	merged := make([]llm.FixSuggestion, 0, len(suggestions))
+	for _, sug := range suggestions {
+		duplicate := -1
+		for i := range merged {
+			if sameIssue(merged[i], sug) {
+				duplicate = i
+				break
+			}
+		}
+
+		switch {
+		case duplicate < 0:
+			merged = append(merged, sug)
+		case severityRank(sug.Severity) > severityRank(merged[duplicate].Severity):
+			merged[duplicate] = sug
+		}
+	}
+	return merged
+}
+
+func sameIssue(a, b llm.FixSuggestion) bool {
+	if a.FilePath != b.FilePath {
+		return false
+	}
+	// Without line numbers only the wording can tell
+	if a.LineStart == 0 || b.LineStart == 0 {
+		return similarText(a.Description, b.Description)
+	}
+	if a.LineStart > lineEnd(b) || b.LineStart > lineEnd(a) {
+		return false
+	}
+	return a.Category == b.Category || similarText(a.Description, b.Description)
+}
+
+func lineEnd(s llm.FixSuggestion) int {
+	if s.LineEnd < s.LineStart {
+		return s.LineStart
+	}
+	return s.LineEnd
+}
+
+// similarText reports whether at least half of the distinct words of two descriptions
+// are shared by both (their Jaccard similarity is at least 0.5)
+func similarText(a, b string) bool {
+	wordsA, wordsB := significantWords(a), significantWords(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return false
+	}
+
+	shared := 0
+	for word := range wordsA {
+		if wordsB[word] {
+			shared++
+		}
+	}
+	union := len(wordsA) + len(wordsB) - shared
+	return float64(shared)/float64(union) >= 0.5
+}
+
+func significantWords(text string) map[string]bool {
+	words := map[string]bool{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_')
+	}) {
+		if len(word) >= 3 {
+			words[word] = true
+		}
+	}
+	return words
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case "critical":
+		return 4
+	case "high":
+		return 3
+	case "medium":
+		return 2
+	case "low":
+		return 1
+	}
+	return 0
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package task
+
+import (
+	"testing"
+
+	"github.com/handsoff/handsoff/internal/llm"
+)
+
+func TestMergePassResponses(t *testing.T) {
+	passes := []reviewPass{
+		{Name: "Security", Category: "security"},
+		{Name: "Correctness", Category: "correctness"},
+	}
+	responses := []*llm.ReviewResponse{
+		{
+			Summary: "Injection risk", Score: 60, ModelUsed: "gpt-4",
+			TokenUsage: llm.TokenUsage{TotalTokens: 100},
+			Suggestions: []llm.FixSuggestion{
+				{FilePath: "db.go", LineStart: 10, LineEnd: 12, Severity: "high", Category: "bug",
+					Description: "SQL query built from user input allows injection"},
+				{FilePath: "db.go", LineStart: 30, Severity: "low", Category: "style",
+					Description: "Hardcoded timeout value"},
+			},
+		},
+		{
+			Summary: "Logic is fine", Score: 80, ModelUsed: "gpt-4",
+			TokenUsage: llm.TokenUsage{TotalTokens: 50},
+			Suggestions: []llm.FixSuggestion{
+				// Same issue, same wording: merged into the more severe one
+				{FilePath: "db.go", LineStart: 11, Severity: "medium", Category: "security",
+					Description: "User input allows SQL injection in the query"},
+				// Same lines, different issue: kept
+				{FilePath: "db.go", LineStart: 12, Severity: "medium",
+					Description: "Rows are never closed"},
+			},
+		},
+	}
+
+	for i, resp := range responses {
+		categorizeSuggestions(resp, passes[i].Category)
+	}
+	merged := mergePassResponses(passes, responses)
+
+	if merged.Score != 60 {
+		t.Errorf("Expected the lowest score 60, got %d", merged.Score)
+	}
+	if merged.TokenUsage.TotalTokens != 150 || merged.ModelUsed != "gpt-4" {
+		t.Errorf("Unexpected totals: tokens=%d model=%q", merged.TokenUsage.TotalTokens, merged.ModelUsed)
+	}
+	if merged.Summary != "**Security**: Injection risk\n\n**Correctness**: Logic is fine" {
+		t.Errorf("Unexpected summary: %q", merged.Summary)
+	}
+
+	expected := []struct {
+		line     int
+		severity string
+		category string
+	}{
+		{10, "high", "security"},
+		{30, "low", "security"}, // Category fixed by the profile
+		{12, "medium", "correctness"},
+	}
+	if len(merged.Suggestions) != len(expected) {
+		t.Fatalf("Expected %d suggestions, got %d: %+v", len(expected), len(merged.Suggestions), merged.Suggestions)
+	}
+	for i, want := range expected {
+		got := merged.Suggestions[i]
+		if got.LineStart != want.line || got.Severity != want.severity || got.Category != want.category {
+			t.Errorf("Suggestion %d: got line=%d %s/%s, want line=%d %s/%s",
+				i, got.LineStart, got.Severity, got.Category, want.line, want.severity, want.category)
+		}
+	}
+}
+
+func TestCategorizeSuggestions(t *testing.T) {
+	resp := &llm.ReviewResponse{Suggestions: []llm.FixSuggestion{{Category: "bug"}}}
+
+	// The general review keeps the LLM's categories, also when it is the only pass
+	categorizeSuggestions(resp, "")
+	if got := resp.Suggestions[0].Category; got != "bug" {
+		t.Errorf("category = %q, want the LLM's %q", got, "bug")
+	}
+	categorizeSuggestions(resp, "security")
+	if got := resp.Suggestions[0].Category; got != "security" {
+		t.Errorf("category = %q, want the profile's %q", got, "security")
+	}
+}
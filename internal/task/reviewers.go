@@ -0,0 +1,103 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/handsoff/handsoff/internal/codeowners"
+	"github.com/handsoff/handsoff/internal/gitlab"
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+	"github.com/handsoff/handsoff/internal/service"
+)
+
+// recommendReviewers records the files the review covered and, when the repository
+// has reviewer recommendation on, lists the best human reviewers in the review
+// comment. In assign mode they are also set on an MR that has no reviewers yet.
+// Best-effort: failures are logged.
+func (h *ReviewHandler) recommendReviewers(ctx context.Context, review *model.ReviewResult, client *gitlab.Client, diff string, resp *llm.ReviewResponse) {
+	reviewerSvc := service.NewReviewerService(h.db)
+	changed := gitlab.ChangedFiles(diff)
+	if err := reviewerSvc.RecordChangedFiles(review.ID, changed); err != nil {
+		h.log.Error("Failed to record changed files", "error", err, "review_id", review.ID)
+	}
+
+	repo := review.Repository
+	if repo.ReviewerMode == model.ReviewerModeOff || len(changed) == 0 {
+		return
+	}
+
+	owners := h.loadCodeOwners(ctx, review, client)
+	candidates, err := reviewerSvc.Recommend(review, changed, owners, time.Now())
+	if err != nil {
+		h.log.Error("Failed to recommend reviewers", "error", err, "review_id", review.ID)
+		return
+	}
+	limit := max(repo.MaxReviewers, 1)
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	usernames := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		usernames = append(usernames, c.Username)
+		resp.Reviewers = append(resp.Reviewers, llm.ReviewerSuggestion{Username: c.Username, Reason: c.Reason()})
+	}
+	if err := reviewerSvc.SaveSuggested(review.ID, usernames); err != nil {
+		h.log.Error("Failed to save suggested reviewers", "error", err, "review_id", review.ID)
+	}
+
+	if repo.ReviewerMode == model.ReviewerModeAssign && len(usernames) > 0 {
+		h.assignReviewers(ctx, review, client, usernames)
+	}
+}
+
+// loadCodeOwners reads the CODEOWNERS file of the MR's target branch, nil if there is none
+func (h *ReviewHandler) loadCodeOwners(ctx context.Context, review *model.ReviewResult, client *gitlab.Client) *codeowners.File {
+	projectID := int(review.Repository.PlatformRepoID)
+	for _, location := range codeowners.Locations {
+		content, err := client.GetRawFileContext(ctx, projectID, location, review.TargetBranch)
+		if errors.Is(err, gitlab.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			h.log.Error("Failed to read CODEOWNERS", "error", err, "path", location, "review_id", review.ID)
+			return nil
+		}
+		return codeowners.Parse(content)
+	}
+	return nil
+}
+
+// assignReviewers sets the recommended users as the MR's reviewers, unless someone
+// already picked reviewers
+func (h *ReviewHandler) assignReviewers(ctx context.Context, review *model.ReviewResult, client *gitlab.Client, usernames []string) {
+	projectID, mrIID := int(review.Repository.PlatformRepoID), int(review.MergeRequestID)
+	mr, err := client.GetMergeRequestContext(ctx, projectID, mrIID)
+	if err != nil {
+		h.log.Error("Failed to get MR for reviewer assignment", "error", err, "review_id", review.ID)
+		return
+	}
+	if len(mr.Reviewers) > 0 {
+		return
+	}
+
+	var ids []int64
+	for _, username := range usernames {
+		user, err := client.FindUserContext(ctx, username)
+		if err != nil {
+			h.log.Info("Skipping reviewer that cannot be resolved", "username", username, "error", err, "review_id", review.ID)
+			continue
+		}
+		ids = append(ids, user.ID)
+	}
+	if len(ids) == 0 {
+		return
+	}
+	if err := client.SetMRReviewersContext(ctx, projectID, mrIID, ids); err != nil {
+		h.log.Error("Failed to assign MR reviewers", "error", err, "review_id", review.ID)
+		return
+	}
+	h.log.Info("MR reviewers assigned", "review_id", review.ID, "reviewers", usernames)
+}
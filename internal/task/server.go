@@ -1,8 +1,10 @@
 package task
 
 import (
+	"github.com/handsoff/handsoff/internal/analyzer"
 	"github.com/handsoff/handsoff/pkg/config"
 	"github.com/handsoff/handsoff/pkg/logger"
+	"github.com/handsoff/handsoff/pkg/ratelimit"
 	"github.com/hibiken/asynq"
 	"gorm.io/gorm"
 )
@@ -14,6 +16,8 @@ type Server struct {
 	db     *gorm.DB
 	cfg    *config.Config
 	log    *logger.Logger
+
+	limiter *ratelimit.Limiter
 }
 
 // NewServer creates a new task server
@@ -27,6 +31,14 @@ func NewServer(db *gorm.DB, cfg *config.Config, log *logger.Logger) *Server {
 		opt,
 		asynq.Config{
 			Concurrency: cfg.Worker.Concurrency,
+			// Running tasks get this long to finish on shutdown, then their context is
+			// cancelled and they are requeued
+			ShutdownTimeout: cfg.Worker.ShutdownTimeout,
+			// Throttled tasks wait for the provider and do not use up their retries
+			RetryDelayFunc: retryDelay,
+			IsFailure: func(err error) bool {
+				return !isRateLimited(err)
+			},
 			// Queues defines queue priority (higher value = higher priority)
 			Queues: map[string]int{
 				"critical": 6,
@@ -42,16 +54,22 @@ func NewServer(db *gorm.DB, cfg *config.Config, log *logger.Logger) *Server {
 	// Apply middleware
 	mux.Use(RecoveryMiddleware(log))
 	mux.Use(LoggingMiddleware(log))
+	mux.Use(TimeoutMiddleware(cfg.Worker.TaskTimeout))
 
 	// Initialize task handlers
-	reviewHandler := NewReviewHandler(db, log, cfg.Security.EncryptionKey)
+	limiter := ratelimit.NewLimiter(cfg.Redis)
+	analyzers := analyzer.NewRunner(cfg.Git, cfg.Analyzer)
+	reviewHandler := NewReviewHandler(db, log, cfg.Security.EncryptionKey, limiter, cfg.Worker.ResponseCacheTTL, analyzers)
 
 	// Register task handlers
 	mux.HandleFunc(TypeCodeReview, reviewHandler.HandleCodeReview)
+	mux.HandleFunc(TypeExplainSuggestion, reviewHandler.HandleExplainSuggestion)
+	mux.HandleFunc(TypeFollowUpReply, reviewHandler.HandleFollowUpReply)
+	mux.HandleFunc(TypeMRSummary, reviewHandler.HandleMRSummary)
 	// Future: mux.HandleFunc(TypeAutoFix, autoFixHandler.HandleAutoFix)
 
 	log.Info("Registered task handlers",
-		"handlers", []string{TypeCodeReview})
+		"handlers", []string{TypeCodeReview, TypeExplainSuggestion, TypeFollowUpReply, TypeMRSummary})
 
 	return &Server{
 		server: srv,
@@ -59,6 +77,8 @@ func NewServer(db *gorm.DB, cfg *config.Config, log *logger.Logger) *Server {
 		db:     db,
 		cfg:    cfg,
 		log:    log,
+
+		limiter: limiter,
 	}
 }
 
@@ -70,4 +90,7 @@ func (s *Server) Start() error {
 // Shutdown gracefully shuts down the server
 func (s *Server) Shutdown() {
 	s.server.Shutdown()
+	if err := s.limiter.Close(); err != nil {
+		s.log.Error("Failed to close rate limiter", "error", err)
+	}
 }
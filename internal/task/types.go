@@ -1,18 +1,34 @@
 package task
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 const (
 	// Task type names
-	TypeCodeReview = "code_review"
-	TypeAutoFix    = "auto_fix"
+	TypeCodeReview        = "code_review"
+	TypeAutoFix           = "auto_fix"
+	TypeExplainSuggestion = "explain_suggestion"
+	TypeFollowUpReply     = "follow_up_reply"
+	TypeMRSummary         = "mr_summary"
 )
 
 // CodeReviewPayload represents the payload for code review task
 // REFACTORED: Only pass ReviewResultID instead of duplicating all MR fields
 // Worker will load ReviewResult with all relationships from DB
 type CodeReviewPayload struct {
-	ReviewResultID uint `json:"review_result_id"` // Foreign key to review_results
+	ReviewResultID uint   `json:"review_result_id"`     // Foreign key to review_results
+	CommitSHA      string `json:"commit_sha,omitempty"` // Reviewed commit; the task is stale once the review moves to another SHA
+}
+
+// ReviewTaskID returns the asynq task ID of a review of the given commit.
+// Enqueueing the same repository+MR+commit twice is rejected by asynq as a conflict.
+func ReviewTaskID(repositoryID uint, mrIID int64, commitSHA string) string {
+	if commitSHA == "" {
+		return ""
+	}
+	return fmt.Sprintf("review:%d:%d:%s", repositoryID, mrIID, commitSHA)
 }
 
 // ToJSON converts payload to JSON
@@ -40,3 +56,73 @@ func (p *AutoFixPayload) ToJSON() ([]byte, error) {
 func (p *AutoFixPayload) FromJSON(data []byte) error {
 	return json.Unmarshal(data, p)
 }
+
+// ExplainSuggestionPayload represents the payload for explaining a finding in an MR thread
+// ("/handsoff explain <n>")
+type ExplainSuggestionPayload struct {
+	ReviewResultID uint   `json:"review_result_id"`
+	Number         int    `json:"number"`        // Finding #N of the latest MR comment
+	DiscussionID   string `json:"discussion_id"` // Thread to answer in
+	Username       string `json:"username"`      // Who asked
+}
+
+// ExplainTaskID returns the asynq task ID of the explanation a note asked for, so a
+// redelivered note is answered once
+func ExplainTaskID(repositoryID uint, noteID int64, number int) string {
+	return fmt.Sprintf("explain:%d:%d:%d", repositoryID, noteID, number)
+}
+
+// ToJSON converts payload to JSON
+func (p *ExplainSuggestionPayload) ToJSON() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+// FromJSON parses JSON to payload
+func (p *ExplainSuggestionPayload) FromJSON(data []byte) error {
+	return json.Unmarshal(data, p)
+}
+
+// FollowUpReplyPayload represents the payload for answering a developer's reply in a
+// thread the bot started
+type FollowUpReplyPayload struct {
+	ReviewResultID uint   `json:"review_result_id"`
+	DiscussionID   string `json:"discussion_id"`
+	NoteID         int64  `json:"note_id"` // The developer's reply; skipped once newer notes follow it
+}
+
+// FollowUpTaskID returns the asynq task ID of the answer to a note, so a redelivered
+// note is answered once
+func FollowUpTaskID(repositoryID uint, noteID int64) string {
+	return fmt.Sprintf("follow-up:%d:%d", repositoryID, noteID)
+}
+
+// ToJSON converts payload to JSON
+func (p *FollowUpReplyPayload) ToJSON() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+// FromJSON parses JSON to payload
+func (p *FollowUpReplyPayload) FromJSON(data []byte) error {
+	return json.Unmarshal(data, p)
+}
+
+// MRSummaryPayload represents the payload for generating a merge request summary
+type MRSummaryPayload struct {
+	ReviewResultID uint `json:"review_result_id"`
+}
+
+// MRSummaryTaskID returns the asynq task ID of the summary of a merge request,
+// so events arriving while it is queued do not enqueue it again
+func MRSummaryTaskID(repositoryID uint, mrIID int64) string {
+	return fmt.Sprintf("mr-summary:%d:%d", repositoryID, mrIID)
+}
+
+// ToJSON converts payload to JSON
+func (p *MRSummaryPayload) ToJSON() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+// FromJSON parses JSON to payload
+func (p *MRSummaryPayload) FromJSON(data []byte) error {
+	return json.Unmarshal(data, p)
+}
@@ -0,0 +1,109 @@
+package task
+
+import (
+	"context"
+	"errors"
+
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+)
+
+// verifyFindings cross-checks the high and critical findings with the repository's
+// verifier provider (consensus mode). Verification is best-effort: if the call fails,
+// the findings are kept unverified and the review goes on.
+func (h *ReviewHandler) verifyFindings(ctx context.Context, review *model.ReviewResult, resp *llm.ReviewResponse, diff string) {
+	provider := review.Repository.VerifierLLMProvider
+	if provider == nil || !provider.IsActive {
+		return
+	}
+
+	var indexes []int
+	var findings []llm.FixSuggestion
+	for i, sug := range resp.Suggestions {
+		if llm.NeedsVerification(sug) {
+			indexes = append(indexes, i)
+			findings = append(findings, sug)
+		}
+	}
+	if len(findings) == 0 {
+		return
+	}
+
+	llmClient, err := llm.GetOrCreateClient(provider, h.encryptionKey)
+	if err != nil {
+		h.log.Error("Failed to get verifier LLM client, findings left unverified", "error", err, "review_id", review.ID)
+		return
+	}
+
+	req := llm.ReviewRequest{
+		Diff:        diff,
+		Prompt:      llm.BuildVerificationPrompt(diff, findings),
+		MaxTokens:   2048,
+		Temperature: 0.2,
+		ModelName:   provider.Model,
+	}
+	estimatedTokens := llm.EstimateTokens(req)
+	if err := h.waitForRateLimit(ctx, provider, estimatedTokens); err != nil {
+		h.log.Error("Verifier rate limited, findings left unverified", "error", err, "review_id", review.ID)
+		return
+	}
+
+	h.log.Info("Verifying findings with second model",
+		"review_id", review.ID,
+		"provider", provider.Name,
+		"findings", len(findings))
+
+	verifyResp, err := llmClient.ReviewContext(ctx, req)
+	if err != nil {
+		var rateErr *llm.RateLimitError
+		if errors.As(err, &rateErr) {
+			h.pauseProvider(provider, rateErr)
+		}
+		h.logUsage(review, provider, model.UsageTypeVerification, nil, err)
+		h.log.Error("Verifier call failed, findings left unverified", "error", err, "review_id", review.ID)
+		return
+	}
+	h.chargeRateLimit(provider, verifyResp.TokenUsage.TotalTokens-estimatedTokens)
+	h.logUsage(review, provider, model.UsageTypeVerification, verifyResp, nil)
+
+	resp.TokensUsed += verifyResp.TokensUsed
+	resp.TokenUsage.PromptTokens += verifyResp.TokenUsage.PromptTokens
+	resp.TokenUsage.CompletionTokens += verifyResp.TokenUsage.CompletionTokens
+	resp.TokenUsage.TotalTokens += verifyResp.TokenUsage.TotalTokens
+	resp.Duration += verifyResp.Duration
+
+	verdicts, err := llm.ParseVerdicts(verifyResp.RawResponse, len(findings))
+	if err != nil {
+		h.log.Error("Failed to parse verifier response, findings left unverified", "error", err, "review_id", review.ID)
+		return
+	}
+
+	rejected := applyVerdicts(resp.Suggestions, indexes, verdicts, review.Repository.RejectedFindingAction)
+	h.log.Info("Findings verified",
+		"review_id", review.ID,
+		"verified", len(verdicts),
+		"rejected", rejected)
+}
+
+// applyVerdicts records the verdicts on the suggestions they refer to (indexes maps
+// a verdict's 1-based finding number to its suggestion) and returns how many were
+// rejected. Rejected findings are hidden, or kept at low severity with downgrade.
+func applyVerdicts(suggestions []llm.FixSuggestion, indexes []int, verdicts []llm.Verdict, action string) int {
+	rejected := 0
+	for _, v := range verdicts {
+		sug := &suggestions[indexes[v.Index-1]]
+		sug.Verdict = v.Verdict
+		sug.VerdictConfidence = v.Confidence
+		sug.VerdictReason = v.Reason
+
+		if v.Verdict != model.VerdictRejected {
+			continue
+		}
+		rejected++
+		if action == model.RejectedFindingDowngrade {
+			sug.Verdict = model.VerdictDowngraded
+			sug.Severity = "low"
+		}
+	}
+	return rejected
+}
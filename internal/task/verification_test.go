@@ -0,0 +1,53 @@
+package task
+
+import (
+	"testing"
+
+	"github.com/handsoff/handsoff/internal/llm"
+	"github.com/handsoff/handsoff/internal/model"
+)
+
+func TestApplyVerdicts(t *testing.T) {
+	newSuggestions := func() []llm.FixSuggestion {
+		return []llm.FixSuggestion{
+			{Severity: "high", Description: "SQL injection"},
+			{Severity: "low", Description: "Naming"},
+			{Severity: "critical", Description: "Race condition"},
+		}
+	}
+	// Findings 1 and 2 of the prompt are suggestions 0 and 2
+	indexes := []int{0, 2}
+	verdicts := []llm.Verdict{
+		{Index: 1, Verdict: model.VerdictConfirmed, Confidence: 0.9},
+		{Index: 2, Verdict: model.VerdictRejected, Confidence: 0.7, Reason: "Guarded by a mutex"},
+	}
+
+	tests := []struct {
+		action   string
+		verdict  string
+		severity string
+	}{
+		{model.RejectedFindingDrop, model.VerdictRejected, "critical"},
+		{model.RejectedFindingDowngrade, model.VerdictDowngraded, "low"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			suggestions := newSuggestions()
+			if rejected := applyVerdicts(suggestions, indexes, verdicts, tt.action); rejected != 1 {
+				t.Errorf("Expected 1 rejected finding, got %d", rejected)
+			}
+
+			if suggestions[0].Verdict != model.VerdictConfirmed || suggestions[0].VerdictConfidence != 0.9 {
+				t.Errorf("Unexpected confirmed finding: %+v", suggestions[0])
+			}
+			if suggestions[1].Verdict != "" {
+				t.Errorf("Expected the low finding to stay unverified, got %q", suggestions[1].Verdict)
+			}
+			if suggestions[2].Verdict != tt.verdict || suggestions[2].Severity != tt.severity ||
+				suggestions[2].VerdictReason != "Guarded by a mutex" {
+				t.Errorf("Unexpected rejected finding: %+v", suggestions[2])
+			}
+		})
+	}
+}
@@ -1,11 +1,12 @@
 package webhook
 
-// No external imports needed - GitLabTime is in same package
+import "strings"
 
 // GitLabWebhookEvent represents the base GitLab webhook event
 type GitLabWebhookEvent struct {
-	ObjectKind string `json:"object_kind"` // "merge_request"
-	EventType  string `json:"event_type"`  // e.g., "merge_request"
+	ObjectKind string        `json:"object_kind"` // "merge_request"
+	EventType  string        `json:"event_type"`  // e.g., "merge_request"
+	Project    GitLabProject `json:"project"`     // Used to locate the repository before authenticating
 }
 
 // GitLabMergeRequestEvent represents GitLab merge request webhook payload
@@ -45,31 +46,33 @@ type GitLabProject struct {
 
 // GitLabMergeRequestAttributes represents MR attributes
 type GitLabMergeRequestAttributes struct {
-	ID              int64      `json:"id"`
-	IID             int64      `json:"iid"`
-	TargetBranch    string     `json:"target_branch"`
-	SourceBranch    string     `json:"source_branch"`
-	SourceProjectID int64      `json:"source_project_id"`
-	AuthorID        int64      `json:"author_id"`
-	Title           string     `json:"title"`
-	Description     string     `json:"description"`
-	State           string     `json:"state"` // opened, closed, merged
-	MergeStatus     string     `json:"merge_status"`
-	URL             string     `json:"url"`
-	Action          string     `json:"action"` // open, update, merge, close, reopen
-	CreatedAt       GitLabTime  `json:"created_at"`
-	UpdatedAt       GitLabTime  `json:"updated_at"`
-	MergedAt        *GitLabTime `json:"merged_at"`
-	ClosedAt        *GitLabTime `json:"closed_at"`
+	ID              int64        `json:"id"`
+	IID             int64        `json:"iid"`
+	TargetBranch    string       `json:"target_branch"`
+	SourceBranch    string       `json:"source_branch"`
+	SourceProjectID int64        `json:"source_project_id"`
+	AuthorID        int64        `json:"author_id"`
+	Title           string       `json:"title"`
+	Description     string       `json:"description"`
+	State           string       `json:"state"` // opened, closed, merged
+	MergeStatus     string       `json:"merge_status"`
+	URL             string       `json:"url"`
+	Action          string       `json:"action"`           // open, update, merge, close, reopen
+	Draft           bool         `json:"draft"`            // GitLab 14+
+	WorkInProgress  bool         `json:"work_in_progress"` // Deprecated alias of draft
+	CreatedAt       GitLabTime   `json:"created_at"`
+	UpdatedAt       GitLabTime   `json:"updated_at"`
+	MergedAt        *GitLabTime  `json:"merged_at"`
+	ClosedAt        *GitLabTime  `json:"closed_at"`
 	LastCommit      GitLabCommit `json:"last_commit"`
 }
 
 // GitLabCommit represents a commit
 type GitLabCommit struct {
-	ID        string    `json:"id"`
-	Message   string    `json:"message"`
-	Timestamp GitLabTime `json:"timestamp"`
-	URL       string    `json:"url"`
+	ID        string             `json:"id"`
+	Message   string             `json:"message"`
+	Timestamp GitLabTime         `json:"timestamp"`
+	URL       string             `json:"url"`
 	Author    GitLabCommitAuthor `json:"author"`
 }
 
@@ -131,11 +134,18 @@ func (e *GitLabMergeRequestEvent) GetMRTitle() string {
 	return e.ObjectAttributes.Title
 }
 
-// GetMRAuthor returns the author username
+// GetMRAuthor returns the username of the user who triggered the event, which is
+// only the author's when AuthorIsActor reports so
 func (e *GitLabMergeRequestEvent) GetMRAuthor() string {
 	return e.User.Username
 }
 
+// AuthorIsActor reports whether the merge request's author triggered the event.
+// The payload only has the author's ID, so otherwise the author must be looked up.
+func (e *GitLabMergeRequestEvent) AuthorIsActor() bool {
+	return e.ObjectAttributes.AuthorID == 0 || e.ObjectAttributes.AuthorID == e.User.ID
+}
+
 // GetSourceBranch returns the source branch
 func (e *GitLabMergeRequestEvent) GetSourceBranch() string {
 	return e.ObjectAttributes.SourceBranch
@@ -150,3 +160,31 @@ func (e *GitLabMergeRequestEvent) GetTargetBranch() string {
 func (e *GitLabMergeRequestEvent) GetMRWebURL() string {
 	return e.ObjectAttributes.URL
 }
+
+// draftTitlePrefixes are the title prefixes GitLab treats as Draft/WIP markers
+var draftTitlePrefixes = []string{"draft:", "[draft]", "(draft)", "wip:", "[wip]"}
+
+// IsDraft reports whether the merge request is a Draft/WIP merge request
+func (e *GitLabMergeRequestEvent) IsDraft() bool {
+	if e.ObjectAttributes.Draft || e.ObjectAttributes.WorkInProgress {
+		return true
+	}
+
+	// Older GitLab versions only mark drafts through the title
+	title := strings.ToLower(strings.TrimSpace(e.ObjectAttributes.Title))
+	for _, prefix := range draftTitlePrefixes {
+		if strings.HasPrefix(title, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetLabels returns the titles of the labels on the merge request
+func (e *GitLabMergeRequestEvent) GetLabels() []string {
+	labels := make([]string, 0, len(e.Labels))
+	for _, label := range e.Labels {
+		labels = append(labels, label.Title)
+	}
+	return labels
+}
@@ -0,0 +1,89 @@
+package webhook
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/handsoff/handsoff/internal/model"
+)
+
+// GitLabNoteEvent represents GitLab comment webhook payload
+type GitLabNoteEvent struct {
+	ObjectKind       string                        `json:"object_kind"` // "note"
+	User             GitLabUser                    `json:"user"`
+	Project          GitLabProject                 `json:"project"`
+	ObjectAttributes GitLabNoteAttributes          `json:"object_attributes"`
+	MergeRequest     *GitLabMergeRequestAttributes `json:"merge_request"` // Set for comments on merge requests
+}
+
+// GitLabNoteAttributes represents comment attributes
+type GitLabNoteAttributes struct {
+	ID           int64  `json:"id"`
+	Note         string `json:"note"`
+	NoteableType string `json:"noteable_type"` // MergeRequest, Commit, Issue, Snippet
+	Type         string `json:"type"`          // DiscussionNote for replies in a thread, DiffNote, or empty
+	DiscussionID string `json:"discussion_id"`
+	System       bool   `json:"system"` // Generated by GitLab, not written by a user
+	URL          string `json:"url"`
+}
+
+// IsMergeRequestComment reports whether the event is a user's comment on a merge request
+func (e *GitLabNoteEvent) IsMergeRequestComment() bool {
+	return e.ObjectAttributes.NoteableType == "MergeRequest" && !e.ObjectAttributes.System && e.MergeRequest != nil
+}
+
+// IsThreadReply reports whether the comment replies in an existing thread
+func (e *GitLabNoteEvent) IsThreadReply() bool {
+	return e.ObjectAttributes.Type == "DiscussionNote" && e.ObjectAttributes.DiscussionID != ""
+}
+
+// FeedbackCommand is feedback on one numbered finding, given in an MR comment
+type FeedbackCommand struct {
+	Number int
+	Status string // accepted, fixed, wont_fix, false_positive
+	Reason string
+}
+
+var (
+	feedbackLine   = regexp.MustCompile(`(?i)^(accepted|accept|fixed|won'?t[ -]?fix|false[ -]?positive)\s+(#\d+(?:[\s,]+#\d+)*)\s*(?:[:\-–—]\s*)?(.*)$`)
+	feedbackNumber = regexp.MustCompile(`#(\d+)`)
+)
+
+// ParseFeedbackCommands extracts feedback from an MR comment, one command per line,
+// e.g. "accepted #1", "fixed #2, #3", "won't fix #4: reason", "false positive #5: reason".
+// Quoted lines are ignored.
+func ParseFeedbackCommands(note string) []FeedbackCommand {
+	var commands []FeedbackCommand
+	for _, line := range strings.Split(note, "\n") {
+		line = strings.TrimSpace(line)
+		m := feedbackLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		status := feedbackStatus(m[1])
+		for _, num := range feedbackNumber.FindAllStringSubmatch(m[2], -1) {
+			n, err := strconv.Atoi(num[1])
+			if err != nil || n == 0 {
+				continue
+			}
+			commands = append(commands, FeedbackCommand{Number: n, Status: status, Reason: strings.TrimSpace(m[3])})
+		}
+	}
+	return commands
+}
+
+func feedbackStatus(keyword string) string {
+	keyword = strings.ToLower(keyword)
+	switch {
+	case strings.HasPrefix(keyword, "accept"):
+		return model.FeedbackAccepted
+	case keyword == "fixed":
+		return model.FeedbackFixed
+	case strings.HasPrefix(keyword, "false"):
+		return model.FeedbackFalsePositive
+	default:
+		return model.FeedbackWontFix
+	}
+}
@@ -0,0 +1,58 @@
+package webhook
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/handsoff/handsoff/internal/model"
+)
+
+func TestParseFeedbackCommands(t *testing.T) {
+	tests := []struct {
+		name string
+		note string
+		want []FeedbackCommand
+	}{
+		{"Accepted", "accepted #1", []FeedbackCommand{{1, model.FeedbackAccepted, ""}}},
+		{"Several findings", "Fixed #2, #3", []FeedbackCommand{{2, model.FeedbackFixed, ""}, {3, model.FeedbackFixed, ""}}},
+		{"Won't fix with reason", "won't fix #4: legacy API", []FeedbackCommand{{4, model.FeedbackWontFix, "legacy API"}}},
+		{"False positive variants", "false-positive #5 - validated upstream\nFalse Positive #6",
+			[]FeedbackCommand{{5, model.FeedbackFalsePositive, "validated upstream"}, {6, model.FeedbackFalsePositive, ""}}},
+		{"Quoted and prose lines ignored", "> accepted #1\nThanks, I think this is fixed #2 now", nil},
+		{"Missing number", "accepted", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseFeedbackCommands(tt.note)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFeedbackCommands(%q) = %+v, want %+v", tt.note, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSlashCommands(t *testing.T) {
+	tests := []struct {
+		name string
+		note string
+		want []SlashCommand
+	}{
+		{"Review", "/handsoff review", []SlashCommand{{Name: CommandReview}}},
+		{"Explain", "Could you elaborate?\n/handsoff explain 2", []SlashCommand{{Name: CommandExplain, Number: 2}}},
+		{"Ignore with reason", "/HandsOff ignore #3: test fixture", []SlashCommand{{Name: CommandIgnore, Number: 3, Reason: "test fixture"}}},
+		{"Bare is help", "/handsoff", []SlashCommand{{Name: CommandHelp}}},
+		{"Unknown command", "/handsoff approve", []SlashCommand{{Name: "approve"}}},
+		{"Not at line start", "please run /handsoff review", nil},
+		{"Other bot", "/handsoffbot review", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseSlashCommands(tt.note)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSlashCommands(%q) = %+v, want %+v", tt.note, got, tt.want)
+			}
+		})
+	}
+}
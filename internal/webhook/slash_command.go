@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Slash commands developers can comment on a merge request
+const (
+	CommandReview  = "review"  // Re-run the review of the merge request
+	CommandExplain = "explain" // Explain finding #N in more depth
+	CommandIgnore  = "ignore"  // Dismiss finding #N
+	CommandHelp    = "help"    // List the commands
+)
+
+// SlashCommand is one "/handsoff <command>" line of an MR comment
+type SlashCommand struct {
+	Name   string // review, explain, ignore, help; anything else is unknown
+	Number int    // Finding the command refers to (explain, ignore), 0 if none
+	Reason string // Rest of the line after the finding number
+}
+
+var slashCommandLine = regexp.MustCompile(`(?i)^/handsoff(?:$|\s+(\S+)(?:\s+#?(\d+))?\s*(?:[:\-–—]\s*)?(.*)$)`)
+
+// ParseSlashCommands extracts the "/handsoff" commands of an MR comment, one per line,
+// e.g. "/handsoff review", "/handsoff explain 2", "/handsoff ignore #3: test fixture".
+// A bare "/handsoff" is read as help.
+func ParseSlashCommands(note string) []SlashCommand {
+	var commands []SlashCommand
+	for _, line := range strings.Split(note, "\n") {
+		m := slashCommandLine.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+
+		cmd := SlashCommand{Name: strings.ToLower(m[1]), Reason: strings.TrimSpace(m[3])}
+		if cmd.Name == "" {
+			cmd.Name = CommandHelp
+		}
+		if m[2] != "" {
+			cmd.Number, _ = strconv.Atoi(m[2])
+		}
+		commands = append(commands, cmd)
+	}
+	return commands
+}
+
+// SlashCommandUsage is the reply to "/handsoff help" and to unknown commands
+const SlashCommandUsage = "Available commands:\n" +
+	"- `/handsoff review` re-runs the review of this merge request\n" +
+	"- `/handsoff explain <n>` explains finding #n in more depth\n" +
+	"- `/handsoff ignore <n> [reason]` dismisses finding #n"
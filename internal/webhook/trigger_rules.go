@@ -0,0 +1,90 @@
+package webhook
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/handsoff/handsoff/internal/model"
+)
+
+// EvaluateTriggerRules checks a review-triggering merge request event against the
+// repository trigger rules. Returns an empty string if the review should run,
+// otherwise the reason it is skipped. author is the merge request author's username,
+// which the event only carries when the author triggered it.
+// The changed-lines cap needs the diff and is enforced by the review worker.
+func EvaluateTriggerRules(rules model.ReviewTriggerRules, e *GitLabMergeRequestEvent, author string) string {
+	if rules.SkipDrafts && e.IsDraft() {
+		return "merge request is a draft"
+	}
+
+	labels := e.GetLabels()
+	for _, excluded := range rules.ExcludeLabels {
+		if containsFold(labels, excluded) {
+			return fmt.Sprintf("merge request has excluded label %q", excluded)
+		}
+	}
+	if len(rules.IncludeLabels) > 0 && !anyContainsFold(labels, rules.IncludeLabels) {
+		return fmt.Sprintf("merge request has none of the required labels %s", strings.Join(rules.IncludeLabels, ", "))
+	}
+
+	if len(rules.TargetBranches) > 0 && !MatchAnyPattern(rules.TargetBranches, e.GetTargetBranch(), false) {
+		return fmt.Sprintf("target branch %q is not configured for review", e.GetTargetBranch())
+	}
+
+	if MatchAnyPattern(rules.ExcludedAuthors, author, true) {
+		return fmt.Sprintf("author %q is excluded from review", author)
+	}
+
+	return ""
+}
+
+// ValidateTriggerRules checks that all glob patterns in the rules are well-formed
+func ValidateTriggerRules(rules model.ReviewTriggerRules) error {
+	for _, pattern := range append(append([]string{}, rules.TargetBranches...), rules.ExcludedAuthors...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	if rules.MaxChangedLines < 0 {
+		return fmt.Errorf("max_changed_lines must not be negative")
+	}
+	return nil
+}
+
+// MatchAnyPattern reports whether value matches any of the glob patterns.
+// "*" does not match "/", so "release/*" matches "release/1.0" but not "release/1.0/hotfix".
+func MatchAnyPattern(patterns []string, value string, ignoreCase bool) bool {
+	if ignoreCase {
+		value = strings.ToLower(value)
+	}
+	for _, pattern := range patterns {
+		if ignoreCase {
+			pattern = strings.ToLower(pattern)
+		}
+		if matched, _ := path.Match(pattern, value); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold reports whether values contains target, ignoring case
+func containsFold(values []string, target string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// anyContainsFold reports whether values contains any of the targets, ignoring case
+func anyContainsFold(values []string, targets []string) bool {
+	for _, target := range targets {
+		if containsFold(values, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/handsoff/handsoff/internal/model"
+)
+
+func TestEvaluateTriggerRules(t *testing.T) {
+	newEvent := func(title, target, author string, draft bool, labels ...string) *GitLabMergeRequestEvent {
+		e := &GitLabMergeRequestEvent{
+			User: GitLabUser{Username: author},
+			ObjectAttributes: GitLabMergeRequestAttributes{
+				Title:        title,
+				TargetBranch: target,
+				Draft:        draft,
+			},
+		}
+		for _, label := range labels {
+			e.Labels = append(e.Labels, GitLabLabel{Title: label})
+		}
+		return e
+	}
+
+	tests := []struct {
+		name     string
+		rules    model.ReviewTriggerRules
+		event    *GitLabMergeRequestEvent
+		wantSkip bool
+	}{
+		{"Defaults review regular MR", model.DefaultReviewTriggerRules(), newEvent("Add feature", "main", "alice", false), false},
+		{"Defaults skip draft flag", model.DefaultReviewTriggerRules(), newEvent("Add feature", "main", "alice", true), true},
+		{"Defaults skip Draft: title", model.DefaultReviewTriggerRules(), newEvent("Draft: Add feature", "main", "alice", false), true},
+		{"Defaults skip WIP title", model.DefaultReviewTriggerRules(), newEvent("[WIP] Add feature", "main", "alice", false), true},
+		{"Drafts allowed", model.ReviewTriggerRules{}, newEvent("Draft: Add feature", "main", "alice", true), false},
+		{"Excluded label", model.ReviewTriggerRules{ExcludeLabels: []string{"no-review"}}, newEvent("x", "main", "alice", false, "No-Review"), true},
+		{"Required label present", model.ReviewTriggerRules{IncludeLabels: []string{"ai-review"}}, newEvent("x", "main", "alice", false, "ai-review"), false},
+		{"Required label missing", model.ReviewTriggerRules{IncludeLabels: []string{"ai-review"}}, newEvent("x", "main", "alice", false, "bug"), true},
+		{"Target branch glob match", model.ReviewTriggerRules{TargetBranches: []string{"main", "release/*"}}, newEvent("x", "release/1.2", "alice", false), false},
+		{"Target branch not configured", model.ReviewTriggerRules{TargetBranches: []string{"main"}}, newEvent("x", "develop", "alice", false), true},
+		{"Excluded bot author", model.ReviewTriggerRules{ExcludedAuthors: []string{"renovate", "*-bot"}}, newEvent("x", "main", "Deps-Bot", false), true},
+		{"Author not excluded", model.ReviewTriggerRules{ExcludedAuthors: []string{"renovate"}}, newEvent("x", "main", "alice", false), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := EvaluateTriggerRules(tt.rules, tt.event, tt.event.GetMRAuthor())
+			if (reason != "") != tt.wantSkip {
+				t.Errorf("EvaluateTriggerRules() = %q, wantSkip %v", reason, tt.wantSkip)
+			}
+		})
+	}
+}
+
+func TestValidateTriggerRules(t *testing.T) {
+	if err := ValidateTriggerRules(model.ReviewTriggerRules{TargetBranches: []string{"release/*"}}); err != nil {
+		t.Errorf("Expected valid rules, got %v", err)
+	}
+	if err := ValidateTriggerRules(model.ReviewTriggerRules{TargetBranches: []string{"release/["}}); err == nil {
+		t.Error("Expected error for malformed pattern")
+	}
+	if err := ValidateTriggerRules(model.ReviewTriggerRules{MaxChangedLines: -1}); err == nil {
+		t.Error("Expected error for negative line cap")
+	}
+}
@@ -3,10 +3,19 @@ package webhook
 import (
 	"crypto/hmac"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/hex"
+	"errors"
 	"fmt"
 )
 
+// Webhook validation errors
+var (
+	ErrMissingToken        = errors.New("missing X-Gitlab-Token header")
+	ErrSecretNotConfigured = errors.New("webhook secret not configured")
+	ErrInvalidToken        = errors.New("invalid webhook token")
+)
+
 // Validator handles webhook signature validation
 type Validator struct{}
 
@@ -21,16 +30,17 @@ func (v *Validator) ValidateGitLabSignature(payload []byte, receivedToken, expec
 	// GitLab uses simple token comparison (not HMAC)
 	// The webhook secret is sent as-is in X-Gitlab-Token header
 	if receivedToken == "" {
-		return fmt.Errorf("missing X-Gitlab-Token header")
+		return ErrMissingToken
 	}
 
+	// Unsigned webhooks are never accepted
 	if expectedSecret == "" {
-		// If no secret is configured, skip validation (for development)
-		return nil
+		return ErrSecretNotConfigured
 	}
 
-	if receivedToken != expectedSecret {
-		return fmt.Errorf("invalid webhook token")
+	// Constant-time comparison to avoid leaking the secret through timing
+	if subtle.ConstantTimeCompare([]byte(receivedToken), []byte(expectedSecret)) != 1 {
+		return ErrInvalidToken
 	}
 
 	return nil
@@ -0,0 +1,32 @@
+package webhook
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateGitLabSignature(t *testing.T) {
+	v := NewValidator()
+
+	tests := []struct {
+		name     string
+		token    string
+		secret   string
+		expected error
+	}{
+		{"Matching token", "s3cret", "s3cret", nil},
+		{"Wrong token", "guess", "s3cret", ErrInvalidToken},
+		{"Token prefix of secret", "s3c", "s3cret", ErrInvalidToken},
+		{"Missing token", "", "s3cret", ErrMissingToken},
+		{"No secret configured", "anything", "", ErrSecretNotConfigured},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateGitLabSignature(nil, tt.token, tt.secret)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("ValidateGitLabSignature() = %v, want %v", err, tt.expected)
+			}
+		})
+	}
+}
@@ -18,7 +18,9 @@ type Config struct {
 	Admin    AdminConfig
 	Log      LogConfig
 	Git      GitConfig
+	Analyzer AnalyzerConfig
 	CORS     CORSConfig
+	OIDC     OIDCConfig
 }
 
 // AppConfig contains application-level settings
@@ -45,14 +47,18 @@ type RedisConfig struct {
 
 // WorkerConfig contains async worker settings
 type WorkerConfig struct {
-	Concurrency int
+	Concurrency      int
+	TaskTimeout      time.Duration // Maximum run time of a single task; its context is cancelled afterwards
+	ShutdownTimeout  time.Duration // Time running tasks get to finish on shutdown before being requeued
+	ResponseCacheTTL time.Duration // How long LLM review responses are reused for an identical diff (0 disables)
 }
 
 // SecurityConfig contains security-related settings
 type SecurityConfig struct {
-	JWTSecret     string
-	JWTExpiry     time.Duration
-	EncryptionKey string
+	JWTSecret        string
+	JWTExpiry        time.Duration // Access token lifetime
+	JWTRefreshExpiry time.Duration // Refresh token lifetime
+	EncryptionKey    string
 }
 
 // AdminConfig contains default admin user settings
@@ -74,6 +80,26 @@ type GitConfig struct {
 	TempDir      string
 }
 
+// AnalyzerConfig contains static analyzer settings (analyzers run on a checkout made with GitConfig)
+type AnalyzerConfig struct {
+	Timeout      time.Duration // Maximum run time of each analyzer
+	SemgrepRules string        // Local semgrep rules file or directory (semgrep is skipped if empty)
+}
+
+// OIDCConfig contains OpenID Connect single sign-on settings
+type OIDCConfig struct {
+	Enabled           bool
+	IssuerURL         string
+	ClientID          string
+	ClientSecret      string
+	RedirectURL       string   // Callback URL registered at the provider, e.g. https://handsoff.example.com/api/auth/oidc/callback
+	Scopes            []string // Requested scopes (openid is always included)
+	UsernameClaim     string   // Claim used as HandsOff username
+	GroupsClaim       string   // Claim holding the user's groups
+	RoleMappings      []string // Group to project role mappings: "<group>=<project_id>:<role>"
+	PostLoginRedirect string   // Frontend URL receiving the issued tokens
+}
+
 // CORSConfig contains CORS settings
 type CORSConfig struct {
 	AllowedOrigins []string
@@ -103,12 +129,16 @@ func Load() (*Config, error) {
 		URL: getEnv("REDIS_URL", "redis://localhost:6379/0"),
 	},
 		Worker: WorkerConfig{
-			Concurrency: getEnvInt("WORKER_CONCURRENCY", 10),
+			Concurrency:      getEnvInt("WORKER_CONCURRENCY", 10),
+			TaskTimeout:      getEnvDuration("WORKER_TASK_TIMEOUT", 10*time.Minute),
+			ShutdownTimeout:  getEnvDuration("WORKER_SHUTDOWN_TIMEOUT", 30*time.Second),
+			ResponseCacheTTL: getEnvDuration("LLM_RESPONSE_CACHE_TTL", 24*time.Hour),
 		},
 		Security: SecurityConfig{
-			JWTSecret:     getEnv("JWT_SECRET", "change_this_to_a_random_secret_key"),
-			JWTExpiry:     getEnvDuration("JWT_EXPIRY", 24*time.Hour),
-			EncryptionKey: getEnv("ENCRYPTION_KEY", "CHANGE_THIS_TO_BASE64_ENCODED_32_BYTES_KEY"),
+			JWTSecret:        getEnv("JWT_SECRET", "change_this_to_a_random_secret_key"),
+			JWTExpiry:        getEnvDuration("JWT_EXPIRY", 15*time.Minute),
+			JWTRefreshExpiry: getEnvDuration("JWT_REFRESH_EXPIRY", 7*24*time.Hour),
+			EncryptionKey:    getEnv("ENCRYPTION_KEY", "CHANGE_THIS_TO_BASE64_ENCODED_32_BYTES_KEY"),
 		},
 		Admin: AdminConfig{
 			InitialPassword: getEnv("ADMIN_INITIAL_PASSWORD", "admin123"),
@@ -123,9 +153,25 @@ func Load() (*Config, error) {
 			CloneTimeout: getEnvDuration("GIT_CLONE_TIMEOUT", 300*time.Second),
 			TempDir:      getEnv("GIT_TEMP_DIR", "./temp/git"),
 		},
+		Analyzer: AnalyzerConfig{
+			Timeout:      getEnvDuration("ANALYZER_TIMEOUT", 3*time.Minute),
+			SemgrepRules: getEnv("SEMGREP_RULES", ""),
+		},
 		CORS: CORSConfig{
 			AllowedOrigins: getEnvSlice("CORS_ALLOWED_ORIGINS", []string{"http://localhost:3000"}),
 		},
+		OIDC: OIDCConfig{
+			Enabled:           getEnvBool("OIDC_ENABLED", false),
+			IssuerURL:         getEnv("OIDC_ISSUER_URL", ""),
+			ClientID:          getEnv("OIDC_CLIENT_ID", ""),
+			ClientSecret:      getEnv("OIDC_CLIENT_SECRET", ""),
+			RedirectURL:       getEnv("OIDC_REDIRECT_URL", ""),
+			Scopes:            getEnvSlice("OIDC_SCOPES", []string{"profile", "email", "groups"}),
+			UsernameClaim:     getEnv("OIDC_USERNAME_CLAIM", "preferred_username"),
+			GroupsClaim:       getEnv("OIDC_GROUPS_CLAIM", "groups"),
+			RoleMappings:      getEnvSlice("OIDC_ROLE_MAPPINGS", nil),
+			PostLoginRedirect: getEnv("OIDC_POST_LOGIN_REDIRECT", "/login"),
+		},
 	}
 
 	// Validate configuration
@@ -146,6 +192,10 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("ENCRYPTION_KEY must be changed from default value")
 	}
 
+	if c.OIDC.Enabled && (c.OIDC.IssuerURL == "" || c.OIDC.ClientID == "" || c.OIDC.RedirectURL == "") {
+		return fmt.Errorf("OIDC_ISSUER_URL, OIDC_CLIENT_ID and OIDC_REDIRECT_URL are required when OIDC is enabled")
+	}
+
 	if c.Database.Type != "sqlite" && c.Database.Type != "mysql" && c.Database.Type != "postgres" {
 		return fmt.Errorf("unsupported database type: %s", c.Database.Type)
 	}
@@ -168,6 +218,13 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvBool(key string, defaultValue bool) bool {
+	if viper.IsSet(key) {
+		return viper.GetBool(key)
+	}
+	return defaultValue
+}
+
 func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	if viper.IsSet(key) {
 		return viper.GetDuration(key)
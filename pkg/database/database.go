@@ -54,10 +54,11 @@ func New(cfg config.DatabaseConfig) (*gorm.DB, error) {
 
 // AutoMigrate runs automatic migration for all models
 func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	err := db.AutoMigrate(
 		&model.User{},
 		&model.Project{},
 		&model.UserProjectPreference{},
+		&model.ProjectMember{}, // Project access for non-owners (e.g. OIDC groups)
 		&model.GitPlatformConfig{},
 		&model.Repository{},
 		&model.LLMProvider{},
@@ -65,6 +66,28 @@ func AutoMigrate(db *gorm.DB) error {
 		&model.WebhookEvent{}, // Webhook event records
 		&model.ReviewResult{},
 		&model.FixSuggestion{},
-		&model.LLMUsageLog{},  // LLM API usage logs for token tracking
+		&model.LLMUsageLog{},      // LLM API usage logs for token tracking
+		&model.RefreshToken{},     // Rotating refresh tokens (hashed)
+		&model.RevokedToken{},     // Access token revocation list
+		&model.APIToken{},         // Scoped API tokens for CI and scripts
+		&model.Budget{},           // Monthly token/cost budgets
+		&model.ModelPrice{},       // Model pricing catalogue for usage costs
+		&model.LLMResponseCache{}, // Reusable LLM review responses
+		&model.RedactionRule{},    // Project-specific secret patterns
+		&model.ReviewerProfile{},  // Specialized review passes
+		&model.GateWaiver{},       // Audited quality gate overrides
 	)
+	if err != nil {
+		return err
+	}
+
+	// idx_repo_commit was unique on commit_sha alone, which rejects the same commit
+	// delivered for two repositories (forks); replaced by idx_webhook_repo_commit
+	if db.Migrator().HasIndex(&model.WebhookEvent{}, "idx_repo_commit") {
+		if err := db.Migrator().DropIndex(&model.WebhookEvent{}, "idx_repo_commit"); err != nil {
+			return fmt.Errorf("failed to drop legacy webhook index: %w", err)
+		}
+	}
+
+	return nil
 }
@@ -1,6 +1,8 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"time"
 
@@ -30,13 +32,26 @@ func NewGenerator(secret string, expiry time.Duration) *Generator {
 
 // Generate creates a new JWT token
 func (g *Generator) Generate(userID uint, username string) (string, error) {
+	return g.GenerateIssuedAt(userID, username, time.Now())
+}
+
+// GenerateIssuedAt creates a new JWT token with the given "iat" claim, which may be
+// later than now so the token is told apart from tokens revoked in the same second.
+// Expiry and "nbf" still count from now.
+func (g *Generator) GenerateIssuedAt(userID uint, username string, issuedAt time.Time) (string, error) {
+	tokenID, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+
 	now := time.Now()
 	claims := Claims{
 		UserID:   userID,
 		Username: username,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID, // jti, used for revocation
 			ExpiresAt: jwt.NewNumericDate(now.Add(g.expiry)),
-			IssuedAt:  jwt.NewNumericDate(now),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
 			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
@@ -45,6 +60,20 @@ func (g *Generator) Generate(userID uint, username string) (string, error) {
 	return token.SignedString(g.secret)
 }
 
+// Expiry returns the lifetime of generated tokens
+func (g *Generator) Expiry() time.Duration {
+	return g.expiry
+}
+
+// newTokenID generates a random JWT ID
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
 // Validate verifies and parses a JWT token
 func (g *Generator) Validate(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
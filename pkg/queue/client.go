@@ -1,5 +1,7 @@
 package queue
+
 import (
+	"errors"
 	"fmt"
 
 	"github.com/handsoff/handsoff/pkg/config"
@@ -9,6 +11,7 @@ import (
 // Client wraps asynq.Client
 type Client struct {
 	*asynq.Client
+	Inspector *asynq.Inspector // Queue inspection and task cancellation
 }
 
 // NewClient creates a new queue client
@@ -17,7 +20,38 @@ func NewClient(cfg config.RedisConfig) *Client {
 	if err != nil {
 		panic(fmt.Sprintf("invalid Redis URL: %v", err))
 	}
-	
+
 	client := asynq.NewClient(opt)
-	return &Client{Client: client}
+	return &Client{
+		Client:    client,
+		Inspector: asynq.NewInspector(opt),
+	}
+}
+
+// CancelTask removes a task that has not started yet, or signals cancellation to a
+// running one. Tasks that no longer exist are ignored.
+func (c *Client) CancelTask(queue, taskID string) error {
+	info, err := c.Inspector.GetTaskInfo(queue, taskID)
+	if errors.Is(err, asynq.ErrTaskNotFound) || errors.Is(err, asynq.ErrQueueNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to inspect task: %w", err)
+	}
+
+	if info.State == asynq.TaskStateActive {
+		return c.Inspector.CancelProcessing(taskID)
+	}
+	if err := c.Inspector.DeleteTask(queue, taskID); err != nil && !errors.Is(err, asynq.ErrTaskNotFound) {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+	return nil
+}
+
+// Close closes the client and inspector connections
+func (c *Client) Close() error {
+	if err := c.Inspector.Close(); err != nil {
+		return err
+	}
+	return c.Client.Close()
 }
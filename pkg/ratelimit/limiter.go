@@ -0,0 +1,177 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/handsoff/handsoff/pkg/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix namespaces limiter keys in the Redis database shared with the task queue
+const keyPrefix = "handsoff:ratelimit:"
+
+// Limits configures the per-minute buckets of a key. A zero limit is unlimited.
+type Limits struct {
+	RequestsPerMinute int
+	TokensPerMinute   int
+}
+
+// Enabled reports whether any limit is configured
+func (l Limits) Enabled() bool {
+	return l.RequestsPerMinute > 0 || l.TokensPerMinute > 0
+}
+
+// Script modes
+const (
+	modeReserve = "reserve" // Take one request and the tokens, or report the wait
+	modePeek    = "peek"    // Report the wait without taking anything
+	modeCharge  = "charge"  // Adjust the token bucket unconditionally (may go negative)
+)
+
+// bucketScript implements two token buckets (requests and tokens per minute) plus a
+// pause key set from provider Retry-After responses. Both buckets are checked and
+// updated atomically, so a request never takes from one bucket when the other is empty.
+// Returns the number of milliseconds to wait (0 when the request may proceed).
+//
+// KEYS: requests bucket, tokens bucket, pause key
+// ARGV: requests per minute, tokens per minute, token cost, mode
+var bucketScript = redis.NewScript(`
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local rpm = tonumber(ARGV[1])
+local tpm = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+local mode = ARGV[4]
+
+local function load(key, capacity)
+  local s = redis.call("HMGET", key, "tokens", "ts")
+  local tokens = tonumber(s[1]) or capacity
+  local ts = tonumber(s[2]) or now
+  return math.min(capacity, tokens + math.max(0, now - ts) * capacity / 60000)
+end
+
+local function store(key, capacity, tokens)
+  redis.call("HSET", key, "tokens", tostring(tokens), "ts", now)
+  redis.call("PEXPIRE", key, math.ceil((capacity - tokens) * 60000 / capacity) + 1000)
+end
+
+if mode == "charge" then
+  if tpm > 0 then
+    store(KEYS[2], tpm, load(KEYS[2], tpm) - cost)
+  end
+  return 0
+end
+
+local paused = redis.call("PTTL", KEYS[3])
+if paused > 0 then
+  return paused
+end
+
+local wait = 0
+local requests, tokens
+if rpm > 0 then
+  requests = load(KEYS[1], rpm)
+  if requests < 1 then
+    wait = math.max(wait, math.ceil((1 - requests) * 60000 / rpm))
+  end
+end
+if tpm > 0 then
+  tokens = load(KEYS[2], tpm)
+  local need = math.min(cost, tpm)
+  if tokens < need then
+    wait = math.max(wait, math.ceil((need - tokens) * 60000 / tpm))
+  end
+end
+
+if wait > 0 or mode == "peek" then
+  return wait
+end
+if rpm > 0 then
+  store(KEYS[1], rpm, requests - 1)
+end
+if tpm > 0 then
+  store(KEYS[2], tpm, tokens - cost)
+end
+return 0
+`)
+
+// pauseScript extends the pause key to at least ARGV[1] milliseconds
+var pauseScript = redis.NewScript(`
+local ttl = redis.call("PTTL", KEYS[1])
+if ttl < tonumber(ARGV[1]) then
+  redis.call("SET", KEYS[1], "1", "PX", ARGV[1])
+end
+return 0
+`)
+
+// Limiter is a Redis-backed token bucket rate limiter.
+// State lives in Redis, so every server and worker instance shares the same budget.
+type Limiter struct {
+	rdb *redis.Client
+}
+
+// NewLimiter creates a limiter on the configured Redis
+func NewLimiter(cfg config.RedisConfig) *Limiter {
+	opt, err := redis.ParseURL(cfg.URL)
+	if err != nil {
+		panic(fmt.Sprintf("invalid Redis URL: %v", err))
+	}
+
+	return &Limiter{rdb: redis.NewClient(opt)}
+}
+
+// Reserve takes one request and the given number of tokens from the key's buckets.
+// When the budget is exhausted (or the key is paused) nothing is taken and the time
+// to wait before trying again is returned.
+func (l *Limiter) Reserve(ctx context.Context, key string, limits Limits, tokens int) (time.Duration, error) {
+	return l.run(ctx, key, limits, tokens, modeReserve)
+}
+
+// Wait reports how long a request of the given size would have to wait, without taking anything
+func (l *Limiter) Wait(ctx context.Context, key string, limits Limits, tokens int) (time.Duration, error) {
+	return l.run(ctx, key, limits, tokens, modePeek)
+}
+
+// Charge adjusts the key's token bucket once the real usage is known.
+// A negative amount returns tokens that were reserved but not used.
+func (l *Limiter) Charge(ctx context.Context, key string, limits Limits, tokens int) error {
+	if limits.TokensPerMinute <= 0 || tokens == 0 {
+		return nil
+	}
+	_, err := l.run(ctx, key, limits, tokens, modeCharge)
+	return err
+}
+
+// Pause blocks all requests of the key for at least d (e.g. from a provider's Retry-After)
+func (l *Limiter) Pause(ctx context.Context, key string, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	return pauseScript.Run(ctx, l.rdb, []string{pauseKey(key)}, d.Milliseconds()).Err()
+}
+
+// Close closes the Redis connection
+func (l *Limiter) Close() error {
+	return l.rdb.Close()
+}
+
+func (l *Limiter) run(ctx context.Context, key string, limits Limits, tokens int, mode string) (time.Duration, error) {
+	keys := []string{
+		keyPrefix + key + ":rpm",
+		keyPrefix + key + ":tpm",
+		pauseKey(key),
+	}
+
+	wait, err := bucketScript.Run(ctx, l.rdb, keys,
+		limits.RequestsPerMinute, limits.TokensPerMinute, tokens, mode).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("rate limiter: %w", err)
+	}
+	return time.Duration(wait) * time.Millisecond, nil
+}
+
+func pauseKey(key string) string {
+	return keyPrefix + key + ":pause"
+}
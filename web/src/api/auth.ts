@@ -9,7 +9,14 @@ export const authApi = {
 
   // Logout
   logout: () => {
-    return request.post('/auth/logout');
+    return request.post('/auth/logout', {
+      refresh_token: localStorage.getItem('refresh_token') || undefined,
+    });
+  },
+
+  // Get single sign-on availability
+  getOIDCConfig: () => {
+    return request.get<{ enabled: boolean }>('/auth/oidc/config');
   },
 
   // Get current user
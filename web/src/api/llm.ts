@@ -1,5 +1,5 @@
 import request from "./request";
-import type { LLMProvider } from "../types";
+import type { LLMProvider, ModelPrice, ReviewerProfile } from "../types";
 
 export const llmApi = {
   // Provider APIs
@@ -53,4 +53,45 @@ export const llmApi = {
       }
     );
   },
+
+  // Model pricing APIs
+  listPrices: (providerId?: number) => {
+    return request.get<ModelPrice[]>("/llm/prices", {
+      params: providerId ? { llm_provider_id: providerId } : undefined,
+    });
+  },
+
+  createPrice: (data: Omit<ModelPrice, "id" | "project_id">) => {
+    return request.post<ModelPrice>("/llm/prices", data);
+  },
+
+  updatePrice: (id: number, data: Omit<ModelPrice, "id" | "project_id">) => {
+    return request.put<ModelPrice>(`/llm/prices/${id}`, data);
+  },
+
+  deletePrice: (id: number) => {
+    return request.delete<{ message: string }>(`/llm/prices/${id}`);
+  },
+
+  // Reviewer profile APIs
+  listReviewerProfiles: () => {
+    return request.get<ReviewerProfile[]>("/reviewer-profiles");
+  },
+
+  createReviewerProfile: (
+    data: Omit<ReviewerProfile, "id" | "project_id" | "llm_provider">
+  ) => {
+    return request.post<ReviewerProfile>("/reviewer-profiles", data);
+  },
+
+  updateReviewerProfile: (
+    id: number,
+    data: Omit<ReviewerProfile, "id" | "project_id" | "llm_provider">
+  ) => {
+    return request.put<ReviewerProfile>(`/reviewer-profiles/${id}`, data);
+  },
+
+  deleteReviewerProfile: (id: number) => {
+    return request.delete<{ message: string }>(`/reviewer-profiles/${id}`);
+  },
 };
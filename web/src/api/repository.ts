@@ -1,5 +1,12 @@
 import request from "./request";
-import type { Repository, GitLabRepository } from "../types";
+import type {
+  Repository,
+  GitLabRepository,
+  ReviewTriggerRules,
+  QualityGate,
+  LabelRule,
+  AnalyzerName,
+} from "../types";
 
 export const repositoryApi = {
   // List repositories from GitLab
@@ -53,6 +60,80 @@ export const repositoryApi = {
     });
   },
 
+  // Update review trigger rules for repository
+  updateTriggerRules: (id: number, rules: ReviewTriggerRules) => {
+    return request.put<{ message: string; trigger_rules: ReviewTriggerRules }>(
+      `/repositories/${id}/trigger-rules`,
+      rules
+    );
+  },
+
+  updateResponseCache: (id: number, disabled: boolean) => {
+    return request.put<{ message: string; response_cache_disabled: boolean }>(
+      `/repositories/${id}/response-cache`,
+      { disabled }
+    );
+  },
+
+  // Set the second provider that verifies high and critical findings (null = off)
+  updateConsensus: (
+    id: number,
+    verifierLLMProviderID: number | null,
+    rejectedFindingAction: "drop" | "downgrade"
+  ) => {
+    return request.put<{ message: string }>(`/repositories/${id}/consensus`, {
+      verifier_llm_provider_id: verifierLLMProviderID,
+      rejected_finding_action: rejectedFindingAction,
+    });
+  },
+
+  // Update the quality gate reported as a commit status on the MR head
+  updateQualityGate: (id: number, gate: QualityGate) => {
+    return request.put<{ message: string; quality_gate: QualityGate }>(
+      `/repositories/${id}/quality-gate`,
+      gate
+    );
+  },
+
+  // Update the rules that keep MR labels in sync with reviews ([] = off)
+  updateLabelRules: (id: number, labelRules: LabelRule[]) => {
+    return request.put<{ message: string; label_rules: LabelRule[] }>(
+      `/repositories/${id}/label-rules`,
+      { label_rules: labelRules }
+    );
+  },
+
+  // Set how generated MR summaries are posted ("" = off; "description"
+  // fills an empty MR description, otherwise posts a comment)
+  updateMRSummary: (id: number, mode: "" | "comment" | "description") => {
+    return request.put<{ message: string; mr_summary_mode: string }>(
+      `/repositories/${id}/mr-summary`,
+      { mode }
+    );
+  },
+
+  // Set how human reviewers are recommended ("" = off; "assign" also sets
+  // them on merge requests that have no reviewers yet)
+  updateReviewers: (
+    id: number,
+    mode: "" | "suggest" | "assign",
+    maxReviewers: number
+  ) => {
+    return request.put<{
+      message: string;
+      reviewer_mode: string;
+      max_reviewers: number;
+    }>(`/repositories/${id}/reviewers`, { mode, max_reviewers: maxReviewers });
+  },
+
+  // Set the static analyzers run on a checkout before the LLM review ([] = off)
+  updateAnalyzers: (id: number, analyzers: AnalyzerName[]) => {
+    return request.put<{ message: string; analyzers: AnalyzerName[] }>(
+      `/repositories/${id}/analyzers`,
+      { analyzers }
+    );
+  },
+
   // Delete repository
   delete: (id: number) => {
     return request.delete<{ message: string }>(`/repositories/${id}`);
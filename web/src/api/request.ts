@@ -2,6 +2,7 @@ import axios, { AxiosError, type InternalAxiosRequestConfig } from "axios";
 import { message } from "antd";
 import { useAuthStore } from "../stores/auth";
 import { ROUTES } from "../constants/routes";
+import type { LoginResponse } from "../types";
 
 // Create axios instance
 const request = axios.create({
@@ -23,12 +24,59 @@ request.interceptors.request.use(
   }
 );
 
+// Single in-flight refresh shared by concurrent 401 responses
+let refreshPromise: Promise<string> | null = null;
+
+const refreshAccessToken = (): Promise<string> => {
+  if (!refreshPromise) {
+    const refreshToken = localStorage.getItem("refresh_token");
+    refreshPromise = (
+      refreshToken
+        ? axios
+            .post<LoginResponse>(`${request.defaults.baseURL}/auth/refresh`, {
+              refresh_token: refreshToken,
+            })
+            .then((res) => {
+              const { token, refresh_token, user } = res.data;
+              useAuthStore.getState().setAuth(token, refresh_token, user);
+              return token;
+            })
+        : Promise.reject(new Error("No refresh token"))
+    ).finally(() => {
+      refreshPromise = null;
+    });
+  }
+  return refreshPromise;
+};
+
 // Response interceptor - handle errors
 request.interceptors.response.use(
   (response) => {
     return response;
   },
-  (error: AxiosError<{ error: string }>) => {
+  async (error: AxiosError<{ error: string }>) => {
+    const original = error.config as
+      | (InternalAxiosRequestConfig & { _retry?: boolean })
+      | undefined;
+
+    // Access token expired: rotate the refresh token once and retry
+    if (
+      error.response?.status === 401 &&
+      original &&
+      !original._retry &&
+      !original.url?.startsWith("/auth/") &&
+      localStorage.getItem("refresh_token")
+    ) {
+      original._retry = true;
+      try {
+        const token = await refreshAccessToken();
+        original.headers.Authorization = `Bearer ${token}`;
+        return request(original);
+      } catch {
+        // Fall through to the regular 401 handling
+      }
+    }
+
     if (error.response) {
       const { status, data } = error.response;
 
@@ -1,5 +1,5 @@
 import request from './request';
-import type { SystemWebhookConfig } from '../types';
+import type { RedactionRule, SystemWebhookConfig } from '../types';
 
 export const systemApi = {
   // Get system webhook configuration
@@ -11,4 +11,35 @@ export const systemApi = {
   updateWebhookConfig: (config: SystemWebhookConfig) => {
     return request.put<{ message: string }>('/system/webhook', config);
   },
+
+  // Prompt template of generated MR summaries (must contain {{.Diff}})
+  getMRSummaryPrompt: () => {
+    return request.get<{ prompt_template: string }>('/system/mr-summary-prompt');
+  },
+
+  updateMRSummaryPrompt: (promptTemplate: string) => {
+    return request.put<{ message: string }>('/system/mr-summary-prompt', {
+      prompt_template: promptTemplate,
+    });
+  },
+
+  // Secret redaction rules (custom rules apply on top of the builtin ones)
+  listRedactionRules: () => {
+    return request.get<{
+      rules: RedactionRule[];
+      builtin: Array<{ name: string; label: string }>;
+    }>('/redaction-rules');
+  },
+
+  createRedactionRule: (rule: Omit<RedactionRule, 'id' | 'project_id'>) => {
+    return request.post<RedactionRule>('/redaction-rules', rule);
+  },
+
+  updateRedactionRule: (id: number, rule: Omit<RedactionRule, 'id' | 'project_id'>) => {
+    return request.put<RedactionRule>(`/redaction-rules/${id}`, rule);
+  },
+
+  deleteRedactionRule: (id: number) => {
+    return request.delete<{ message: string }>(`/redaction-rules/${id}`);
+  },
 };
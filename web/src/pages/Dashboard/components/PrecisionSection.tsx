@@ -0,0 +1,55 @@
+import { Card, Col, Row, Table } from "antd";
+import type { PrecisionRow, PrecisionStats } from "../../../types";
+
+interface PrecisionSectionProps {
+  precision: PrecisionStats | null;
+}
+
+const columns = (title: string) => [
+  {
+    title,
+    dataIndex: "key",
+    key: "key",
+    render: (key: string) => key || "-",
+  },
+  { title: "Feedback", dataIndex: "total", key: "total" },
+  { title: "False Positive", dataIndex: "false_positive", key: "false_positive" },
+  {
+    title: "Precision",
+    dataIndex: "precision",
+    key: "precision",
+    render: (precision: number) => `${precision.toFixed(1)}%`,
+  },
+];
+
+const PrecisionTable = ({ title, rows }: { title: string; rows: PrecisionRow[] }) => (
+  <Table
+    size="small"
+    columns={columns(title)}
+    dataSource={rows}
+    rowKey="key"
+    pagination={false}
+  />
+);
+
+const PrecisionSection = ({ precision }: PrecisionSectionProps) => {
+  if (!precision) return null;
+
+  return (
+    <Card title="Finding Precision (developer feedback)" style={{ marginTop: 16 }}>
+      <Row gutter={[16, 16]}>
+        <Col xs={24} lg={8}>
+          <PrecisionTable title="Category" rows={precision.by_category} />
+        </Col>
+        <Col xs={24} lg={8}>
+          <PrecisionTable title="Model" rows={precision.by_model} />
+        </Col>
+        <Col xs={24} lg={8}>
+          <PrecisionTable title="Prompt Version" rows={precision.by_prompt_version} />
+        </Col>
+      </Row>
+    </Card>
+  );
+};
+
+export default PrecisionSection;
@@ -7,12 +7,28 @@ import {
 import ReactECharts from "echarts-for-react";
 import dayjs from "dayjs";
 import { formatTokens } from "../../../utils/statusConfig";
+import type { BudgetStatus, CostBreakdown } from "../../../types";
+
+const budgetStrokeColor: Record<BudgetStatus["state"], string> = {
+  ok: "#52c41a",
+  warning: "#faad14",
+  exceeded: "#ff4d4f",
+};
+
+const formatBudgetValue = (budget: BudgetStatus, value: number) =>
+  budget.metric === "cost"
+    ? `${value.toFixed(2)} ${budget.currency || ""}`.trim()
+    : formatTokens(value);
+
+const formatCost = (cost: number, currency: string) =>
+  currency ? `${cost.toFixed(2)} ${currency}` : "Unpriced";
 
 interface TokenUsageData {
   summary: {
     total_calls: number;
     successful_calls: number;
     failed_calls: number;
+    cached_calls: number;
     total_tokens: number;
     prompt_tokens: number;
     completion_tokens: number;
@@ -33,6 +49,8 @@ interface TokenUsageData {
     avg_duration_ms: number;
     success_rate: number;
   }>;
+  cost?: CostBreakdown;
+  budgets?: BudgetStatus[];
 }
 
 interface TokenUsageSectionProps {
@@ -222,6 +240,98 @@ const TokenUsageSection: React.FC<TokenUsageSectionProps> = ({
           </Card>
         </Col>
       </Row>
+
+      {tokenUsage?.cost && tokenUsage.cost.by_model.length > 0 && (
+        <Row gutter={[16, 16]} style={{ marginTop: 16 }}>
+          <Col xs={24} lg={12}>
+            <Card
+              title="Cost by Model"
+              extra={tokenUsage.cost.by_currency
+                .map((row) => formatCost(row.cost, row.currency))
+                .join(" + ")}
+            >
+              {tokenUsage.cost.by_model.map((row) => (
+                <div
+                  key={`${row.llm_provider_id}-${row.model_name}-${row.currency}`}
+                  style={{
+                    display: "flex",
+                    justifyContent: "space-between",
+                    padding: "4px 0",
+                  }}
+                >
+                  <span>
+                    {row.model_name}
+                    <span style={{ color: "#888", marginLeft: 8 }}>
+                      {formatTokens(row.total_tokens)} tokens
+                    </span>
+                  </span>
+                  <span style={{ fontWeight: 500 }}>
+                    {formatCost(row.cost, row.currency)}
+                  </span>
+                </div>
+              ))}
+            </Card>
+          </Col>
+          <Col xs={24} lg={12}>
+            <Card title="Cost by Repository">
+              {tokenUsage.cost.by_repository.map((row) => (
+                <div
+                  key={`${row.repository_id}-${row.currency}`}
+                  style={{
+                    display: "flex",
+                    justifyContent: "space-between",
+                    padding: "4px 0",
+                  }}
+                >
+                  <span>
+                    {row.repository_name || `Repository #${row.repository_id}`}
+                  </span>
+                  <span style={{ fontWeight: 500 }}>
+                    {formatCost(row.cost, row.currency)}
+                  </span>
+                </div>
+              ))}
+            </Card>
+          </Col>
+        </Row>
+      )}
+
+      {tokenUsage?.budgets && tokenUsage.budgets.length > 0 && (
+        <Row gutter={16} style={{ marginTop: 16 }}>
+          <Col span={24}>
+            <Card title="Monthly Budgets">
+              {tokenUsage.budgets.map((budget) => (
+                <div key={budget.id} style={{ marginBottom: 12 }}>
+                  <div
+                    style={{ display: "flex", justifyContent: "space-between" }}
+                  >
+                    <span>
+                      {budget.repository?.name ?? "Project"} ·{" "}
+                      {budget.metric === "cost" ? "Cost" : "Tokens"}
+                    </span>
+                    <span>
+                      {formatBudgetValue(budget, budget.used)}
+                      {budget.hard_limit > 0 &&
+                        ` / ${formatBudgetValue(budget, budget.hard_limit)}`}
+                    </span>
+                  </div>
+                  <Progress
+                    percent={Math.min(100, Math.round(budget.percent))}
+                    size="small"
+                    status={budget.state === "exceeded" ? "exception" : "normal"}
+                    strokeColor={budgetStrokeColor[budget.state]}
+                  />
+                  {budget.state === "exceeded" && (
+                    <div style={{ fontSize: 12, color: "#ff4d4f" }}>
+                      Hard limit reached, reviews are skipped until next month
+                    </div>
+                  )}
+                </div>
+              ))}
+            </Card>
+          </Col>
+        </Row>
+      )}
     </>
   );
 };
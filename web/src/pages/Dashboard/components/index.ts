@@ -2,3 +2,4 @@ export { default as StatisticsCards } from "./StatisticsCards";
 export { default as ReviewTrendChart } from "./ReviewTrendChart";
 export { default as IssueDistributionChart } from "./IssueDistributionChart";
 export { default as TokenUsageSection } from "./TokenUsageSection";
+export { default as PrecisionSection } from "./PrecisionSection";
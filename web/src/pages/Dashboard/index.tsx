@@ -9,8 +9,10 @@ import {
   StatisticsCards,
   ReviewTrendChart,
   TokenUsageSection,
+  PrecisionSection,
 } from "./components";
 import { renderStatusTag, getScoreColor } from "../../utils/statusConfig";
+import type { BudgetStatus, CostBreakdown, PrecisionStats } from "../../types";
 
 interface DashboardStats {
   total_reviews: number;
@@ -55,6 +57,7 @@ interface TokenUsageData {
     total_calls: number;
     successful_calls: number;
     failed_calls: number;
+    cached_calls: number;
     total_tokens: number;
     prompt_tokens: number;
     completion_tokens: number;
@@ -75,6 +78,8 @@ interface TokenUsageData {
     avg_duration_ms: number;
     success_rate: number;
   }>;
+  cost?: CostBreakdown;
+  budgets?: BudgetStatus[];
 }
 
 const Dashboard = () => {
@@ -83,6 +88,7 @@ const Dashboard = () => {
   const [recentReviews, setRecentReviews] = useState<ReviewRecord[]>([]);
   const [trends, setTrends] = useState<TrendData[]>([]);
   const [tokenUsage, setTokenUsage] = useState<TokenUsageData | null>(null);
+  const [precision, setPrecision] = useState<PrecisionStats | null>(null);
   const navigate = useNavigate();
 
   useEffect(() => {
@@ -92,17 +98,20 @@ const Dashboard = () => {
   const fetchDashboardData = async () => {
     setLoading(true);
     try {
-      const [statsRes, recentRes, trendsRes, tokenRes] = await Promise.all([
-        axios.get("/api/dashboard/statistics"),
-        axios.get("/api/dashboard/recent?limit=10"),
-        axios.get("/api/dashboard/trends?days=30"),
-        axios.get("/api/dashboard/token-usage?days=30"),
-      ]);
+      const [statsRes, recentRes, trendsRes, tokenRes, precisionRes] =
+        await Promise.all([
+          axios.get("/api/dashboard/statistics"),
+          axios.get("/api/dashboard/recent?limit=10"),
+          axios.get("/api/dashboard/trends?days=30"),
+          axios.get("/api/dashboard/token-usage?days=30"),
+          axios.get("/api/dashboard/precision?days=30"),
+        ]);
 
       setStats(statsRes.data);
       setRecentReviews(Array.isArray(recentRes.data) ? recentRes.data : []);
       setTrends(Array.isArray(trendsRes.data) ? trendsRes.data : []);
       setTokenUsage(tokenRes.data);
+      setPrecision(precisionRes.data);
     } catch (error) {
       message.error("Failed to load dashboard data");
       console.error(error);
@@ -293,6 +302,9 @@ const Dashboard = () => {
       {/* Token Usage Section Component */}
       <TokenUsageSection tokenUsage={tokenUsage} />
 
+      {/* Finding Precision from developer feedback */}
+      <PrecisionSection precision={precision} />
+
       {/* Recent Reviews Table */}
       <Card title="Recent Reviews" style={{ marginTop: 16 }}>
         <Table
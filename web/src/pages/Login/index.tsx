@@ -1,8 +1,9 @@
-import { useState } from "react";
+import { useEffect, useState } from "react";
 import { useNavigate } from "react-router-dom";
 import { Form, Input, Button, Card, message } from "antd";
 import { UserOutlined, LockOutlined } from "@ant-design/icons";
 import { authApi } from "../../api/auth";
+import request from "../../api/request";
 import { useAuthStore } from "../../stores/auth";
 import { ROUTES } from "../../constants/routes";
 import type { LoginRequest } from "../../types";
@@ -12,14 +13,52 @@ const Login = () => {
   const [loading, setLoading] = useState(false);
   const navigate = useNavigate();
   const setAuth = useAuthStore((state) => state.setAuth);
+  const [ssoEnabled, setSsoEnabled] = useState(false);
+
+  useEffect(() => {
+    authApi
+      .getOIDCConfig()
+      .then((response) => setSsoEnabled(response.data.enabled))
+      .catch(() => setSsoEnabled(false));
+  }, []);
+
+  // Single sign-on callback redirects back here with tokens in the URL fragment
+  useEffect(() => {
+    const params = new URLSearchParams(window.location.hash.slice(1));
+    if (!params.has("token") && !params.has("error")) {
+      return;
+    }
+    window.history.replaceState(null, "", window.location.pathname);
+
+    const error = params.get("error");
+    if (error) {
+      message.error(error);
+      return;
+    }
+
+    const token = params.get("token") as string;
+    const refreshToken = params.get("refresh_token") || "";
+    localStorage.setItem("token", token);
+    authApi
+      .getCurrentUser()
+      .then((response) => {
+        setAuth(token, refreshToken, response.data);
+        message.success("Login successful!");
+        navigate(ROUTES.HOME);
+      })
+      .catch((err) => {
+        localStorage.removeItem("token");
+        console.error("Single sign-on failed:", err);
+      });
+  }, [navigate, setAuth]);
 
   const onFinish = async (values: LoginRequest) => {
     setLoading(true);
     try {
       const response = await authApi.login(values);
-      const { token, user } = response.data;
+      const { token, refresh_token, user } = response.data;
 
-      setAuth(token, user);
+      setAuth(token, refresh_token, user);
       message.success("Login successful!");
       navigate(ROUTES.HOME);
     } catch (error) {
@@ -69,6 +108,18 @@ const Login = () => {
           </Form.Item>
         </Form>
 
+        {ssoEnabled && (
+          <Button
+            block
+            size="large"
+            onClick={() => {
+              window.location.href = `${request.defaults.baseURL}/auth/oidc/login`;
+            }}
+          >
+            Sign in with SSO
+          </Button>
+        )}
+
         <div className="login-hint">
           <p>Default credentials:</p>
           <p>
@@ -15,6 +15,9 @@ import {
   Select,
   Alert,
   Divider,
+  Modal,
+  Input,
+  message,
 } from "antd";
 import { useParams, useNavigate } from "react-router-dom";
 import {
@@ -52,8 +55,28 @@ interface FixSuggestion {
   description: string;
   suggestion: string;
   code_snippet?: string;
+  verdict?: "" | "confirmed" | "rejected" | "downgraded"; // Consensus mode
+  verdict_confidence?: number;
+  verdict_reason?: string;
+  number?: number; // #N in the MR comment
+  feedback_status?: FeedbackStatus;
+  feedback_by?: string;
+  feedback_reason?: string;
+  occurrences?: number; // Reviews of the MR that reported it
+  resolved_at?: string; // No longer reported by the latest review
+  source?: "llm" | "analyzer" | "builtin"; // Who reported it
 }
 
+type FeedbackStatus = "" | "accepted" | "fixed" | "wont_fix" | "false_positive";
+
+const feedbackOptions: { value: FeedbackStatus; label: string }[] = [
+  { value: "", label: "No feedback" },
+  { value: "accepted", label: "Accepted" },
+  { value: "fixed", label: "Fixed" },
+  { value: "wont_fix", label: "Won't fix" },
+  { value: "false_positive", label: "False positive" },
+];
+
 interface ReviewData {
   id: number;
   repository?: { id: number; name: string; full_path: string };
@@ -70,10 +93,16 @@ interface ReviewData {
   high_issues_count: number;
   medium_issues_count: number;
   low_issues_count: number;
+  new_issues_count: number;
+  persisting_issues_count: number;
+  resolved_issues_count: number;
   summary: string;
   fix_suggestions: FixSuggestion[];
   comment_posted: boolean;
   comment_url?: string;
+  gate_status?: "" | "passed" | "failed" | "waived"; // "" = no quality gate
+  gate_reason?: string;
+  suggested_reviewers?: string[] | null;
   prompt_tokens: number;
   completion_tokens: number;
   total_tokens: number;
@@ -91,6 +120,8 @@ const ReviewDetail = () => {
   const [loading, setLoading] = useState(true);
   const [review, setReview] = useState<ReviewData | null>(null);
   const [severityFilter, setSeverityFilter] = useState<SeverityFilter>("all");
+  const [waiveOpen, setWaiveOpen] = useState(false);
+  const [waiveReason, setWaiveReason] = useState("");
 
   useEffect(() => {
     fetchReviewDetail();
@@ -108,6 +139,40 @@ const ReviewDetail = () => {
     }
   };
 
+  const updateFeedback = async (
+    suggestion: FixSuggestion,
+    status: FeedbackStatus
+  ) => {
+    try {
+      await axios.put(`/api/suggestions/${suggestion.id}/feedback`, { status });
+      fetchReviewDetail();
+    } catch (error) {
+      console.error(error);
+    }
+  };
+
+  const waiveGate = async () => {
+    try {
+      await axios.post(`/api/reviews/${id}/gate/waive`, {
+        reason: waiveReason,
+      });
+      message.success("Quality gate waived");
+      setWaiveOpen(false);
+      setWaiveReason("");
+      fetchReviewDetail();
+    } catch (error: any) {
+      message.error(
+        error.response?.data?.error || "Failed to waive quality gate"
+      );
+    }
+  };
+
+  const gateColors: Record<string, string> = {
+    passed: "green",
+    failed: "red",
+    waived: "orange",
+  };
+
   const severityColors: Record<string, string> = {
     critical: "red",
     high: "orange",
@@ -170,7 +235,12 @@ const ReviewDetail = () => {
       dataIndex: "category",
       key: "category",
       width: 120,
-      render: (cat: string) => <Tag>{cat}</Tag>,
+      render: (cat: string, record: FixSuggestion) => (
+        <Space size={4}>
+          <Tag>{cat}</Tag>
+          {record.source === "analyzer" && <Tag color="purple">analyzer</Tag>}
+        </Space>
+      ),
     },
     {
       title: "Description",
@@ -178,6 +248,61 @@ const ReviewDetail = () => {
       key: "description",
       ellipsis: true,
     },
+    {
+      title: "Status",
+      key: "tracking",
+      width: 110,
+      render: (_: unknown, record: FixSuggestion) =>
+        record.resolved_at ? (
+          <Tooltip title={dayjs(record.resolved_at).format("YYYY-MM-DD HH:mm")}>
+            <Tag color="green">Resolved</Tag>
+          </Tooltip>
+        ) : (record.occurrences || 1) > 1 ? (
+          <Tag color="orange">Seen {record.occurrences}×</Tag>
+        ) : (
+          <Tag color="blue">New</Tag>
+        ),
+    },
+    {
+      title: "Verified",
+      dataIndex: "verdict",
+      key: "verdict",
+      width: 120,
+      render: (_: unknown, record: FixSuggestion) =>
+        record.verdict ? (
+          <Tooltip title={record.verdict_reason}>
+            <Tag color={record.verdict === "confirmed" ? "green" : "default"}>
+              {record.verdict} {Math.round((record.verdict_confidence || 0) * 100)}%
+            </Tag>
+          </Tooltip>
+        ) : (
+          <Text type="secondary">-</Text>
+        ),
+    },
+    {
+      title: "Feedback",
+      key: "feedback",
+      width: 150,
+      render: (_: unknown, record: FixSuggestion) => (
+        <Tooltip
+          title={
+            record.feedback_by
+              ? `${record.feedback_by}: ${record.feedback_reason || ""}`
+              : undefined
+          }
+        >
+          <Select
+            size="small"
+            style={{ width: 140 }}
+            value={record.feedback_status || ""}
+            options={feedbackOptions}
+            onChange={(status: FeedbackStatus) =>
+              updateFeedback(record, status)
+            }
+          />
+        </Tooltip>
+      ),
+    },
   ];
 
   if (loading) {
@@ -317,9 +442,59 @@ const ReviewDetail = () => {
               ? dayjs(review.reviewed_at).format("YYYY-MM-DD HH:mm:ss")
               : "-"}
           </Descriptions.Item>
+          <Descriptions.Item label="Since Last Review">
+            {review.new_issues_count || 0} new,{" "}
+            {review.persisting_issues_count || 0} persisting,{" "}
+            {review.resolved_issues_count || 0} resolved
+          </Descriptions.Item>
+          {review.gate_status && (
+            <Descriptions.Item label="Quality Gate" span={2}>
+              <Space>
+                <Tag color={gateColors[review.gate_status]}>
+                  {review.gate_status.toUpperCase()}
+                </Tag>
+                {review.gate_reason && (
+                  <Text type="secondary">{review.gate_reason}</Text>
+                )}
+                {review.gate_status === "failed" && (
+                  <Button size="small" onClick={() => setWaiveOpen(true)}>
+                    Waive
+                  </Button>
+                )}
+              </Space>
+            </Descriptions.Item>
+          )}
+          {review.suggested_reviewers &&
+            review.suggested_reviewers.length > 0 && (
+              <Descriptions.Item label="Suggested Reviewers" span={2}>
+                {review.suggested_reviewers.map((username) => (
+                  <Tag key={username}>@{username}</Tag>
+                ))}
+              </Descriptions.Item>
+            )}
         </Descriptions>
       </Card>
 
+      <Modal
+        title="Waive Quality Gate"
+        open={waiveOpen}
+        okText="Waive"
+        okButtonProps={{ disabled: !waiveReason.trim() }}
+        onOk={waiveGate}
+        onCancel={() => setWaiveOpen(false)}
+      >
+        <Paragraph type="secondary">
+          The commit status is set to success for this commit only. The
+          waiver is recorded with your name and reason.
+        </Paragraph>
+        <Input.TextArea
+          rows={3}
+          placeholder="Why can this merge request be merged anyway?"
+          value={waiveReason}
+          onChange={(e) => setWaiveReason(e.target.value)}
+        />
+      </Modal>
+
       {/* Statistics Row */}
       <Row gutter={16} style={{ marginTop: 16 }}>
         {/* Issues Summary */}
@@ -5,6 +5,7 @@ import {
   Modal,
   Form,
   Input,
+  InputNumber,
   Select,
   message,
   Space,
@@ -30,6 +31,8 @@ type LLMProviderFormValues = Omit<
   | "last_tested_at"
   | "last_test_status"
   | "last_test_message"
+  | "throttled"
+  | "throttled_until"
   | "api_key"
   | "model"
 > & {
@@ -113,6 +116,8 @@ const LLMProviders = () => {
     form.resetFields();
     form.setFieldsValue({
       is_active: true,
+      rpm_limit: 0,
+      tpm_limit: 0,
     });
     setAvailableModels([]);
     setSelectedModel(null); // 重置选中的模型
@@ -239,12 +244,29 @@ const LLMProviders = () => {
       title: "状态",
       dataIndex: "is_active",
       key: "is_active",
-      render: (active: boolean) => (
-        <Tag color={active ? "success" : "default"}>
-          {active ? "启用" : "禁用"}
-        </Tag>
+      render: (active: boolean, record: LLMProvider) => (
+        <Space size={4}>
+          <Tag color={active ? "success" : "default"}>
+            {active ? "启用" : "禁用"}
+          </Tag>
+          {record.throttled && (
+            <Tag color="warning">
+              限流中
+              {record.throttled_until &&
+                ` 至 ${new Date(record.throttled_until).toLocaleTimeString()}`}
+            </Tag>
+          )}
+        </Space>
       ),
     },
+    {
+      title: "速率限制",
+      key: "rate_limit",
+      render: (_: any, record: LLMProvider) =>
+        record.rpm_limit || record.tpm_limit
+          ? `${record.rpm_limit || "∞"} RPM / ${record.tpm_limit || "∞"} TPM`
+          : "不限",
+    },
     {
       title: "测试状态",
       dataIndex: "last_test_status",
@@ -373,6 +395,23 @@ const LLMProviders = () => {
             />
           </Form.Item>
 
+          <Space style={{ width: "100%" }} size="large">
+            <Form.Item
+              label="每分钟请求数 (RPM)"
+              name="rpm_limit"
+              tooltip="所有 Worker 共享，0 表示不限制"
+            >
+              <InputNumber min={0} precision={0} style={{ width: 180 }} />
+            </Form.Item>
+            <Form.Item
+              label="每分钟 Token 数 (TPM)"
+              name="tpm_limit"
+              tooltip="所有 Worker 共享，0 表示不限制"
+            >
+              <InputNumber min={0} precision={0} style={{ width: 180 }} />
+            </Form.Item>
+          </Space>
+
           {/* 操作按钮：获取模型 + 测试 */}
           <Form.Item>
             <Space style={{ width: "100%" }}>
@@ -4,8 +4,9 @@ import type { User } from '../types';
 
 interface AuthState {
   token: string | null;
+  refreshToken: string | null;
   user: User | null;
-  setAuth: (token: string, user: User) => void;
+  setAuth: (token: string, refreshToken: string, user: User) => void;
   clearAuth: () => void;
   isAuthenticated: () => boolean;
 }
@@ -14,16 +15,19 @@ export const useAuthStore = create<AuthState>()(
   persist(
     (set, get) => ({
       token: null,
+      refreshToken: null,
       user: null,
 
-      setAuth: (token: string, user: User) => {
+      setAuth: (token: string, refreshToken: string, user: User) => {
         localStorage.setItem('token', token);
-        set({ token, user });
+        localStorage.setItem('refresh_token', refreshToken);
+        set({ token, refreshToken, user });
       },
 
       clearAuth: () => {
         localStorage.removeItem('token');
-        set({ token: null, user: null });
+        localStorage.removeItem('refresh_token');
+        set({ token: null, refreshToken: null, user: null });
       },
 
       isAuthenticated: () => {
@@ -32,7 +36,11 @@ export const useAuthStore = create<AuthState>()(
     }),
     {
       name: 'auth-storage',
-      partialize: (state) => ({ token: state.token, user: state.user }),
+      partialize: (state) => ({
+        token: state.token,
+        refreshToken: state.refreshToken,
+        user: state.user,
+      }),
     }
   )
 );
@@ -15,6 +15,8 @@ export interface LoginRequest {
 
 export interface LoginResponse {
   token: string;
+  refresh_token: string;
+  expires_in: number;
   user: User;
 }
 
@@ -56,6 +58,132 @@ export interface LLMProvider {
   last_tested_at?: string;
   last_test_status?: string;
   last_test_message?: string;
+  rpm_limit?: number; // Requests per minute (0 = unlimited)
+  tpm_limit?: number; // Tokens per minute (0 = unlimited)
+  throttled?: boolean;
+  throttled_until?: string;
+  created_at?: string;
+  updated_at?: string;
+}
+
+// Model pricing types
+export interface ModelPrice {
+  id: number;
+  project_id: number;
+  llm_provider_id: number;
+  llm_provider?: LLMProvider;
+  model_name: string;
+  prompt_price: number; // Per million prompt tokens
+  completion_price: number; // Per million completion tokens
+  currency: string;
+  effective_from: string;
+  effective_to?: string | null; // null = open-ended
+  created_at?: string;
+  updated_at?: string;
+}
+
+// Developer feedback on findings; precision = findings that were real issues / findings with feedback
+export interface PrecisionRow {
+  key: string; // Category, model name or prompt version
+  accepted: number;
+  fixed: number;
+  wont_fix: number;
+  false_positive: number;
+  total: number;
+  precision: number; // Percent
+}
+
+export interface PrecisionStats {
+  by_category: PrecisionRow[];
+  by_model: PrecisionRow[];
+  by_prompt_version: PrecisionRow[];
+}
+
+// Cost rows are per currency; currency is empty for unpriced models
+export interface CostBreakdown {
+  by_currency: Array<{
+    currency: string;
+    total_tokens: number;
+    cost: number;
+  }>;
+  by_model: Array<{
+    llm_provider_id: number;
+    model_name: string;
+    currency: string;
+    prompt_tokens: number;
+    completion_tokens: number;
+    total_tokens: number;
+    cost: number;
+  }>;
+  by_repository: Array<{
+    repository_id: number;
+    repository_name: string;
+    currency: string;
+    total_tokens: number;
+    cost: number;
+  }>;
+  by_day: Array<{
+    date: string;
+    currency: string;
+    total_tokens: number;
+    cost: number;
+  }>;
+}
+
+// Budget types
+export type BudgetMetric = "tokens" | "cost";
+
+export interface Budget {
+  id: number;
+  project_id: number;
+  repository_id?: number | null; // null = whole project
+  repository?: Repository;
+  metric: BudgetMetric;
+  soft_limit: number; // 0 = no warning
+  hard_limit: number; // 0 = no limit
+  currency?: string;
+  is_active: boolean;
+  created_at?: string;
+  updated_at?: string;
+}
+
+export interface BudgetStatus extends Budget {
+  used: number;
+  percent: number;
+  state: "ok" | "warning" | "exceeded";
+  period_start: string;
+  period_end: string;
+}
+
+// Reviewer profile (specialized review pass run in parallel with the others)
+export type ReviewCategory =
+  | "security"
+  | "performance"
+  | "correctness"
+  | "quality"
+  | "style";
+
+export interface ReviewerProfile {
+  id: number;
+  project_id: number;
+  name: string;
+  category: ReviewCategory; // Set on every suggestion of the pass
+  prompt_template?: string; // Empty = project prompt focused on the category
+  llm_provider_id?: number | null; // null = the repository's provider
+  llm_provider?: LLMProvider;
+  is_active: boolean;
+  created_at?: string;
+  updated_at?: string;
+}
+
+// Secret redaction rule (project-specific, on top of the builtin rules)
+export interface RedactionRule {
+  id: number;
+  project_id: number;
+  name: string; // Shown in placeholders, e.g. [REDACTED:name:1]
+  pattern: string; // Go regexp; the first capture group is redacted if any
+  description?: string;
+  is_active: boolean;
   created_at?: string;
   updated_at?: string;
 }
@@ -88,12 +216,70 @@ export interface Repository {
   last_webhook_test_status?: "success" | "failed" | "";
   last_webhook_test_error?: string;
 
+  // Review trigger rules (null = defaults: skip drafts)
+  trigger_rules?: ReviewTriggerRules | null;
+  response_cache_disabled?: boolean; // Always call the LLM, bypassing the response cache
+
+  // Consensus mode: a second provider verifies high and critical findings
+  verifier_llm_provider_id?: number | null; // null = off
+  rejected_finding_action?: "drop" | "downgrade";
+  verifier_llm_provider?: LLMProvider;
+
+  // Quality gate: commit status on the MR head (null = off)
+  quality_gate?: QualityGate | null;
+
+  // MR labels kept in sync with each review's findings
+  label_rules?: LabelRule[] | null;
+
+  // Generated summary of new merge requests ("" = off)
+  mr_summary_mode?: "" | "comment" | "description";
+
+  // Human reviewer recommendation from CODEOWNERS and path history ("" = off)
+  reviewer_mode?: "" | "suggest" | "assign";
+  max_reviewers?: number;
+
+  // Static analyzers run before the LLM review ([] = off)
+  analyzers?: AnalyzerName[] | null;
+
   created_at?: string;
   updated_at?: string;
   platform?: GitPlatformConfig;
   llm_provider?: LLMProvider;
 }
 
+export interface ReviewTriggerRules {
+  skip_drafts: boolean;
+  include_labels: string[];
+  exclude_labels: string[];
+  target_branches: string[];
+  excluded_authors: string[];
+  max_changed_lines: number;
+}
+
+export interface QualityGate {
+  enabled: boolean;
+  min_score: number; // 0 = no minimum
+  no_critical: boolean;
+  no_security_issues: boolean;
+}
+
+export interface LabelRule {
+  label: string;
+  metric:
+    | "score"
+    | "changed_lines"
+    | "issues"
+    | "critical_issues"
+    | "high_issues"
+    | "security_issues"
+    | "performance_issues";
+  min?: number | null; // Inclusive lower bound (null = none)
+  max?: number | null; // Inclusive upper bound (null = none)
+}
+
+// Static analyzers a repository can run before the LLM review
+export type AnalyzerName = "go_vet" | "golangci_lint" | "semgrep";
+
 export interface GitLabRepository {
   id: number;
   name: string;
@@ -2,6 +2,7 @@ import {
   CheckCircleOutlined,
   CloseCircleOutlined,
   ClockCircleOutlined,
+  MinusCircleOutlined,
 } from "@ant-design/icons";
 import { Tag } from "antd";
 import React from "react";
@@ -11,7 +12,14 @@ import React from "react";
  * Eliminates code duplication across List, Detail, and Dashboard components
  */
 
-export type ReviewStatus = "completed" | "failed" | "processing" | "pending";
+export type ReviewStatus =
+  | "completed"
+  | "failed"
+  | "processing"
+  | "pending"
+  | "skipped"
+  | "superseded"
+  | "budget_exceeded";
 
 export interface StatusConfig {
   color: string;
@@ -45,6 +53,24 @@ export const STATUS_CONFIG: Record<ReviewStatus, StatusConfig> = {
     text: "Pending",
     tagColor: "default",
   },
+  skipped: {
+    color: "#8c8c8c",
+    icon: <MinusCircleOutlined style={{ color: "#8c8c8c" }} />,
+    text: "Skipped",
+    tagColor: "default",
+  },
+  superseded: {
+    color: "#8c8c8c",
+    icon: <MinusCircleOutlined style={{ color: "#8c8c8c" }} />,
+    text: "Superseded",
+    tagColor: "default",
+  },
+  budget_exceeded: {
+    color: "#fa8c16",
+    icon: <MinusCircleOutlined style={{ color: "#fa8c16" }} />,
+    text: "Budget Exceeded",
+    tagColor: "orange",
+  },
 };
 
 /**